@@ -2,6 +2,13 @@
 import { useState } from 'react';
 import api from '../services/api';
 
+// Локаль ввода чисел, вывода результатов и сообщений об ошибках
+const LOCALE = 'ru';
+
+// Значения имён скрипта «r = 3; area = pi * r ^ 2» перед ответом
+const named = (bindings = []) =>
+  bindings.map(b => `${b.name} = ${b.ready ? b.value : '…'}; `).join('');
+
 export default function Calculator() {
   const [expr, setExpr] = useState('');
   const [result, setResult] = useState('');
@@ -29,7 +36,8 @@ export default function Calculator() {
     setResult('📤 Отправка выражения...'); // ✅ Показываем сразу
 
     try {
-      const res = await api.post('/v1/calculate', { expression: expr });
+      // ru: 3,14 и 1 000, аргументы функций через «;»
+      const res = await api.post('/v1/calculate', { expression: expr, locale: LOCALE });
       const taskId = res.data.taskId;
 
       setResult('⏳ Задача отправлена, ждём результат...'); // ✅ Показываем после получения taskId
@@ -39,9 +47,14 @@ export default function Calculator() {
       const interval = setInterval(async () => {
         attempts++;
         try {
-          const res2 = await api.post('/v1/result', { task_id: taskId });
+          const res2 = await api.post('/v1/result', { task_id: taskId, format: { locale: LOCALE } });
+          // formatted — результат, отформатированный сервером
           if (res2.data.hasOwnProperty('value')) {
-            setResult(`= ${res2.data.value}`);
+            setResult(`${named(res2.data.bindings)}= ${res2.data.formatted ?? res2.data.value}`);
+            clearInterval(interval);
+            setLoading(false);
+          } else if (res2.data.hasOwnProperty('text')) {
+            setResult(`${named(res2.data.bindings)}= ${res2.data.formatted ?? res2.data.text}`); // списки и матрицы
             clearInterval(interval);
             setLoading(false);
           } else if (res2.data.hasOwnProperty('error')) {
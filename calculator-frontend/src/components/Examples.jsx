@@ -5,6 +5,7 @@ import api from '../services/api';
 export default function Examples() {
   const [examples, setExamples] = useState([]);
   const [loading, setLoading] = useState(true);
+  const [explanations, setExplanations] = useState({}); // id → шаги решения
 
   useEffect(() => {
     const fetchExamples = async () => {
@@ -20,6 +21,20 @@ export default function Examples() {
     fetchExamples();
   }, []);
 
+  // Пошаговое решение: повторное нажатие скрывает его
+  const toggleExplain = async (id) => {
+    if (explanations[id]) {
+      setExplanations(({ [id]: _, ...rest }) => rest);
+      return;
+    }
+    try {
+      const res = await api.post('/v1/explain', { taskId: id });
+      setExplanations((prev) => ({ ...prev, [id]: res.data.steps || [] }));
+    } catch (err) {
+      console.error('Ошибка загрузки решения', err);
+    }
+  };
+
   // Форматируем дату: 27.07.2025, 20:18
   const formatDate = (isoString) => {
     try {
@@ -95,7 +110,7 @@ export default function Examples() {
                 }}>
                   {ex.calculated === false ? (
                     '⏳' // В процессе
-                  ) : ex.result !== undefined ? (
+                  ) : ex.result !== undefined || ex.resultText !== undefined ? (
                     '✅' // Успешно
                   ) : (
                     '❌' // Ошибка
@@ -119,7 +134,9 @@ export default function Examples() {
                   {ex.calculated === false ? (
                     <em>Ожидает вычисления...</em>
                   ) : ex.result !== undefined ? (
-                    <strong style={{ color: '#bb86fc' }}>Результат: {ex.result}</strong>
+                    <strong style={{ color: '#bb86fc' }}>Результат: {ex.formatted ?? ex.result}</strong>
+                  ) : ex.resultText !== undefined ? (
+                    <strong style={{ color: '#bb86fc' }}>Результат: {ex.formatted ?? ex.resultText}</strong>
                   ) : (
                     <span style={{ color: '#cf6679' }}>
                       Ошибка: {getErrorMessage(ex.error)}
@@ -127,6 +144,28 @@ export default function Examples() {
                   )}
                 </div>
 
+                {/* Решение по шагам */}
+                {ex.calculated && ex.error === undefined && (
+                  <div style={{ marginBottom: '8px' }}>
+                    <button
+                      onClick={() => toggleExplain(ex.id)}
+                      style={{ background: 'none', border: 'none', color: '#bb86fc', cursor: 'pointer', padding: 0 }}
+                    >
+                      {explanations[ex.id] ? 'Скрыть решение' : 'Показать решение'}
+                    </button>
+                    {explanations[ex.id] && (
+                      <ol style={{ margin: '8px 0 0', paddingLeft: '20px', color: '#ccc', fontSize: '14px' }}>
+                        {explanations[ex.id].map((step) => (
+                          <li key={step.number}>
+                            <code>{step.operation} = {step.result}</code>
+                            <span style={{ color: '#777' }}> — {step.expression}</span>
+                          </li>
+                        ))}
+                      </ol>
+                    )}
+                  </div>
+                )}
+
                 {/* Дата */}
                 <small style={{ color: '#777' }}>
                   {formatDate(ex.createdAt)}
@@ -8,9 +8,16 @@ import (
 	"syscall"
 
 	"github.com/tainj/distributed_calculator2/internal/auth"
+	"github.com/tainj/distributed_calculator2/internal/dependency"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/internal/operations"
+	"github.com/tainj/distributed_calculator2/internal/orchestrator"
+	"github.com/tainj/distributed_calculator2/internal/rates"
 	repo "github.com/tainj/distributed_calculator2/internal/repository"
 	service "github.com/tainj/distributed_calculator2/internal/service"
 	"github.com/tainj/distributed_calculator2/internal/transport/grpc"
+	"github.com/tainj/distributed_calculator2/internal/valueprovider"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/config"
 	"github.com/tainj/distributed_calculator2/pkg/db/cache"
 	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
@@ -71,23 +78,61 @@ func main() {
 		mainLogger.Error(ctx, "failed to init kafka: "+err.Error())
 		os.Exit(1)
 	}
+	results, err := kafka.NewResultQueue(cfg.Kafka, kafkaLogger) // completion events of workers
+	if err != nil {
+		mainLogger.Error(ctx, "failed to init kafka: "+err.Error())
+		os.Exit(1)
+	}
+	deadLetters, err := kafka.NewDeadLetterQueue(cfg.Kafka, kafkaLogger) // tasks workers gave up on
+	if err != nil {
+		mainLogger.Error(ctx, "failed to init kafka: "+err.Error())
+		os.Exit(1)
+	}
 
 	// 6. ValueProvider - for retrieving variables from redis
-	// valueProvider := valueprovider.NewRedisValueProvider(redis)
+	valueProvider := valueprovider.NewRedisValueProvider(redis)
 
 	// 7. Repositories
-	// variableRepo := factory.CreateVariableRepository() // for saving results
 	exampleRepo := factory.CreateExampleRepository() // for saving expressions
 	userRepo := factory.CreateUserRepository()
+	userVariableRepo := factory.CreateUserVariableRepository() // saved variables of users
+	userFunctionRepo := factory.CreateUserFunctionRepository() // functions defined by users
+	deadLetterRepo := factory.CreateDeadLetterRepository()     // tasks workers gave up on
+	resultRepo := factory.CreateVariableRepository()           // results of tasks in Redis
+
+	// cancelled examples, workers skip their tasks while an example may still run
+	cancellationRepo := factory.CreateCancellationRepository(cfg.Deadline.Longest())
+
+	// 8. Currency rates - money expressions fail until they are loaded
+	rateProvider := rates.NewFileProvider(cfg.Rates)
+	if _, err := rateProvider.Refresh(ctx); err != nil {
+		mainLogger.Warn(ctx, "failed to load currency rates", "file", cfg.Rates.FilePath, "error", err)
+	}
 
-	// 8. Calculator service
-	srv := service.NewCalculatorService(userRepo, exampleRepo, jwtService, kafkaQueue, mainLogger)
-
-	// 9. Worker - processes tasks from kafka
-	// worker := worker.NewWorker(exampleRepo, variableRepo, kafkaQueue, valueProvider, workerLogger)
-	// go worker.Start() // in separate goroutine
-
-	// 10. gRPC server (gRPC + REST via gateway)
+	// 9. Calculator service
+	limits := map[models.Role]calculator.Limits{
+		models.UserRole:  cfg.Limits.User,
+		models.AdminRole: cfg.Limits.Admin,
+	}
+	taskOrchestrator := orchestrator.NewOrchestrator(factory.CreatePendingTaskRepository(), kafkaQueue, mainLogger) // tasks in order of dependencies
+	releaser := dependency.NewReleaser(exampleRepo, taskOrchestrator, valueProvider, mainLogger)                    // examples using results of others
+	registry := operations.NewRegistry(ctx, factory.CreateWasmModuleRepository(), cfg.Wasm, mainLogger)             // custom operations
+	defer registry.Close(ctx)
+	srv := service.NewCalculatorService(userRepo, exampleRepo, userVariableRepo, userFunctionRepo, deadLetterRepo, cancellationRepo, resultRepo, jwtService, taskOrchestrator, rateProvider, limits, cfg.Deadline, valueProvider, releaser, registry, mainLogger)
+
+	// 10. Results of workers - steps, progress and final results are saved here
+	consumerCtx, stopConsumer := context.WithCancel(ctx)
+	defer stopConsumer()
+	consumer := orchestrator.NewConsumer(results, deadLetters, exampleRepo, cancellationRepo, resultRepo, taskOrchestrator, releaser, cfg.Retry, mainLogger.With("component", "ResultConsumer"))
+	go consumer.Run(consumerCtx)
+	letters := orchestrator.NewDeadLetterConsumer(deadLetters, deadLetterRepo, exampleRepo, releaser, cfg.Retry, mainLogger.With("component", "DeadLetterConsumer"))
+	go letters.Run(consumerCtx)
+	sweeper := orchestrator.NewDeadlineSweeper(exampleRepo, taskOrchestrator, releaser, cfg.Deadline, mainLogger.With("component", "DeadlineSweeper"))
+	go sweeper.Run(consumerCtx) // examples not calculated by their deadline fail
+	reaper := orchestrator.NewReaper(exampleRepo, resultRepo, factory.CreateLockRepository(), taskOrchestrator, releaser, cfg.Reaper, mainLogger.With("component", "Reaper"))
+	go reaper.Run(consumerCtx) // lost tasks are sent again, by one server at a time
+
+	// 11. gRPC server (gRPC + REST via gateway)
 	grpcServer, err := grpc.New(ctx, cfg.Grpc.GRPCPort, cfg.Grpc.RestPort, srv, jwtService)
 	if err != nil {
 		mainLogger.Error(ctx, err.Error())
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/tainj/distributed_calculator2/internal/operations"
 	repo "github.com/tainj/distributed_calculator2/internal/repository"
 	"github.com/tainj/distributed_calculator2/internal/valueprovider"
 	"github.com/tainj/distributed_calculator2/internal/worker"
@@ -43,7 +44,6 @@ func main() {
 	}
 
 	factory := repo.NewRepositoryFactory(db, redis, mainLogger)
-	exampleRepo := factory.CreateExampleRepository()
 	variableRepo := factory.CreateVariableRepository()
 
 	kafkaLogger := mainLogger.With("component", "KafkaConsumer")
@@ -52,10 +52,22 @@ func main() {
 		mainLogger.Error(ctx, "failed to init kafka", "error", err)
 		os.Exit(1)
 	}
+	results, err := kafka.NewResultWriter(cfg.Kafka, kafkaLogger) // completion events for the server, read only by it
+	if err != nil {
+		mainLogger.Error(ctx, "failed to init kafka", "error", err)
+		os.Exit(1)
+	}
+	deadLetters, err := kafka.NewDeadLetterWriter(cfg.Kafka, kafkaLogger) // tasks that failed all attempts, read only by the server
+	if err != nil {
+		mainLogger.Error(ctx, "failed to init kafka", "error", err)
+		os.Exit(1)
+	}
 
 	valueProvider := valueprovider.NewRedisValueProvider(redis)
 
-	w := worker.NewWorker(exampleRepo, variableRepo, kafkaQueue, valueProvider, workerLogger, port)
+	registry := operations.NewRegistry(ctx, factory.CreateWasmModuleRepository(), cfg.Wasm, workerLogger) // custom operations in the sandbox
+	defer registry.Close(ctx)
+	w := worker.NewWorker(variableRepo, factory.CreateCancellationRepository(cfg.Deadline.Longest()), kafkaQueue, results, deadLetters, valueProvider, registry, cfg.Retry, cfg.Latency, workerLogger, port)
 
 	// Run
 	go w.Start()
@@ -0,0 +1,107 @@
+package dependency
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/internal/orchestrator"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/internal/valueprovider"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// Releaser sends the held tasks of examples that use results of other
+// examples (ans, @last, result("id")) once those are calculated.
+// Workers call Finished for every example they finish; the service calls
+// Release right after saving a held example, in case its dependencies
+// finished in between. Held tasks are taken once, so both may try.
+type Releaser struct {
+	examples     repo.ExampleRepository
+	orchestrator *orchestrator.Orchestrator
+	values       valueprovider.Provider
+	logger       logger.Logger
+}
+
+func NewReleaser(examples repo.ExampleRepository, orchestrator *orchestrator.Orchestrator, values valueprovider.Provider, logger logger.Logger) *Releaser {
+	return &Releaser{examples: examples, orchestrator: orchestrator, values: values, logger: logger}
+}
+
+// Finished - the example got its result or an error: examples waiting
+// for it are released or fail too
+func (r *Releaser) Finished(ctx context.Context, exampleID string) error {
+	dependents, err := r.examples.GetDependents(ctx, exampleID)
+	if err != nil {
+		return fmt.Errorf("dependents of %s: %w", exampleID, err)
+	}
+	for i := range dependents {
+		if err := r.Release(ctx, &dependents[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release - sends the held tasks of the example if all examples it
+// depends on are calculated; fails it if one of them failed
+func (r *Releaser) Release(ctx context.Context, example *models.Example) error {
+	for _, id := range example.DependsOn {
+		dependency, err := r.examples.GetExampleByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("release %s: %w", example.ID, err)
+		}
+		if dependency.Error != nil {
+			return r.fail(ctx, example.ID, fmt.Errorf("%w: example %s failed: %s", calculator.ErrUnknownReference, id, *dependency.Error))
+		}
+		if !dependency.Calculated {
+			return nil // still waiting
+		}
+	}
+
+	tasks, held, err := r.examples.TakeHeldTasks(ctx, example.ID)
+	if err != nil {
+		return fmt.Errorf("release %s: %w", example.ID, err)
+	}
+	if !held {
+		return nil // released by someone else
+	}
+
+	// the answer is the result of another example as it is: ans
+	if len(tasks) == 0 {
+		value, err := r.values.Resolve(ctx, example.Response)
+		if err != nil {
+			return fmt.Errorf("release %s: %w", example.ID, err)
+		}
+		if err := r.examples.UpdateExample(ctx, example.ID, value); err != nil {
+			return fmt.Errorf("release %s: %w", example.ID, err)
+		}
+		return r.Finished(ctx, example.ID)
+	}
+
+	if err := r.orchestrator.Dispatch(ctx, tasks); err != nil {
+		return fmt.Errorf("release %s: %w", example.ID, err)
+	}
+	r.logger.Debug(ctx, "held tasks released", "example_id", example.ID, "tasks", len(tasks))
+	return nil
+}
+
+// fail - the example gets the error of its dependency instead of a result
+func (r *Releaser) fail(ctx context.Context, exampleID string, cause error) error {
+	_, held, err := r.examples.TakeHeldTasks(ctx, exampleID)
+	if err != nil {
+		return fmt.Errorf("fail %s: %w", exampleID, err)
+	}
+	if !held {
+		return nil
+	}
+	failed, err := r.examples.UpdateExampleWithError(ctx, exampleID, cause.Error())
+	if err != nil {
+		return fmt.Errorf("fail %s: %w", exampleID, err)
+	}
+	if !failed {
+		return nil // cancelled or expired while it was held
+	}
+	r.logger.Debug(ctx, "held example failed", "example_id", exampleID, "error", cause)
+	return r.Finished(ctx, exampleID)
+}
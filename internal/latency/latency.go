@@ -0,0 +1,153 @@
+package latency
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config - simulated time of operations, applied by workers before they
+// calculate a task. Makes the distribution of tasks between workers visible
+// in demos and load tests; nothing is delayed by default.
+type Config struct {
+	Addition       Delay `env:"TIME_ADDITION_MS"`
+	Subtraction    Delay `env:"TIME_SUBTRACTION_MS"` // unary minus too: ~x is 0 - x
+	Multiplication Delay `env:"TIME_MULTIPLICATIONS_MS"`
+	Division       Delay `env:"TIME_DIVISIONS_MS"`
+	Power          Delay `env:"TIME_POWER_MS"`
+	Function       Delay `env:"TIME_FUNCTIONS_MS"` // functions, "in" and custom operations
+}
+
+// For - the delay of the operation of a task
+func (c Config) For(sign string) Delay {
+	switch sign {
+	case "+":
+		return c.Addition
+	case "-":
+		return c.Subtraction
+	case "*":
+		return c.Multiplication
+	case "/":
+		return c.Division
+	case "^":
+		return c.Power
+	}
+	return c.Function
+}
+
+// Wait - sleeps the delay of the operation, the time slept is returned.
+// It returns early with the error of ctx.
+func (c Config) Wait(ctx context.Context, sign string) (time.Duration, error) {
+	delay := c.For(sign).Sample()
+	if delay <= 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
+
+// Delay - time of an operation in milliseconds:
+//
+//	100            always 100
+//	50-150         uniformly from 50 to 150
+//	normal:100,20  normally with mean 100 and standard deviation 20
+//	exp:100        exponentially with mean 100
+//
+// Negative samples are 0. The zero Delay is no delay.
+type Delay struct {
+	kind string // fixed, uniform, normal or exp
+	a, b float64
+}
+
+func ParseDelay(text string) (Delay, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Delay{}, nil
+	}
+
+	kind, params, ok := strings.Cut(text, ":")
+	if !ok {
+		if low, high, ok := strings.Cut(text, "-"); ok {
+			return delayOf("uniform", text, low, high)
+		}
+		return delayOf("fixed", text, text)
+	}
+	switch kind {
+	case "normal":
+		mean, deviation, ok := strings.Cut(params, ",")
+		if !ok {
+			return Delay{}, fmt.Errorf("delay %q: normal takes a mean and a standard deviation", text)
+		}
+		return delayOf(kind, text, mean, deviation)
+	case "exp":
+		return delayOf(kind, text, params)
+	}
+	return Delay{}, fmt.Errorf("delay %q: unknown distribution %q, expected normal or exp", text, kind)
+}
+
+// delayOf - the delay with parameters in milliseconds
+func delayOf(kind, text string, params ...string) (Delay, error) {
+	values := make([]float64, 2)
+	for i, param := range params {
+		value, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+		if err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+			return Delay{}, fmt.Errorf("delay %q: %q is not a number of milliseconds", text, param)
+		}
+		values[i] = value
+	}
+	if kind == "uniform" && values[0] > values[1] {
+		return Delay{}, fmt.Errorf("delay %q: the range is reversed", text)
+	}
+	return Delay{kind: kind, a: values[0], b: values[1]}, nil
+}
+
+// UnmarshalText - for environment variables
+func (d *Delay) UnmarshalText(text []byte) error {
+	delay, err := ParseDelay(string(text))
+	if err != nil {
+		return err
+	}
+	*d = delay
+	return nil
+}
+
+func (d Delay) String() string {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	switch d.kind {
+	case "fixed":
+		return format(d.a)
+	case "uniform":
+		return format(d.a) + "-" + format(d.b)
+	case "normal":
+		return "normal:" + format(d.a) + "," + format(d.b)
+	case "exp":
+		return "exp:" + format(d.a)
+	}
+	return "0"
+}
+
+// Sample - a random time of the operation
+func (d Delay) Sample() time.Duration {
+	var ms float64
+	switch d.kind {
+	case "fixed":
+		ms = d.a
+	case "uniform":
+		ms = d.a + rand.Float64()*(d.b-d.a)
+	case "normal":
+		ms = d.a + rand.NormFloat64()*d.b
+	case "exp":
+		ms = rand.ExpFloat64() * d.a
+	}
+	return time.Duration(max(ms, 0) * float64(time.Millisecond))
+}
@@ -0,0 +1,123 @@
+package latency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"empty", "", "0"},
+		{"spaces", "  ", "0"},
+		{"fixed", "100", "100"},
+		{"fixed fraction", "2.5", "2.5"},
+		{"fixed zero", "0", "0"},
+		{"uniform", "50-150", "50-150"},
+		{"uniform with spaces", " 50 - 150 ", "50-150"},
+		{"uniform of one value", "70-70", "70-70"},
+		{"normal", "normal:100,20", "normal:100,20"},
+		{"normal with spaces", "normal: 100 , 20", "normal:100,20"},
+		{"exp", "exp:100", "exp:100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, err := ParseDelay(tt.text)
+			if err != nil {
+				t.Fatalf("ParseDelay(%q) error: %v", tt.text, err)
+			}
+			if got := delay.String(); got != tt.expected {
+				t.Errorf("ParseDelay(%q) = %q, expected %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDelayErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"not a number", "fast"},
+		{"negative", "-5"},
+		{"infinite", "Inf"},
+		{"not a number NaN", "NaN"},
+		{"reversed range", "150-50"},
+		{"open range", "50-"},
+		{"normal without deviation", "normal:100"},
+		{"normal with a bad deviation", "normal:100,x"},
+		{"exp without mean", "exp:"},
+		{"unknown distribution", "poisson:3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if delay, err := ParseDelay(tt.text); err == nil {
+				t.Errorf("ParseDelay(%q) = %q, expected an error", tt.text, delay)
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		low  time.Duration
+		high time.Duration // -1 - no upper bound
+	}{
+		{"no delay", "", 0, 0},
+		{"fixed", "100", 100 * time.Millisecond, 100 * time.Millisecond},
+		{"uniform", "50-150", 50 * time.Millisecond, 150 * time.Millisecond},
+		{"uniform of one value", "70-70", 70 * time.Millisecond, 70 * time.Millisecond},
+		{"normal is never negative", "normal:1,100", 0, -1},
+		{"exp is never negative", "exp:10", 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, err := ParseDelay(tt.text)
+			if err != nil {
+				t.Fatalf("ParseDelay(%q) error: %v", tt.text, err)
+			}
+			for range 1000 {
+				got := delay.Sample()
+				if got < tt.low || (tt.high >= 0 && got > tt.high) {
+					t.Fatalf("Sample() of %q = %v, expected from %v to %v", tt.text, got, tt.low, tt.high)
+				}
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	cfg := Config{Addition: mustParse(t, "1"), Multiplication: mustParse(t, "60000")}
+
+	if slept, err := cfg.Wait(context.Background(), "-"); slept != 0 || err != nil {
+		t.Errorf("Wait() without a delay = %v, %v, expected 0, nil", slept, err)
+	}
+	if slept, err := cfg.Wait(context.Background(), "+"); slept != time.Millisecond || err != nil {
+		t.Errorf("Wait() = %v, %v, expected %v, nil", slept, err, time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if slept, err := cfg.Wait(ctx, "*"); slept != 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() with a cancelled context = %v, %v, expected 0, %v", slept, err, context.Canceled)
+	}
+}
+
+func mustParse(t *testing.T, text string) Delay {
+	t.Helper()
+	delay, err := ParseDelay(text)
+	if err != nil {
+		t.Fatalf("ParseDelay(%q) error: %v", text, err)
+	}
+	return delay
+}
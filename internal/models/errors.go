@@ -6,4 +6,14 @@ var (
 	ErrDivisionByZero       = errors.New("division by zero")
 	ErrNonExistingOperation = errors.New("operation does not exist or not implemented")
 	ErrCovertExample        = errors.New("line is not a mathematical expression or contains an error")
+	ErrForbidden            = errors.New("forbidden: admin role required")
+	ErrInvalidVariable      = errors.New("invalid variable") // the name can't be used or the value is not a number
+	ErrInvalidFunction      = errors.New("invalid function") // the definition can't be parsed or calls unknown functions
+	ErrInvalidModule        = errors.New("invalid module")   // not WebAssembly, imports something or exports no operations
+	ErrTaskFailed           = errors.New("task failed")      // workers gave up on a task, see DeadLetter
+	ErrNotReplayable        = errors.New("dead letter can't be replayed")
+	ErrCancelled            = errors.New("example cancelled")     // by its user, workers skip its tasks
+	ErrTimeout              = errors.New("calculation timed out") // not calculated by its deadline
+	ErrInvalidDeadline      = errors.New("invalid deadline")      // not a time, in the past or too far
+	ErrStuck                = errors.New("example stuck")         // its tasks were lost and can't be sent again
 )
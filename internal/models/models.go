@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Role string
 
@@ -10,13 +13,56 @@ const (
 )
 
 type Task struct {
-	Num1      string `json:"num1"`
-	Num2      string `json:"num2"`
-	Sign      string `json:"sign"`
-	Variable  string `json:"variable"`
-	ExampleID string `json:"example_id"`
-	Index     int    `json:"index"`
-	IsFinal   bool   `json:"is_final"`
+	Num1      string             `json:"num1"`
+	Num2      string             `json:"num2"`
+	Sign      string             `json:"sign"`
+	Args      []string           `json:"args,omitempty"`  // operands of functions: mean, list, ...
+	Rates     map[string]float64 `json:"rates,omitempty"` // currency rates for "in" on money
+	Seed      int64              `json:"seed,omitempty"`  // seed of the example, see calculator.TaskSeed
+	IEEE      bool               `json:"ieee,omitempty"`  // keep ±Inf and NaN instead of errors
+	Variable  string             `json:"variable"`
+	ExampleID string             `json:"example_id"`
+	Index     int                `json:"index"`
+	IsFinal   bool               `json:"is_final"`
+	Deadline  *time.Time         `json:"deadline,omitempty"` // of the example, workers drop the task after it
+}
+
+// TaskResult - the completion event of a task: workers send it to
+// calculator_results, the server saves the step and advances the example.
+// V is calculator.Value, the calculator itself uses models.
+type TaskResult[V any] struct {
+	Task     Task          `json:"task"`
+	Args     []V           `json:"args,omitempty"`  // operands as the worker resolved them
+	Value    *V            `json:"value,omitempty"` // nil if the task failed
+	Error    string        `json:"error,omitempty"` // division by zero, bad arguments, ...
+	Duration time.Duration `json:"duration"`        // of the calculation, in nanoseconds
+	WorkerID string        `json:"worker_id"`
+}
+
+// DeadLetter - a task that failed all its attempts or a message that is
+// not a task. Workers send them to calculator_dead_letters, the server
+// saves them for admins to look at and replay.
+type DeadLetter struct {
+	ID         string     `json:"id" db:"id"`
+	ExampleID  string     `json:"example_id,omitempty" db:"example_id"` // empty if the message is not a task
+	Variable   string     `json:"variable,omitempty" db:"variable"`
+	Payload    string     `json:"payload" db:"payload"` // the message as it was read
+	Reason     string     `json:"reason" db:"reason"`   // the last error
+	Attempts   int        `json:"attempts" db:"attempts"`
+	WorkerID   string     `json:"worker_id" db:"worker_id"`
+	FailedAt   time.Time  `json:"failed_at" db:"failed_at"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty" db:"replayed_at"`
+}
+
+// ExampleError - the error saved to the example of the task
+func (d DeadLetter) ExampleError() string {
+	return fmt.Sprintf("%s: %s after %d attempts: %s", ErrTaskFailed, d.Variable, d.Attempts, d.Reason)
+}
+
+// PendingTask - a task waiting for results of other tasks of its example
+type PendingTask struct {
+	Task       *Task
+	WaitingFor []string // variables of the tasks it needs
 }
 
 type Example struct {
@@ -25,21 +71,134 @@ type Example struct {
 	Response       string    `json:"response" db:"response"`
 	Calculated     bool      `json:"calculated" db:"calculated"`
 	Result         *float64  `json:"result,omitempty" db:"result"`
+	ResultText     *string   `json:"result_text,omitempty" db:"result_value"` // results that are not numbers: [[1, 2], [3, 4]]
+	ResultKind     *string   `json:"result_kind,omitempty" db:"-"`            // kind of ResultText: matrix, time, duration, ...
 	Error          *string   `json:"error,omitempty" db:"error"`
 	UserID         string    `json:"user_id" db:"user_id"`
 	SimpleExamples []*Task   `json:"simple_examples"` // for logic
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	Seed           *int64    `json:"seed,omitempty" db:"seed"`                     // random functions draw from it
+	RatesSnapshot  *string   `json:"rates_snapshot,omitempty" db:"rates_snapshot"` // currency rates used for money
+	IEEE           bool      `json:"ieee" db:"ieee"`                               // results may be ±Inf and NaN
+	Locale         string    `json:"locale,omitempty" db:"locale"`                 // separators of the input, the expression is saved without them
+	Syntax         string    `json:"syntax,omitempty" db:"syntax"`                 // infix, rpn or sexpr
+	Bindings       []Binding `json:"bindings,omitempty" db:"bindings"`             // names assigned by a script
+	TotalTasks     int       `json:"total_tasks" db:"total_tasks"`                 // tasks sent to workers
+	DoneTasks      int       `json:"done_tasks" db:"done_tasks"`                   // tasks calculated so far
+	Cancelled      bool      `json:"cancelled" db:"cancelled"`                     // by the user, Error is ErrCancelled
+
+	// saved variables of the user used by the expression, as they were
+	// when it was sent
+	UserVariables map[string]float64 `json:"user_variables,omitempty" db:"user_variables"`
+
+	// functions of the user inlined into the expression, as they were
+	// defined when it was sent
+	UserFunctions []UserFunction `json:"user_functions,omitempty" db:"user_functions"`
+
+	// earlier examples used by the expression: ans, @last, result("id");
+	// tasks are held until the examples in DependsOn are calculated
+	References []Reference `json:"references,omitempty" db:"refs"`
+	DependsOn  []string    `json:"depends_on,omitempty" db:"depends_on"`
+	HeldTasks  []*Task     `json:"-" db:"held_tasks"` // nil if the tasks are sent
+
+	// tasks sent to workers; the reaper sends lost ones again, Redispatches
+	// counts the times it looked at the example
+	Tasks        []*Task `json:"-" db:"tasks"`
+	Redispatches int     `json:"redispatches" db:"redispatches"`
+
+	// the example fails with ErrTimeout if it is not calculated by then,
+	// sweeps themselves have no deadline
+	Deadline *time.Time `json:"deadline,omitempty" db:"deadline"`
+
+	// sweep: parent example and position of the point in the grid
+	ParentID  *string            `json:"parent_id,omitempty" db:"parent_id"`
+	Position  int                `json:"position" db:"position"`
+	Variables map[string]float64 `json:"variables,omitempty" db:"variables"`
+}
+
+// Binding - a name assigned in a script and the ref of its value:
+// a variable of a task (result:<ref> in Redis) or a literal
+type Binding struct {
+	Name string `json:"name"`
+	Ref  string `json:"ref"`
 }
 
+// Reference - an earlier example used by an expression and its result
+type Reference struct {
+	Name      string `json:"name"` // as written: ans, @last, or @<id> for result("<id>")
+	ExampleID string `json:"example_id"`
+	Ref       string `json:"ref"` // a literal or a variable, result:<ref> in Redis
+}
+
+// UserVariable - a named value saved by a user for later expressions
+type UserVariable struct {
+	Name      string    `json:"name" db:"name"`
+	Value     float64   `json:"value" db:"value"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UserFunction - a function defined by a user: f(x, y) = x^2 + 3*y
+type UserFunction struct {
+	Name      string    `json:"name" db:"name"`
+	Params    []string  `json:"params" db:"params"`
+	Body      string    `json:"body" db:"body"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WasmModule - a WebAssembly module with custom operations, uploaded by an admin
+type WasmModule struct {
+	Name       string      `json:"name" db:"name"`
+	Code       []byte      `json:"-" db:"code"`
+	Hash       string      `json:"hash" db:"hash"` // sha256 of Code
+	Operations []Operation `json:"operations" db:"operations"`
+	UploadedBy string      `json:"uploaded_by" db:"uploaded_by"`
+	UpdatedAt  time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// Operation - a function exported by a module: numbers in, a number out
+type Operation struct {
+	Name   string `json:"name"`
+	Arity  int    `json:"arity"`
+	Module string `json:"module,omitempty"`
+}
+
+// SweepRange - values of one variable in a parameter sweep:
+// Steps+1 evenly spaced points from From to To inclusive
+type SweepRange struct {
+	Variable string  `json:"variable"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Steps    int     `json:"steps"`
+}
+
+// Step - a calculated task, saved by workers for explanations
 type Step struct {
-	ID        string  `json:"id" db:"id"`
-	ExampleID string  `json:"example_id" db:"example_id"`
-	Value1    float64 `json:"value1" db:"value1"`
-	Value2    float64 `json:"value2" db:"value2"`
-	Result    float64 `json:"result" db:"result"`
-	Sign      string  `json:"sign" db:"sign"`
-	Variable  string  `json:"variable" db:"variable"`
-	Order     int     `json:"order" db:"order"`
+	ID         string   `json:"id" db:"id"`
+	ExampleID  string   `json:"example_id" db:"example_id"`
+	Value1     *float64 `json:"value1" db:"value1"` // numbers only, nil for other values
+	Value2     *float64 `json:"value2" db:"value2"`
+	Result     *float64 `json:"result" db:"result"`
+	Sign       string   `json:"sign" db:"sign"`
+	Variable   string   `json:"variable" db:"variable"`
+	Order      int      `json:"order" db:"order"`              // index of the task
+	Operands   []string `json:"operands" db:"operands"`        // readable operands, strings are quoted
+	ResultText string   `json:"result_text" db:"result_value"` // readable result
+}
+
+// Explanation - steps of an example in the order they were planned
+type Explanation struct {
+	Steps     []ExplainStep `json:"steps"`
+	Completed bool          `json:"completed"`
+	Error     *string       `json:"error,omitempty"`
+}
+
+// ExplainStep - Step 1: 2 * 2 = 4
+type ExplainStep struct {
+	Number     int    `json:"number"`     // from 1
+	Expression string `json:"expression"` // part of the original expression: 2 * 2
+	Operation  string `json:"operation"`  // the step with values of the operands
+	Result     string `json:"result"`
+	Text       string `json:"text"`
 }
 
 type User struct {
@@ -0,0 +1,220 @@
+package operations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// refreshInterval - how long the list of operations is trusted; a missing
+// operation is looked up at once
+const refreshInterval = time.Minute
+
+// longest names that can be saved: wasm_modules.name and tasks.sign,
+// an operation is the sign of the steps that call it
+const (
+	maxModuleName    = 64
+	maxOperationName = 32
+)
+
+type Config struct {
+	MemoryPages   uint32        `env:"WASM_MEMORY_PAGES" envDefault:"16"` // pages of 64 KiB
+	Timeout       time.Duration `env:"WASM_TIMEOUT" envDefault:"100ms"`   // of one call
+	MaxModuleSize int           `env:"WASM_MAX_MODULE_SIZE" envDefault:"1048576"`
+}
+
+// Registry - custom operations of uploaded WebAssembly modules.
+// The server validates and saves modules, workers run their functions
+// in the sandbox when a task names one of them.
+type Registry struct {
+	modules repo.WasmModuleRepository
+	sandbox *sandbox
+	logger  logger.Logger
+
+	mu       sync.Mutex
+	known    map[string]known // by the name of the operation
+	loadedAt time.Time
+}
+
+// known - an operation and the hash of its module
+type known struct {
+	operation models.Operation
+	hash      string
+}
+
+func NewRegistry(ctx context.Context, modules repo.WasmModuleRepository, cfg Config, logger logger.Logger) *Registry {
+	return &Registry{
+		modules: modules,
+		sandbox: newSandbox(ctx, cfg),
+		logger:  logger,
+	}
+}
+
+// Upload - validates the module and saves it, a module with the same name
+// is replaced. Operations must have names that can be called and must not
+// be exported by other modules.
+func (r *Registry) Upload(ctx context.Context, module *models.WasmModule) error {
+	if err := calculator.CheckName(module.Name); err != nil {
+		return fmt.Errorf("%w: %w", models.ErrInvalidModule, err)
+	}
+	if utf8.RuneCountInString(module.Name) > maxModuleName {
+		return fmt.Errorf("%w: name is longer than %d characters", models.ErrInvalidModule, maxModuleName)
+	}
+	compiled, operations, err := r.sandbox.inspect(ctx, module.Code)
+	if err != nil {
+		return err
+	}
+	compiled.Close(ctx) // workers compile it again
+
+	others, err := r.operations(ctx, true)
+	if err != nil {
+		return err
+	}
+	for i, operation := range operations {
+		if err := calculator.CheckName(operation.Name); err != nil {
+			return fmt.Errorf("%w: operation %w", models.ErrInvalidModule, err)
+		}
+		if utf8.RuneCountInString(operation.Name) > maxOperationName {
+			return fmt.Errorf("%w: operation %s is longer than %d characters", models.ErrInvalidModule, operation.Name, maxOperationName)
+		}
+		if other, ok := others[operation.Name]; ok && other.operation.Module != module.Name {
+			return fmt.Errorf("%w: %s is an operation of module %s", models.ErrInvalidModule, operation.Name, other.operation.Module)
+		}
+		operations[i].Module = module.Name
+	}
+
+	sum := sha256.Sum256(module.Code)
+	module.Hash = hex.EncodeToString(sum[:])
+	module.Operations = operations
+	if err := r.modules.SaveModule(ctx, module); err != nil {
+		return fmt.Errorf("upload module: %w", err)
+	}
+	r.invalidate()
+	r.logger.Info(ctx, "module uploaded", "name", module.Name, "hash", module.Hash, "operations", len(operations))
+	return nil
+}
+
+// Modules - uploaded modules without their code
+func (r *Registry) Modules(ctx context.Context) ([]models.WasmModule, error) {
+	modules, err := r.modules.GetModules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("modules: %w", err)
+	}
+	return modules, nil
+}
+
+// Delete - removes the module, expressions calling its operations fail
+func (r *Registry) Delete(ctx context.Context, name string) (bool, error) {
+	deleted, err := r.modules.DeleteModule(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("delete module: %w", err)
+	}
+	r.invalidate()
+	return deleted, nil
+}
+
+// Unknown - the names that are not operations of any module
+func (r *Registry) Unknown(ctx context.Context, names []string) ([]string, error) {
+	operations, err := r.operations(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	unknown := make([]string, 0)
+	for _, name := range names {
+		if _, ok := operations[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown, nil
+}
+
+// Operation - the function that runs the operation in the sandbox,
+// false if no module exports it
+func (r *Registry) Operation(ctx context.Context, name string) (func(args []float64) (float64, error), bool, error) {
+	return r.operation(ctx, name, false)
+}
+
+// operation - Operation, the list of operations is loaded again once if
+// the module was replaced since it was loaded
+func (r *Registry) operation(ctx context.Context, name string, reloaded bool) (func(args []float64) (float64, error), bool, error) {
+	operations, err := r.operations(ctx, reloaded)
+	if err != nil {
+		return nil, false, err
+	}
+	op, ok := operations[name]
+	if !ok {
+		// uploaded after the list was loaded
+		if operations, err = r.operations(ctx, true); err != nil {
+			return nil, false, err
+		}
+		if op, ok = operations[name]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	compiled, err := r.sandbox.module(ctx, op.hash, func() ([]byte, string, error) {
+		return r.modules.GetModuleCode(ctx, op.operation.Module)
+	})
+	if errors.Is(err, errReplaced) && !reloaded {
+		return r.operation(ctx, name, true)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("module %s: %w", op.operation.Module, err)
+	}
+
+	return func(args []float64) (float64, error) {
+		if len(args) != op.operation.Arity {
+			return 0, fmt.Errorf("%w: %s takes %d arguments, got %d", calculator.ErrInvalidArgument, name, op.operation.Arity, len(args))
+		}
+		return r.sandbox.call(ctx, compiled, name, args)
+	}, true, nil
+}
+
+// operations - operations of all modules, loaded again when they are
+// older than refreshInterval or reload is set
+func (r *Registry) operations(ctx context.Context, reload bool) (map[string]known, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.known != nil && !reload && time.Since(r.loadedAt) < refreshInterval {
+		return r.known, nil
+	}
+	modules, err := r.modules.GetModules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("operations: %w", err)
+	}
+
+	operations := make(map[string]known)
+	hashes := make(map[string]bool, len(modules))
+	for _, module := range modules {
+		hashes[module.Hash] = true
+		for _, operation := range module.Operations {
+			operation.Module = module.Name
+			operations[operation.Name] = known{operation: operation, hash: module.Hash}
+		}
+	}
+	r.sandbox.forget(ctx, hashes) // replaced and deleted modules
+	r.known, r.loadedAt = operations, time.Now()
+	return operations, nil
+}
+
+func (r *Registry) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.known = nil
+}
+
+// Close frees compiled modules
+func (r *Registry) Close(ctx context.Context) error {
+	return r.sandbox.close(ctx)
+}
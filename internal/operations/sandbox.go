@@ -0,0 +1,179 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// sandbox runs functions of modules. Modules can't import anything, so
+// they have no access to files, the network or the clock; memory is
+// limited by the runtime and every call by a timeout. Each call gets a new
+// instance, nothing is kept between calls.
+type sandbox struct {
+	runtime wazero.Runtime
+	cfg     Config
+
+	mu       sync.Mutex
+	compiled map[string]wazero.CompiledModule // by hash of the code
+}
+
+func newSandbox(ctx context.Context, cfg Config) *sandbox {
+	config := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(cfg.MemoryPages).
+		WithCloseOnContextDone(true) // stops infinite loops at the timeout
+	return &sandbox{
+		runtime:  wazero.NewRuntimeWithConfig(ctx, config),
+		cfg:      cfg,
+		compiled: make(map[string]wazero.CompiledModule),
+	}
+}
+
+// inspect compiles the code and returns its operations: exported functions
+// that take and return f64, sorted by name
+func (s *sandbox) inspect(ctx context.Context, code []byte) (wazero.CompiledModule, []models.Operation, error) {
+	if len(code) > s.cfg.MaxModuleSize {
+		return nil, nil, fmt.Errorf("%w: module is larger than %d bytes", models.ErrInvalidModule, s.cfg.MaxModuleSize)
+	}
+	compiled, err := s.runtime.CompileModule(ctx, code)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", models.ErrInvalidModule, err)
+	}
+	if imports := compiled.ImportedFunctions(); len(imports) > 0 {
+		module, name, _ := imports[0].Import()
+		compiled.Close(ctx)
+		return nil, nil, fmt.Errorf("%w: modules can't import anything, it imports %s.%s", models.ErrInvalidModule, module, name)
+	}
+	if imports := compiled.ImportedMemories(); len(imports) > 0 {
+		compiled.Close(ctx)
+		return nil, nil, fmt.Errorf("%w: modules can't import memory", models.ErrInvalidModule)
+	}
+
+	operations := make([]models.Operation, 0)
+	for name, definition := range compiled.ExportedFunctions() {
+		if numeric(definition) {
+			operations = append(operations, models.Operation{Name: name, Arity: len(definition.ParamTypes())})
+		}
+	}
+	if len(operations) == 0 {
+		compiled.Close(ctx)
+		return nil, nil, fmt.Errorf("%w: no exported function takes and returns f64", models.ErrInvalidModule)
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].Name < operations[j].Name })
+	return compiled, operations, nil
+}
+
+// numeric - f64 parameters and a single f64 result
+func numeric(definition api.FunctionDefinition) bool {
+	results := definition.ResultTypes()
+	if len(results) != 1 || results[0] != api.ValueTypeF64 {
+		return false
+	}
+	for _, param := range definition.ParamTypes() {
+		if param != api.ValueTypeF64 {
+			return false
+		}
+	}
+	return true
+}
+
+// errReplaced - the module has other code than the hash it was asked by,
+// it was replaced after the list of operations was loaded
+var errReplaced = errors.New("module was replaced")
+
+// module - the compiled module with the hash, code is read only when it is
+// not compiled yet. Reading and compiling are done without the lock, so
+// calls of other modules don't wait; if two callers compile the same code,
+// the first one is kept. Code with another hash is kept under its own hash
+// and errReplaced is returned.
+func (s *sandbox) module(ctx context.Context, hash string, code func() ([]byte, string, error)) (wazero.CompiledModule, error) {
+	s.mu.Lock()
+	compiled, ok := s.compiled[hash]
+	s.mu.Unlock()
+	if ok {
+		return compiled, nil
+	}
+
+	data, actual, err := code()
+	if err != nil {
+		return nil, err
+	}
+	compiled, _, err = s.inspect(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if other, ok := s.compiled[actual]; ok {
+		compiled.Close(ctx)
+		compiled = other
+	} else {
+		s.compiled[actual] = compiled
+	}
+	s.mu.Unlock()
+
+	if actual != hash {
+		return nil, fmt.Errorf("%w: hash %s, expected %s", errReplaced, actual, hash)
+	}
+	return compiled, nil
+}
+
+// forget closes compiled modules whose hashes are not kept
+func (s *sandbox) forget(ctx context.Context, keep map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, compiled := range s.compiled {
+		if !keep[hash] {
+			compiled.Close(ctx)
+			delete(s.compiled, hash)
+		}
+	}
+}
+
+// call runs the function in a new instance of the module
+func (s *sandbox) call(ctx context.Context, compiled wazero.CompiledModule, name string, args []float64) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	// no name: instances of the same module don't clash, no start function
+	config := wazero.NewModuleConfig().WithName("").WithStartFunctions()
+	instance, err := s.runtime.InstantiateModule(ctx, compiled, config)
+	if err != nil {
+		return 0, s.failed(ctx, name, err)
+	}
+	defer instance.Close(context.Background())
+
+	function := instance.ExportedFunction(name)
+	if function == nil {
+		return 0, fmt.Errorf("%w: %s", calculator.ErrUnknownFunction, name)
+	}
+	params := make([]uint64, len(args))
+	for i, arg := range args {
+		params[i] = api.EncodeF64(arg)
+	}
+	results, err := function.Call(ctx, params...)
+	if err != nil {
+		return 0, s.failed(ctx, name, err)
+	}
+	return api.DecodeF64(results[0]), nil
+}
+
+// failed - a trap, the timeout or the memory limit
+func (s *sandbox) failed(ctx context.Context, name string, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s ran longer than %s", calculator.ErrOperationFailed, name, s.cfg.Timeout)
+	}
+	return fmt.Errorf("%w: %s: %v", calculator.ErrOperationFailed, name, err)
+}
+
+func (s *sandbox) close(ctx context.Context) error {
+	return s.runtime.Close(ctx)
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
+	"github.com/tainj/distributed_calculator2/pkg/retry"
+)
+
+// DeadLetterConsumer saves tasks workers gave up on and fails their
+// examples. Pending tasks of the example are kept, so a replayed task
+// can still finish it.
+type DeadLetterConsumer struct {
+	letters  kafka.TaskQueue
+	repo     repo.DeadLetterRepository
+	examples repo.ExampleRepository
+	finisher Finisher
+	retry    retry.Config
+	logger   logger.Logger
+}
+
+func NewDeadLetterConsumer(letters kafka.TaskQueue, deadLetters repo.DeadLetterRepository, examples repo.ExampleRepository, finisher Finisher, policy retry.Config, logger logger.Logger) *DeadLetterConsumer {
+	return &DeadLetterConsumer{letters: letters, repo: deadLetters, examples: examples, finisher: finisher, retry: policy, logger: logger}
+}
+
+// Run reads dead letters until ctx is done
+func (c *DeadLetterConsumer) Run(ctx context.Context) {
+	c.logger.Info(ctx, "reading dead letters from kafka...")
+	consume(ctx, c.letters, c.retry, c.logger, c.Handle, c.giveUp)
+}
+
+// giveUp - a letter that can't be saved still fails its example,
+// the letter itself is only logged
+func (c *DeadLetterConsumer) giveUp(ctx context.Context, letter models.DeadLetter, _ int, reason error) {
+	c.logger.Error(ctx, "dead letter is not saved", "id", letter.ID, "payload", letter.Payload, "error", reason)
+	if letter.ExampleID == "" {
+		return
+	}
+	failed, err := c.examples.UpdateExampleWithError(ctx, letter.ExampleID, letter.ExampleError())
+	if err != nil {
+		c.logger.Error(ctx, "failed to fail example of dead letter", "example_id", letter.ExampleID, "error", err)
+		return
+	}
+	if !failed {
+		return
+	}
+	if err := c.finisher.Finished(ctx, letter.ExampleID); err != nil {
+		c.logger.Error(ctx, "failed to release dependent examples", "example_id", letter.ExampleID, "error", err)
+	}
+}
+
+// Handle - saves the letter, the example of its task gets ErrTaskFailed
+func (c *DeadLetterConsumer) Handle(ctx context.Context, letter models.DeadLetter) error {
+	c.logger.Warn(ctx, "dead letter",
+		"id", letter.ID,
+		"example_id", letter.ExampleID,
+		"variable", letter.Variable,
+		"attempts", letter.Attempts,
+		"reason", letter.Reason,
+	)
+	if err := c.repo.SaveDeadLetter(ctx, letter); err != nil {
+		return fmt.Errorf("save dead letter: %w", err)
+	}
+	if letter.ExampleID == "" {
+		return nil // not a task
+	}
+
+	failed, err := c.examples.UpdateExampleWithError(ctx, letter.ExampleID, letter.ExampleError())
+	if err != nil {
+		return fmt.Errorf("save error: %w", err)
+	}
+	if !failed {
+		return nil // finished before the letter came
+	}
+	if err := c.finisher.Finished(ctx, letter.ExampleID); err != nil {
+		c.logger.Error(ctx, "failed to release dependent examples", "example_id", letter.ExampleID, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// DeadlineConfig - how long an example may be calculated: CALCULATION_TIMEOUT
+// if the request sets no deadline, CALCULATION_MAX_TIMEOUT at most. Expired
+// examples are looked for every DEADLINE_SWEEP_INTERVAL.
+type DeadlineConfig struct {
+	Default  time.Duration `env:"CALCULATION_TIMEOUT" envDefault:"5m"`
+	Max      time.Duration `env:"CALCULATION_MAX_TIMEOUT" envDefault:"1h"`
+	Interval time.Duration `env:"DEADLINE_SWEEP_INTERVAL" envDefault:"10s"`
+}
+
+// DeadlineSweeper fails examples that are not calculated by their deadline,
+// so an example whose task got lost doesn't wait forever. Workers drop
+// tasks of expired examples themselves.
+type DeadlineSweeper struct {
+	examples     repo.ExampleRepository
+	orchestrator *Orchestrator
+	finisher     Finisher
+	interval     time.Duration
+	logger       logger.Logger
+}
+
+// Longest - the longest time an example may be calculated, 0 if a request
+// may go without a deadline or set any
+func (c DeadlineConfig) Longest() time.Duration {
+	if c.Default <= 0 || c.Max <= 0 {
+		return 0
+	}
+	return max(c.Default, c.Max)
+}
+
+func NewDeadlineSweeper(examples repo.ExampleRepository, orchestrator *Orchestrator, finisher Finisher, cfg DeadlineConfig, logger logger.Logger) *DeadlineSweeper {
+	return &DeadlineSweeper{examples: examples, orchestrator: orchestrator, finisher: finisher, interval: cfg.Interval, logger: logger}
+}
+
+// Run sweeps expired examples every interval until ctx is done,
+// an interval of 0 turns the sweeper off
+func (s *DeadlineSweeper) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		s.logger.Warn(ctx, "deadline sweeper is off, DEADLINE_SWEEP_INTERVAL is 0")
+		return
+	}
+	s.logger.Info(ctx, "looking for expired examples...", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Sweep(ctx); err != nil {
+				s.logger.Error(ctx, "failed to sweep expired examples", "error", err)
+			}
+		}
+	}
+}
+
+// Sweep - expired examples get ErrTimeout and lose their held and pending
+// tasks, then examples waiting for them fail too. Each example is expired
+// once, so several servers may sweep at the same time. Pending tasks are
+// dropped again here, in case one was saved while the example expired.
+func (s *DeadlineSweeper) Sweep(ctx context.Context) error {
+	ids, err := s.examples.ExpireExamples(ctx, models.ErrTimeout.Error())
+	if err != nil {
+		return fmt.Errorf("expire examples: %w", err)
+	}
+	for _, id := range ids {
+		s.logger.Warn(ctx, "example timed out", "example_id", id)
+		if err := s.orchestrator.Failed(ctx, id); err != nil {
+			s.logger.Error(ctx, "failed to drop pending tasks", "example_id", id, "error", err)
+		}
+		if err := s.finisher.Finished(ctx, id); err != nil {
+			s.logger.Error(ctx, "failed to release dependent examples", "example_id", id, "error", err)
+		}
+	}
+	return nil
+}
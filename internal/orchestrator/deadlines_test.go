@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// expiringExamples - ExpireExamples returns the ids once, as the repository
+// expires each example once
+type expiringExamples struct {
+	repo.ExampleRepository
+	expired []string
+	errors  []string
+}
+
+func (e *expiringExamples) ExpireExamples(ctx context.Context, errorMsg string) ([]string, error) {
+	ids := e.expired
+	e.expired = nil
+	for range ids {
+		e.errors = append(e.errors, errorMsg)
+	}
+	return ids, nil
+}
+
+// finished - ids of examples whose dependents were released
+type finished []string
+
+func (f *finished) Finished(ctx context.Context, exampleID string) error {
+	*f = append(*f, exampleID)
+	return nil
+}
+
+func TestSweep(t *testing.T) {
+	ctx := context.Background()
+	pending := newPendingStore()
+	queue := &taskQueue{}
+	o := NewOrchestrator(pending, queue, logger.New("test"))
+
+	// x2 waits for x1 that got lost
+	tasks := []*models.Task{
+		{Num1: "1", Num2: "2", Sign: "+", Variable: "x1", ExampleID: "e1"},
+		{Num1: "x1", Num2: "3", Sign: "*", Variable: "x2", ExampleID: "e1", IsFinal: true},
+	}
+	if err := o.Dispatch(ctx, tasks); err != nil {
+		t.Fatalf("Dispatch() error: %v", err)
+	}
+
+	examples := &expiringExamples{expired: []string{"e1"}}
+	released := &finished{}
+	sweeper := NewDeadlineSweeper(examples, o, released, DeadlineConfig{}, logger.New("test"))
+
+	if err := sweeper.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep() error: %v", err)
+	}
+	if expected := []string{models.ErrTimeout.Error()}; !reflect.DeepEqual(examples.errors, expected) {
+		t.Errorf("examples failed with %q, expected %q", examples.errors, expected)
+	}
+	if len(pending.tasks) != 0 {
+		t.Errorf("pending %v after the example expired, expected nothing", pending.waiting())
+	}
+	if expected := []string{"e1"}; !reflect.DeepEqual([]string(*released), expected) {
+		t.Errorf("released %q, expected %q", *released, expected)
+	}
+
+	// a late result of x1 sends nothing
+	queue.sent = nil
+	if err := o.Completed(ctx, *tasks[0]); err != nil {
+		t.Fatalf("Completed(x1) error: %v", err)
+	}
+	if len(queue.sent) != 0 {
+		t.Errorf("sent %q after the example expired, expected nothing", queue.sent)
+	}
+
+	// the next sweep finds nothing
+	if err := sweeper.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep() error: %v", err)
+	}
+	if expected := []string{"e1"}; !reflect.DeepEqual([]string(*released), expected) {
+		t.Errorf("released %q after the second sweep, expected %q", *released, expected)
+	}
+}
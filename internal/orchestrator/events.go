@@ -0,0 +1,167 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
+	"github.com/tainj/distributed_calculator2/pkg/retry"
+)
+
+// Finisher - examples waiting for a finished one, see dependency.Releaser
+type Finisher interface {
+	Finished(ctx context.Context, exampleID string) error
+}
+
+// Consumer reads completion events of workers. Only the server writes
+// examples: steps and progress, the final result or the error; tasks
+// waiting for the calculated one are sent from here.
+type Consumer struct {
+	results       kafka.TaskQueue
+	deadLetters   kafka.TaskQueue // results that can't be saved
+	examples      repo.ExampleRepository
+	cancellations repo.CancellationRepository
+	variables     repo.VariableRepository // results of tasks in Redis
+	orchestrator  *Orchestrator
+	finisher      Finisher
+	retry         retry.Config
+	logger        logger.Logger
+}
+
+func NewConsumer(results, deadLetters kafka.TaskQueue, examples repo.ExampleRepository, cancellations repo.CancellationRepository, variables repo.VariableRepository, orchestrator *Orchestrator, finisher Finisher, policy retry.Config, logger logger.Logger) *Consumer {
+	return &Consumer{results: results, deadLetters: deadLetters, examples: examples, cancellations: cancellations, variables: variables, orchestrator: orchestrator, finisher: finisher, retry: policy, logger: logger}
+}
+
+// Run reads events until ctx is done. An event is committed after it is
+// handled; handling it again changes nothing.
+func (c *Consumer) Run(ctx context.Context) {
+	c.logger.Info(ctx, "reading task results from kafka...")
+	consume(ctx, c.results, c.retry, c.logger, c.Handle, c.giveUp)
+}
+
+// giveUp - the task of a result that can't be saved becomes a dead letter:
+// its example fails with ErrTaskFailed and an admin can replay the task
+func (c *Consumer) giveUp(ctx context.Context, result models.TaskResult[calculator.Value], attempts int, reason error) {
+	payload, err := json.Marshal(result.Task)
+	if err != nil {
+		c.logger.Error(ctx, "failed to marshal task of dead letter", "variable", result.Task.Variable, "error", err)
+		return
+	}
+	letter := models.DeadLetter{
+		ID:        uuid.New().String(),
+		ExampleID: result.Task.ExampleID,
+		Variable:  result.Task.Variable,
+		Payload:   string(payload),
+		Reason:    fmt.Sprintf("save result: %s", reason),
+		Attempts:  attempts,
+		WorkerID:  result.WorkerID,
+		FailedAt:  time.Now(),
+	}
+	if _, err := retry.Do(ctx, c.retry, func() error { return c.deadLetters.SendTask(letter) }); err != nil {
+		c.logger.Error(ctx, "failed to send dead letter, result is lost", "example_id", letter.ExampleID, "variable", letter.Variable, "error", err)
+	}
+}
+
+// consume - reads messages of the queue and commits each one after it is
+// handled. Handling is retried by the policy; when the attempts run out
+// the message is given up on and committed, so one broken message does not
+// stop the ones after it. A message that can't be decoded is skipped.
+func consume[T any](ctx context.Context, queue kafka.TaskQueue, policy retry.Config, logger logger.Logger, handle func(context.Context, T) error, giveUp func(context.Context, T, int, error)) {
+	for ctx.Err() == nil {
+		data, message, err := queue.ReadTask()
+		if err != nil {
+			logger.Error(ctx, "failed to read message", "error", err)
+			continue
+		}
+
+		var event T
+		if err := json.Unmarshal(data, &event); err != nil {
+			logger.Error(ctx, "failed to unmarshal message, skipped", "error", err, "raw_json", string(data))
+		} else if attempts, err := retry.Do(ctx, policy, func() error { return handle(ctx, event) }); err != nil {
+			if ctx.Err() != nil {
+				return // not committed, read again after a restart
+			}
+			logger.Error(ctx, "failed to handle message, given up", "attempts", attempts, "error", err, "raw_json", string(data))
+			giveUp(ctx, event, attempts, err)
+		}
+		if err := queue.Commit(message); err != nil {
+			logger.Error(ctx, "failed to commit message", "error", err)
+		}
+	}
+}
+
+// Handle - advances the example of the task
+func (c *Consumer) Handle(ctx context.Context, result models.TaskResult[calculator.Value]) error {
+	task := result.Task
+	c.logger.Debug(ctx, "task result",
+		"example_id", task.ExampleID,
+		"variable", task.Variable,
+		"worker_id", result.WorkerID,
+		"duration", result.Duration,
+		"error", result.Error,
+	)
+
+	// calculated before the worker saw the cancellation
+	cancelled, err := c.cancellations.IsCancelled(ctx, task.ExampleID)
+	if err != nil {
+		return fmt.Errorf("check cancellation: %w", err)
+	}
+	if cancelled {
+		if err := c.variables.DeleteResults(ctx, task.Variable); err != nil {
+			return fmt.Errorf("delete result of cancelled example: %w", err)
+		}
+		c.logger.Debug(ctx, "result of cancelled example dropped", "example_id", task.ExampleID, "variable", task.Variable)
+		return nil
+	}
+
+	if result.Error != "" {
+		failed, err := c.examples.UpdateExampleWithError(ctx, task.ExampleID, result.Error)
+		if err != nil {
+			return fmt.Errorf("save error: %w", err)
+		}
+		if !failed {
+			return nil // finished before, whoever finished it released the rest
+		}
+		if err := c.orchestrator.Failed(ctx, task.ExampleID); err != nil {
+			c.logger.Error(ctx, "failed to drop pending tasks", "example_id", task.ExampleID, "error", err)
+		}
+		c.release(ctx, task.ExampleID)
+		return nil
+	}
+	if result.Value == nil {
+		// reading it again won't help
+		c.logger.Error(ctx, "task result has neither a value nor an error", "variable", task.Variable)
+		return nil
+	}
+
+	// the step is kept for explanations and counted in the progress
+	if err := c.examples.SaveStep(ctx, task, result.Args, *result.Value); err != nil {
+		return fmt.Errorf("save step: %w", err)
+	}
+
+	if !task.IsFinal {
+		// tasks waiting for this result
+		return c.orchestrator.Completed(ctx, task)
+	}
+	if err := c.examples.UpdateExample(ctx, task.ExampleID, *result.Value); err != nil {
+		return fmt.Errorf("update example in DB: %w", err)
+	}
+	c.logger.Info(ctx, "final result saved", "example", task.ExampleID, "result", result.Value.String())
+	c.release(ctx, task.ExampleID)
+	return nil
+}
+
+// release - sends held tasks of examples that use the result of the finished one
+func (c *Consumer) release(ctx context.Context, exampleID string) {
+	if err := c.finisher.Finished(ctx, exampleID); err != nil {
+		c.logger.Error(ctx, "failed to release dependent examples", "example_id", exampleID, "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
+)
+
+// Orchestrator sends the tasks of an example in the order of their
+// dependencies. A task that uses results of other tasks of the example
+// waits in pending_tasks and is sent when workers report that all of them
+// are calculated, so workers never get a task before its operands.
+type Orchestrator struct {
+	pending repo.PendingTaskRepository
+	queue   kafka.TaskQueue
+	logger  logger.Logger
+}
+
+func NewOrchestrator(pending repo.PendingTaskRepository, queue kafka.TaskQueue, logger logger.Logger) *Orchestrator {
+	return &Orchestrator{pending: pending, queue: queue, logger: logger}
+}
+
+// Dispatch - sends the tasks of one example that need no other tasks,
+// the rest are saved until their inputs are calculated
+func (o *Orchestrator) Dispatch(ctx context.Context, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	exampleID := tasks[0].ExampleID
+
+	produced := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		produced[task.Variable] = true
+	}
+
+	ready := make([]*models.Task, 0, len(tasks))
+	pending := make([]models.PendingTask, 0)
+	for _, task := range tasks {
+		if waiting := inputs(task, produced); len(waiting) > 0 {
+			pending = append(pending, models.PendingTask{Task: task, WaitingFor: waiting})
+		} else {
+			ready = append(ready, task)
+		}
+	}
+
+	// saved first: a ready task may be calculated before the loop ends
+	if err := o.pending.SavePendingTasks(ctx, exampleID, pending); err != nil {
+		return fmt.Errorf("dispatch %s: %w", exampleID, err)
+	}
+	if err := o.send(ready); err != nil {
+		return fmt.Errorf("dispatch %s: %w", exampleID, err)
+	}
+	o.logger.Debug(ctx, "tasks dispatched", "example_id", exampleID, "ready", len(ready), "pending", len(pending))
+	return nil
+}
+
+// Completed - the task is calculated, tasks that waited only for it are sent.
+// They stay pending if sending fails, a repeated call after a successful
+// one sends nothing.
+func (o *Orchestrator) Completed(ctx context.Context, task models.Task) error {
+	if err := o.pending.CompleteTask(ctx, task.ExampleID, task.Variable, o.send); err != nil {
+		return fmt.Errorf("complete %s: %w", task.Variable, err)
+	}
+	return nil
+}
+
+// Failed - the example got an error, its pending tasks are not needed
+func (o *Orchestrator) Failed(ctx context.Context, exampleID string) error {
+	if err := o.pending.DropPendingTasks(ctx, exampleID); err != nil {
+		return fmt.Errorf("fail %s: %w", exampleID, err)
+	}
+	return nil
+}
+
+// Redispatch - sends lost tasks of an example again: tasks waiting for
+// other lost ones are saved again, the rest are sent. Pending tasks saved
+// before are dropped, they are among the lost ones.
+func (o *Orchestrator) Redispatch(ctx context.Context, exampleID string, lost []*models.Task) error {
+	if err := o.pending.DropPendingTasks(ctx, exampleID); err != nil {
+		return fmt.Errorf("redispatch %s: %w", exampleID, err)
+	}
+	return o.Dispatch(ctx, lost)
+}
+
+// Resend - sends a task as it was read from kafka, for replaying dead letters
+func (o *Orchestrator) Resend(payload []byte) error {
+	if !json.Valid(payload) {
+		return fmt.Errorf("%w: the message is not JSON", models.ErrNotReplayable)
+	}
+	if err := o.queue.SendTask(json.RawMessage(payload)); err != nil {
+		return fmt.Errorf("send task to kafka: %w", err)
+	}
+	return nil
+}
+
+func (o *Orchestrator) send(tasks []*models.Task) error {
+	for _, task := range tasks {
+		if err := o.queue.SendTask(task); err != nil {
+			return fmt.Errorf("send task to kafka: %w", err)
+		}
+	}
+	return nil
+}
+
+// inputs - operands of the task that are variables of other tasks,
+// without repeats: x * x waits for x once
+func inputs(task *models.Task, produced map[string]bool) []string {
+	refs := []string{task.Num1, task.Num2}
+	if !calculator.IsOperator(task.Sign) {
+		refs = task.Args
+	}
+
+	waiting := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if produced[ref] && ref != task.Variable && !slices.Contains(waiting, ref) {
+			waiting = append(waiting, ref)
+		}
+	}
+	return waiting
+}
@@ -0,0 +1,183 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
+)
+
+// pendingStore - pending_tasks in memory, CompleteTask keeps the changes
+// only if send succeeds, as the transaction of the postgres repository
+type pendingStore struct {
+	tasks map[string]models.PendingTask
+}
+
+func newPendingStore() *pendingStore {
+	return &pendingStore{tasks: make(map[string]models.PendingTask)}
+}
+
+func (s *pendingStore) SavePendingTasks(ctx context.Context, exampleID string, tasks []models.PendingTask) error {
+	for _, task := range tasks {
+		s.tasks[task.Task.Variable] = task
+	}
+	return nil
+}
+
+func (s *pendingStore) CompleteTask(ctx context.Context, exampleID, variable string, send func([]*models.Task) error) error {
+	left := make(map[string]models.PendingTask, len(s.tasks))
+	ready := make([]*models.Task, 0)
+	for name, task := range s.tasks {
+		task.WaitingFor = slices.DeleteFunc(slices.Clone(task.WaitingFor), func(v string) bool { return v == variable })
+		if len(task.WaitingFor) == 0 {
+			ready = append(ready, task.Task)
+			continue
+		}
+		left[name] = task
+	}
+	if err := send(ready); err != nil {
+		return err
+	}
+	s.tasks = left
+	return nil
+}
+
+func (s *pendingStore) DropPendingTasks(ctx context.Context, exampleID string) error {
+	s.tasks = make(map[string]models.PendingTask)
+	return nil
+}
+
+// waiting - the pending tasks and the inputs they wait for
+func (s *pendingStore) waiting() map[string][]string {
+	waiting := make(map[string][]string, len(s.tasks))
+	for name, task := range s.tasks {
+		waiting[name] = task.WaitingFor
+	}
+	return waiting
+}
+
+// taskQueue - records sent tasks, fails sending while err is set
+type taskQueue struct {
+	kafka.TaskQueue
+	sent []string
+	err  error
+}
+
+func (q *taskQueue) SendTask(task interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.sent = append(q.sent, task.(*models.Task).Variable)
+	return nil
+}
+
+func TestInputs(t *testing.T) {
+	produced := map[string]bool{"x1": true, "x2": true, "x3": true}
+
+	tests := []struct {
+		name     string
+		task     *models.Task
+		expected []string
+	}{
+		{"numbers", &models.Task{Num1: "2", Num2: "3", Sign: "+", Variable: "x1"}, []string{}},
+		{"one variable", &models.Task{Num1: "x1", Num2: "3", Sign: "*", Variable: "x2"}, []string{"x1"}},
+		{"two variables", &models.Task{Num1: "x1", Num2: "x2", Sign: "-", Variable: "x3"}, []string{"x1", "x2"}},
+		{"repeated variable", &models.Task{Num1: "x1", Num2: "x1", Sign: "*", Variable: "x2"}, []string{"x1"}},
+		{"saved variable of the user", &models.Task{Num1: "rate", Num2: "x1", Sign: "*", Variable: "x2"}, []string{"x1"}},
+		{"own variable", &models.Task{Num1: "x1", Num2: "1", Sign: "+", Variable: "x1"}, []string{}},
+		{"function", &models.Task{Sign: "max", Args: []string{"x1", "5", "x2", "x1"}, Variable: "x3"}, []string{"x1", "x2"}},
+		{"function ignores num1 and num2", &models.Task{Num1: "x1", Num2: "x2", Sign: "max", Args: []string{"1", "2"}, Variable: "x3"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inputs(tt.task, produced); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("inputs() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	// (1 + 2) * (3 + 4) - 5
+	tasks := []*models.Task{
+		{Num1: "1", Num2: "2", Sign: "+", Variable: "x1", ExampleID: "e1"},
+		{Num1: "3", Num2: "4", Sign: "+", Variable: "x2", ExampleID: "e1"},
+		{Num1: "x1", Num2: "x2", Sign: "*", Variable: "x3", ExampleID: "e1"},
+		{Num1: "x3", Num2: "5", Sign: "-", Variable: "x4", ExampleID: "e1", IsFinal: true},
+	}
+
+	pending := newPendingStore()
+	queue := &taskQueue{}
+	o := NewOrchestrator(pending, queue, logger.New("test"))
+
+	if err := o.Dispatch(context.Background(), tasks); err != nil {
+		t.Fatalf("Dispatch() error: %v", err)
+	}
+	if expected := []string{"x1", "x2"}; !reflect.DeepEqual(queue.sent, expected) {
+		t.Errorf("sent %q, expected %q", queue.sent, expected)
+	}
+	expected := map[string][]string{"x3": {"x1", "x2"}, "x4": {"x3"}}
+	if got := pending.waiting(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("pending %v, expected %v", got, expected)
+	}
+
+	if err := o.Dispatch(context.Background(), nil); err != nil {
+		t.Errorf("Dispatch() of no tasks error: %v", err)
+	}
+}
+
+func TestCompleted(t *testing.T) {
+	tasks := []*models.Task{
+		{Num1: "1", Num2: "2", Sign: "+", Variable: "x1", ExampleID: "e1"},
+		{Num1: "3", Num2: "4", Sign: "+", Variable: "x2", ExampleID: "e1"},
+		{Num1: "x1", Num2: "x2", Sign: "*", Variable: "x3", ExampleID: "e1", IsFinal: true},
+	}
+
+	pending := newPendingStore()
+	queue := &taskQueue{}
+	o := NewOrchestrator(pending, queue, logger.New("test"))
+	ctx := context.Background()
+
+	if err := o.Dispatch(ctx, tasks); err != nil {
+		t.Fatalf("Dispatch() error: %v", err)
+	}
+	queue.sent = nil
+
+	if err := o.Completed(ctx, *tasks[0]); err != nil {
+		t.Fatalf("Completed(x1) error: %v", err)
+	}
+	if len(queue.sent) != 0 {
+		t.Errorf("sent %q after x1, expected nothing", queue.sent)
+	}
+
+	// kafka is down: x3 is not lost
+	queue.err = errors.New("kafka is down")
+	if err := o.Completed(ctx, *tasks[1]); err == nil {
+		t.Fatal("Completed(x2) with a failed send: expected an error")
+	}
+	if expected := map[string][]string{"x3": {"x2"}}; !reflect.DeepEqual(pending.waiting(), expected) {
+		t.Errorf("pending %v after a failed send, expected %v", pending.waiting(), expected)
+	}
+
+	queue.err = nil
+	if err := o.Completed(ctx, *tasks[1]); err != nil {
+		t.Fatalf("Completed(x2) error: %v", err)
+	}
+	if expected := []string{"x3"}; !reflect.DeepEqual(queue.sent, expected) {
+		t.Errorf("sent %q, expected %q", queue.sent, expected)
+	}
+
+	// a repeated result sends nothing
+	if err := o.Completed(ctx, *tasks[1]); err != nil {
+		t.Fatalf("repeated Completed(x2) error: %v", err)
+	}
+	if expected := []string{"x3"}; !reflect.DeepEqual(queue.sent, expected) {
+		t.Errorf("sent %q after a repeated result, expected %q", queue.sent, expected)
+	}
+}
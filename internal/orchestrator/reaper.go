@@ -0,0 +1,175 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// reaperLock - the lock held by the server that reaps examples
+const reaperLock = "reaper"
+
+// ReaperConfig - examples that made no progress for REAPER_THRESHOLD are
+// looked at every REAPER_INTERVAL, REAPER_BATCH at a time. Lost
+// tasks of an example are sent again REAPER_MAX_REDISPATCHES times, then
+// the example fails.
+type ReaperConfig struct {
+	Interval        time.Duration `env:"REAPER_INTERVAL" envDefault:"1m"`
+	Threshold       time.Duration `env:"REAPER_THRESHOLD" envDefault:"2m"`
+	Batch           int           `env:"REAPER_BATCH" envDefault:"100"`
+	MaxRedispatches int           `env:"REAPER_MAX_REDISPATCHES" envDefault:"3"`
+}
+
+// Reaper recovers examples whose tasks got lost: a task that has no result
+// in Redis is sent again, an example whose tasks can't be sent again fails.
+// Only the server holding the reaper lock reaps; examples are claimed
+// before they are looked at, so a pass repeated by another server after
+// the lock moved changes nothing.
+type Reaper struct {
+	examples     repo.ExampleRepository
+	variables    repo.VariableRepository // results of tasks in Redis
+	locks        repo.LockRepository
+	orchestrator *Orchestrator
+	finisher     Finisher
+	config       ReaperConfig
+	owner        string // this server in the lock
+	logger       logger.Logger
+}
+
+func NewReaper(examples repo.ExampleRepository, variables repo.VariableRepository, locks repo.LockRepository, orchestrator *Orchestrator, finisher Finisher, cfg ReaperConfig, logger logger.Logger) *Reaper {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "server"
+	}
+	return &Reaper{
+		examples:     examples,
+		variables:    variables,
+		locks:        locks,
+		orchestrator: orchestrator,
+		finisher:     finisher,
+		config:       cfg,
+		owner:        host + ":" + uuid.New().String(),
+		logger:       logger,
+	}
+}
+
+// Run reaps every interval while this server holds the lock, until ctx is
+// done; an interval of 0 turns the reaper off. The lock is held for two
+// intervals and prolonged each time, another server takes it when this
+// one is gone.
+func (r *Reaper) Run(ctx context.Context) {
+	if r.config.Interval <= 0 {
+		r.logger.Warn(ctx, "reaper is off, REAPER_INTERVAL is 0")
+		return
+	}
+	r.logger.Info(ctx, "looking for stuck examples...", "interval", r.config.Interval, "threshold", r.config.Threshold, "owner", r.owner)
+	defer func() {
+		// the next leader doesn't wait for the lock to expire
+		if err := r.locks.Release(context.Background(), reaperLock, r.owner); err != nil {
+			r.logger.Error(ctx, "failed to release the reaper lock", "error", err)
+		}
+	}()
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			leader, err := r.locks.Acquire(ctx, reaperLock, r.owner, 2*r.config.Interval)
+			if err != nil {
+				r.logger.Error(ctx, "failed to take the reaper lock", "error", err)
+				continue
+			}
+			if !leader {
+				continue // another server reaps
+			}
+			if err := r.Reap(ctx); err != nil {
+				r.logger.Error(ctx, "failed to reap stuck examples", "error", err)
+			}
+		}
+	}
+}
+
+// Reap - one pass over stuck examples
+func (r *Reaper) Reap(ctx context.Context) error {
+	examples, err := r.examples.ClaimStuckExamples(ctx, r.config.Threshold, r.config.Batch)
+	if err != nil {
+		return fmt.Errorf("claim stuck examples: %w", err)
+	}
+	for i := range examples {
+		if err := r.recover(ctx, &examples[i]); err != nil {
+			// claimed already, it is looked at again after the threshold
+			r.logger.Error(ctx, "failed to recover stuck example", "example_id", examples[i].ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// recover - sends the tasks of the example that have no result again, or
+// fails the example if they were sent again too many times or are unknown
+func (r *Reaper) recover(ctx context.Context, example *models.Example) error {
+	if len(example.Tasks) == 0 {
+		return r.fail(ctx, example.ID, fmt.Errorf("%w: its tasks are not known", models.ErrStuck))
+	}
+	if example.Redispatches > r.config.MaxRedispatches {
+		return r.fail(ctx, example.ID, fmt.Errorf("%w: tasks were sent again %d times and lost", models.ErrStuck, r.config.MaxRedispatches))
+	}
+
+	variables := make([]string, 0, len(example.Tasks))
+	for _, task := range example.Tasks {
+		variables = append(variables, task.Variable)
+	}
+	missing, err := r.variables.MissingResults(ctx, variables...)
+	if err != nil {
+		return fmt.Errorf("check results: %w", err)
+	}
+
+	lost := lostTasks(example.Tasks, missing)
+	if err := r.orchestrator.Redispatch(ctx, example.ID, lost); err != nil {
+		return err
+	}
+	r.logger.Warn(ctx, "stuck example sent again", "example_id", example.ID, "lost", len(lost), "tasks", len(example.Tasks), "attempt", example.Redispatches)
+	return nil
+}
+
+// lostTasks - the tasks whose results are missing. If all results are
+// there, the report of the final task was lost: it is calculated again to
+// be reported.
+func lostTasks(tasks []*models.Task, missing []string) []*models.Task {
+	lost := make([]*models.Task, 0, len(missing))
+	for _, task := range tasks {
+		if slices.Contains(missing, task.Variable) || (len(missing) == 0 && task.IsFinal) {
+			lost = append(lost, task)
+		}
+	}
+	return lost
+}
+
+// fail - the example gets the error, its pending tasks are dropped and
+// examples waiting for it fail too
+func (r *Reaper) fail(ctx context.Context, exampleID string, cause error) error {
+	failed, err := r.examples.UpdateExampleWithError(ctx, exampleID, cause.Error())
+	if err != nil {
+		return fmt.Errorf("save error: %w", err)
+	}
+	if !failed {
+		return nil // calculated, cancelled or expired since it was claimed
+	}
+	r.logger.Warn(ctx, "stuck example failed", "example_id", exampleID, "error", cause)
+	if err := r.orchestrator.Failed(ctx, exampleID); err != nil {
+		r.logger.Error(ctx, "failed to drop pending tasks", "example_id", exampleID, "error", err)
+	}
+	if err := r.finisher.Finished(ctx, exampleID); err != nil {
+		r.logger.Error(ctx, "failed to release dependent examples", "example_id", exampleID, "error", err)
+	}
+	return nil
+}
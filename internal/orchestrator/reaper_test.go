@@ -0,0 +1,178 @@
+package orchestrator
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// stuckExamples - ClaimStuckExamples returns the examples once and records
+// the errors examples fail with
+type stuckExamples struct {
+	repo.ExampleRepository
+	stuck  []models.Example
+	failed map[string]string
+}
+
+func (e *stuckExamples) ClaimStuckExamples(ctx context.Context, age time.Duration, limit int) ([]models.Example, error) {
+	stuck := e.stuck
+	e.stuck = nil
+	return stuck, nil
+}
+
+func (e *stuckExamples) UpdateExampleWithError(ctx context.Context, exampleID, errorMsg string) (bool, error) {
+	e.failed[exampleID] = errorMsg
+	return true, nil
+}
+
+// results - variables with results in Redis
+type results []string
+
+func (r results) SetResult(ctx context.Context, variable string, result calculator.Value) error {
+	return nil
+}
+
+func (r results) DeleteResults(ctx context.Context, variables ...string) error {
+	return nil
+}
+
+func (r results) MissingResults(ctx context.Context, variables ...string) ([]string, error) {
+	missing := make([]string, 0)
+	for _, variable := range variables {
+		if !slices.Contains(r, variable) {
+			missing = append(missing, variable)
+		}
+	}
+	return missing, nil
+}
+
+// chain - x1 = 1 + 2, x2 = x1 * 3, x3 = x2 - 4
+func chain() []*models.Task {
+	return []*models.Task{
+		{Num1: "1", Num2: "2", Sign: "+", Variable: "x1", ExampleID: "e1"},
+		{Num1: "x1", Num2: "3", Sign: "*", Variable: "x2", ExampleID: "e1"},
+		{Num1: "x2", Num2: "4", Sign: "-", Variable: "x3", ExampleID: "e1", IsFinal: true},
+	}
+}
+
+func TestLostTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		missing  []string
+		expected []string
+	}{
+		{"nothing calculated", []string{"x1", "x2", "x3"}, []string{"x1", "x2", "x3"}},
+		{"first calculated", []string{"x2", "x3"}, []string{"x2", "x3"}},
+		{"only the final missing", []string{"x3"}, []string{"x3"}},
+		{"all calculated, the report is lost", []string{}, []string{"x3"}},
+		{"unknown variable", []string{"y1"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, task := range lostTasks(chain(), tt.missing) {
+				got = append(got, task.Variable)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("lostTasks() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReap(t *testing.T) {
+	tests := []struct {
+		name       string
+		example    models.Example
+		calculated results
+		sent       []string
+		pending    map[string][]string
+		failed     string // prefix of the error, "" if the example is sent again
+	}{
+		{
+			name:       "lost tasks are sent in order",
+			example:    models.Example{ID: "e1", Tasks: chain(), Redispatches: 1},
+			calculated: results{"x1"},
+			sent:       []string{"x2"},
+			pending:    map[string][]string{"x3": {"x2"}},
+		},
+		{
+			name:       "lost report",
+			example:    models.Example{ID: "e1", Tasks: chain(), Redispatches: 1},
+			calculated: results{"x1", "x2", "x3"},
+			sent:       []string{"x3"},
+			pending:    map[string][]string{},
+		},
+		{
+			name:       "last redispatch",
+			example:    models.Example{ID: "e1", Tasks: chain(), Redispatches: 3},
+			calculated: results{},
+			sent:       []string{"x1"},
+			pending:    map[string][]string{"x2": {"x1"}, "x3": {"x2"}},
+		},
+		{
+			name:       "too many redispatches",
+			example:    models.Example{ID: "e1", Tasks: chain(), Redispatches: 4},
+			calculated: results{"x1"},
+			pending:    map[string][]string{},
+			failed:     models.ErrStuck.Error(),
+		},
+		{
+			name:    "unknown tasks",
+			example: models.Example{ID: "e1", Redispatches: 1},
+			pending: map[string][]string{},
+			failed:  models.ErrStuck.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			pending := newPendingStore()
+			queue := &taskQueue{}
+			o := NewOrchestrator(pending, queue, logger.New("test"))
+
+			// pending tasks of the first dispatch are replaced
+			if err := pending.SavePendingTasks(ctx, "e1", []models.PendingTask{{Task: chain()[2], WaitingFor: []string{"x2"}}}); err != nil {
+				t.Fatalf("SavePendingTasks() error: %v", err)
+			}
+
+			examples := &stuckExamples{stuck: []models.Example{tt.example}, failed: make(map[string]string)}
+			released := &finished{}
+			cfg := ReaperConfig{Threshold: time.Minute, Batch: 10, MaxRedispatches: 3}
+			reaper := NewReaper(examples, tt.calculated, nil, o, released, cfg, logger.New("test"))
+
+			if err := reaper.Reap(ctx); err != nil {
+				t.Fatalf("Reap() error: %v", err)
+			}
+			if !slices.Equal(queue.sent, tt.sent) {
+				t.Errorf("sent %q, expected %q", queue.sent, tt.sent)
+			}
+			if got := pending.waiting(); !reflect.DeepEqual(got, tt.pending) {
+				t.Errorf("pending %v, expected %v", got, tt.pending)
+			}
+
+			failed, ok := examples.failed["e1"]
+			if tt.failed == "" && ok {
+				t.Errorf("example failed with %q, expected it sent again", failed)
+			}
+			if tt.failed != "" {
+				if !strings.HasPrefix(failed, tt.failed) {
+					t.Errorf("example failed with %q, expected %q", failed, tt.failed)
+				}
+				if expected := []string{"e1"}; !reflect.DeepEqual([]string(*released), expected) {
+					t.Errorf("released %q, expected %q", *released, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package rates
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileRates - format of the rates file:
+// {"base": "USD", "rates": {"USD": 1, "EUR": 0.92}}
+type fileRates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// FileProvider reads rates from a local JSON file
+type FileProvider struct {
+	path string
+
+	mu       sync.RWMutex
+	snapshot *Snapshot
+}
+
+func NewFileProvider(cfg Config) *FileProvider {
+	return &FileProvider{path: cfg.FilePath}
+}
+
+func (p *FileProvider) Current(ctx context.Context) (*Snapshot, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.snapshot == nil {
+		return nil, ErrNotLoaded
+	}
+	return p.snapshot, nil
+}
+
+// Refresh reads the file again. The ID of the snapshot is a hash of the
+// file, so the same table always has the same ID.
+func (p *FileProvider) Refresh(ctx context.Context) (*Snapshot, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("read rates file: %w", err)
+	}
+
+	var file fileRates
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode rates file: %w", err)
+	}
+	if rate, ok := file.Rates[file.Base]; !ok || rate != 1 {
+		return nil, fmt.Errorf("rates file: base currency %q must have rate 1", file.Base)
+	}
+	for code, rate := range file.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("rates file: rate of %s must be positive", code)
+		}
+	}
+
+	hash := sha256.Sum256(data)
+	snapshot := &Snapshot{
+		ID:       hex.EncodeToString(hash[:8]),
+		Base:     file.Base,
+		Rates:    file.Rates,
+		LoadedAt: time.Now().UTC(),
+	}
+
+	p.mu.Lock()
+	p.snapshot = snapshot
+	p.mu.Unlock()
+	return snapshot, nil
+}
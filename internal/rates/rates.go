@@ -0,0 +1,31 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotLoaded = errors.New("currency rates are not loaded")
+
+type Config struct {
+	FilePath string `env:"RATES_FILE" envDefault:"rates.json"`
+}
+
+// Snapshot - rates at one moment. Every example with money keeps the ID
+// of the snapshot it was calculated with.
+type Snapshot struct {
+	ID       string
+	Base     string             // currency with rate 1
+	Rates    map[string]float64 // units of the currency for one unit of Base
+	LoadedAt time.Time
+}
+
+// Provider - source of currency rates. The file provider works offline,
+// an HTTP feed can implement the same interface.
+type Provider interface {
+	// Current returns the last loaded snapshot
+	Current(ctx context.Context) (*Snapshot, error)
+	// Refresh loads rates again and returns the new snapshot
+	Refresh(ctx context.Context) (*Snapshot, error)
+}
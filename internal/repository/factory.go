@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	postgresRepo "github.com/tainj/distributed_calculator2/internal/repository/postgres"
 	redisRepo "github.com/tainj/distributed_calculator2/internal/repository/redis"
 	"github.com/tainj/distributed_calculator2/pkg/db/cache"
@@ -32,7 +34,45 @@ func (f *RepositoryFactory) CreateExampleRepository() ExampleRepository {
 	return postgresRepo.NewPostgresResultRepository(f.postgresDB, f.logger.With("layer", "repo"))
 }
 
+// CreatePendingTaskRepository creates repository for tasks waiting for other tasks of their example
+func (f *RepositoryFactory) CreatePendingTaskRepository() PendingTaskRepository {
+	return postgresRepo.NewPostgresPendingTaskRepository(f.postgresDB, f.logger.With("layer", "repo"))
+}
+
+// CreateDeadLetterRepository creates repository for tasks workers gave up on
+func (f *RepositoryFactory) CreateDeadLetterRepository() DeadLetterRepository {
+	return postgresRepo.NewPostgresDeadLetterRepository(f.postgresDB, f.logger.With("layer", "repo"))
+}
+
+// CreateUserVariableRepository creates repository for saved variables of users,
+// stored in postgres and cached in redis
+func (f *RepositoryFactory) CreateUserVariableRepository() UserVariableRepository {
+	store := postgresRepo.NewPostgresUserVariableRepository(f.postgresDB, f.logger.With("layer", "repo"))
+	return redisRepo.NewCachedUserVariableRepository(store, f.redisCache, f.logger.With("layer", "repo"))
+}
+
+// CreateUserFunctionRepository creates repository for functions defined by users
+func (f *RepositoryFactory) CreateUserFunctionRepository() UserFunctionRepository {
+	return postgresRepo.NewPostgresUserFunctionRepository(f.postgresDB, f.logger.With("layer", "repo"))
+}
+
+// CreateWasmModuleRepository creates repository for modules with custom operations
+func (f *RepositoryFactory) CreateWasmModuleRepository() WasmModuleRepository {
+	return postgresRepo.NewPostgresWasmModuleRepository(f.postgresDB, f.logger.With("layer", "repo"))
+}
+
 // CreateVariableRepository создает репозиторий для работы с переменными в Redis
 func (f *RepositoryFactory) CreateVariableRepository() VariableRepository {
 	return redisRepo.NewRedisResultRepository(f.redisCache, f.logger.With("layer", "repo"))
 }
+
+// CreateCancellationRepository creates repository for cancelled examples in Redis,
+// ttl is the longest time an example may be calculated
+func (f *RepositoryFactory) CreateCancellationRepository(ttl time.Duration) CancellationRepository {
+	return redisRepo.NewRedisCancellationRepository(f.redisCache, ttl, f.logger.With("layer", "repo"))
+}
+
+// CreateLockRepository creates repository for locks in Redis, one replica of the server does a job at a time
+func (f *RepositoryFactory) CreateLockRepository() LockRepository {
+	return redisRepo.NewRedisLockRepository(f.redisCache, f.logger.With("layer", "repo"))
+}
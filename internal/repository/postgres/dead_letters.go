@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+var deadLetterColumns = []string{
+	"id", "example_id", "variable", "payload", "reason", "attempts", "worker_id", "failed_at", "replayed_at",
+}
+
+type PostgresDeadLetterRepository struct {
+	db     *postgres.DB
+	logger logger.Logger
+}
+
+func NewPostgresDeadLetterRepository(db *postgres.DB, logger logger.Logger) *PostgresDeadLetterRepository {
+	return &PostgresDeadLetterRepository{db: db, logger: logger}
+}
+
+// SaveDeadLetter saves the letter, a letter read again is saved once
+func (r *PostgresDeadLetterRepository) SaveDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	query := sq.Insert("dead_letters").
+		Columns("id", "example_id", "variable", "payload", "reason", "attempts", "worker_id", "failed_at").
+		Values(letter.ID, letter.ExampleID, letter.Variable, letter.Payload, letter.Reason, letter.Attempts, letter.WorkerID, letter.FailedAt).
+		Suffix("ON CONFLICT (id) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if _, err := query.ExecContext(ctx); err != nil {
+		return fmt.Errorf("repository.SaveDeadLetter: %w", err)
+	}
+
+	r.logger.Debug(ctx, "dead letter saved", "id", letter.ID, "exampleId", letter.ExampleID)
+	return nil
+}
+
+// GetDeadLetters returns the latest letters, newest first
+func (r *PostgresDeadLetterRepository) GetDeadLetters(ctx context.Context, limit int) ([]models.DeadLetter, error) {
+	query := sq.Select(deadLetterColumns...).
+		From("dead_letters").
+		OrderBy("failed_at DESC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository.GetDeadLetters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := make([]models.DeadLetter, 0)
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repository.GetDeadLetters: failed to scan row: %w", err)
+		}
+		letters = append(letters, *letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository.GetDeadLetters: %w", err)
+	}
+	return letters, nil
+}
+
+// GetDeadLetter returns the letter by its id, nil if there is none
+func (r *PostgresDeadLetterRepository) GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	query := sq.Select(deadLetterColumns...).
+		From("dead_letters").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	letter, err := scanDeadLetter(query.QueryRowContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository.GetDeadLetter: %w", err)
+	}
+	return letter, nil
+}
+
+// MarkReplayed remembers that the letter was sent to workers again
+func (r *PostgresDeadLetterRepository) MarkReplayed(ctx context.Context, id string) error {
+	query := sq.Update("dead_letters").
+		Set("replayed_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if _, err := query.ExecContext(ctx); err != nil {
+		return fmt.Errorf("repository.MarkReplayed: %w", err)
+	}
+	return nil
+}
+
+func scanDeadLetter(row rowScanner) (*models.DeadLetter, error) {
+	var letter models.DeadLetter
+	var exampleID, variable, workerID sql.NullString
+	var replayedAt sql.NullTime
+
+	err := row.Scan(
+		&letter.ID,
+		&exampleID,
+		&variable,
+		&letter.Payload,
+		&letter.Reason,
+		&letter.Attempts,
+		&workerID,
+		&letter.FailedAt,
+		&replayedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	letter.ExampleID = exampleID.String
+	letter.Variable = variable.String
+	letter.WorkerID = workerID.String
+	if replayedAt.Valid {
+		letter.ReplayedAt = &replayedAt.Time
+	}
+	return &letter, nil
+}
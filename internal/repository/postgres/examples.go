@@ -3,11 +3,15 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
 	"github.com/tainj/distributed_calculator2/pkg/logger"
 )
@@ -22,22 +26,80 @@ type PostgresResultRepository struct {
 }
 
 func (r *PostgresResultRepository) SaveExample(ctx context.Context, example *models.Example) error {
-	calculated := example.Error != nil
+	// an example with an error or a ready result doesn't need workers
+	calculated := example.Error != nil || example.Result != nil
+
+	// position only makes sense for sweep points
+	var position *int
+	if example.ParentID != nil {
+		position = &example.Position
+	}
+
+	variables, err := marshalVariables(example.Variables)
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	bindings, err := marshalList(example.Bindings, "bindings")
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	references, err := marshalList(example.References, "references")
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	dependsOn, err := marshalList(example.DependsOn, "dependencies")
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	heldTasks, err := marshalHeldTasks(example.HeldTasks)
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	tasks, err := marshalList(example.Tasks, "tasks")
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	userVariables, err := marshalVariables(example.UserVariables)
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
+	userFunctions, err := marshalList(example.UserFunctions, "functions")
+	if err != nil {
+		return fmt.Errorf("repository.SaveExample: %w", err)
+	}
 
 	query := sq.Insert("examples").
-		Columns("id", "expression", "response", "user_id", "calculated", "error").
+		Columns("id", "expression", "response", "user_id", "calculated", "result", "error", "parent_id", "position", "variables", "seed", "rates_snapshot", "ieee", "syntax", "locale", "bindings", "user_variables", "user_functions", "refs", "depends_on", "held_tasks", "tasks", "total_tasks", "deadline").
 		Values(
 			example.ID,
 			example.Expression,
 			example.Response,
 			example.UserID,
 			calculated,
+			example.Result,
 			example.Error,
+			example.ParentID,
+			position,
+			variables,
+			example.Seed,
+			example.RatesSnapshot,
+			example.IEEE,
+			syntaxOf(example),
+			example.Locale,
+			bindings,
+			userVariables,
+			userFunctions,
+			references,
+			dependsOn,
+			heldTasks,
+			tasks,
+			example.TotalTasks,
+			example.Deadline,
 		).
 		PlaceholderFormat(sq.Dollar).
 		RunWith(r.db.Db)
 
-	_, err := query.ExecContext(ctx)
+	_, err = query.ExecContext(ctx)
 	if err != nil {
 		return fmt.Errorf("repository.SaveExample: failed to insert example: %w", err)
 	}
@@ -45,12 +107,28 @@ func (r *PostgresResultRepository) SaveExample(ctx context.Context, example *mod
 	return nil
 }
 
-func (r *PostgresResultRepository) UpdateExample(ctx context.Context, exampleId string, result float64) error {
+func (r *PostgresResultRepository) UpdateExample(ctx context.Context, exampleId string, result calculator.Value) error {
+	// numbers go to "result", other values (integers that don't fit
+	// float64 exactly, ±Inf and NaN of the IEEE mode) to "result_value" as JSON
+	var number *float64
+	var value *string
+	if exact, ok := result.Exact(); ok && !math.IsInf(exact, 0) && !math.IsNaN(exact) {
+		number = &exact
+	} else {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("repository.UpdateExample: failed to encode result: %w", err)
+		}
+		encoded := string(data)
+		value = &encoded
+	}
+
 	// update status and result by id
 	query := sq.Update("examples").
 		Set("calculated", true).
-		Set("result", result).
-		Where(sq.Eq{"id": exampleId}).
+		Set("result", number).
+		Set("result_value", value).
+		Where(sq.Eq{"id": exampleId, "cancelled": false, "error": nil}). // a late result of a cancelled or timed out example is dropped
 		PlaceholderFormat(sq.Dollar).
 		RunWith(r.db.Db)
 
@@ -65,65 +143,230 @@ func (r *PostgresResultRepository) UpdateExample(ctx context.Context, exampleId
 	return nil
 }
 
-func (r *PostgresResultRepository) UpdateExampleWithError(ctx context.Context, exampleId, errorMsg string) error {
+// UpdateExampleWithError fails the example with errorMsg; false if it is
+// already calculated, failed or cancelled, so the first error is kept
+func (r *PostgresResultRepository) UpdateExampleWithError(ctx context.Context, exampleId, errorMsg string) (bool, error) {
 	query := sq.Update("examples").
 		Set("calculated", true).
 		Set("error", errorMsg).
-		Where(sq.Eq{"id": exampleId}).
+		Where(sq.Eq{"id": exampleId, "calculated": false, "error": nil, "cancelled": false}).
 		PlaceholderFormat(sq.Dollar).
 		RunWith(r.db.Db)
 
-	_, err := query.ExecContext(ctx)
+	res, err := query.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to update example with error: %w", err)
+	}
+	affected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to update example with error: %w", err)
+		return false, fmt.Errorf("failed to update example with error: %w", err)
 	}
 
-	r.logger.Debug(ctx, "updating example with error", "exampleId", exampleId, "errorMsg", errorMsg)
+	r.logger.Debug(ctx, "updating example with error", "exampleId", exampleId, "errorMsg", errorMsg, "failed", affected > 0)
 
-	return nil
+	return affected > 0, nil
+}
+
+// ReopenExample clears the error of the example if it is still errorMsg,
+// so the replayed task can finish it; false if the example failed differently
+func (r *PostgresResultRepository) ReopenExample(ctx context.Context, exampleID, errorMsg string) (bool, error) {
+	query := sq.Update("examples").
+		Set("calculated", false).
+		Set("error", nil).
+		Where(sq.Eq{"id": exampleID, "error": errorMsg}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	res, err := query.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("repository.ReopenExample: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.ReopenExample: %w", err)
+	}
+
+	r.logger.Debug(ctx, "reopen example", "exampleId", exampleID, "reopened", affected > 0)
+	return affected > 0, nil
+}
+
+// CancelExample marks the example as cancelled, false if it is already
+// calculated
+func (r *PostgresResultRepository) CancelExample(ctx context.Context, exampleID string) (bool, error) {
+	query := sq.Update("examples").
+		Set("calculated", true).
+		Set("cancelled", true).
+		Set("error", models.ErrCancelled.Error()).
+		Where(sq.Eq{"id": exampleID, "calculated": false}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	res, err := query.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("repository.CancelExample: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.CancelExample: %w", err)
+	}
+
+	r.logger.Debug(ctx, "cancel example", "exampleId", exampleID, "cancelled", affected > 0)
+	return affected > 0, nil
+}
+
+// ExpireExamples fails examples not calculated by their deadline with
+// errorMsg and returns their ids. An example is returned once, whichever
+// replica of the server expires it. Its held and pending tasks are dropped
+// in the same statement, so they are not sent later even if the caller
+// stops before it handles the ids.
+func (r *PostgresResultRepository) ExpireExamples(ctx context.Context, errorMsg string) ([]string, error) {
+	const query = `
+		WITH expired AS (
+			UPDATE examples SET calculated = TRUE, error = $1, held_tasks = NULL
+			WHERE calculated = FALSE AND deadline < NOW()
+			RETURNING id
+		), dropped AS (
+			DELETE FROM pending_tasks WHERE example_id IN (SELECT id FROM expired)
+		)
+		SELECT id FROM expired`
+
+	rows, err := r.db.Db.QueryContext(ctx, query, errorMsg)
+	if err != nil {
+		return nil, fmt.Errorf("repository.ExpireExamples: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("repository.ExpireExamples: failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository.ExpireExamples: %w", err)
+	}
+
+	if len(ids) > 0 {
+		r.logger.Debug(ctx, "examples expired", "count", len(ids))
+	}
+	return ids, nil
+}
+
+// ClaimStuckExamples returns examples that made no progress for longer
+// than age since they were sent, saved a step or were claimed last, at most
+// limit of them, and counts the claim in their redispatches. Tasks are sent
+// at those moments only, so every task without a result has been out for
+// longer than age. A claimed example is not returned again for age, so
+// concurrent callers never get the same one. Only ID, Tasks and
+// Redispatches are read; examples waiting for other examples are skipped.
+func (r *PostgresResultRepository) ClaimStuckExamples(ctx context.Context, age time.Duration, limit int) ([]models.Example, error) {
+	stuck := sq.Select("id").
+		From("examples").
+		Where(sq.Eq{"calculated": false, "held_tasks": nil}).
+		Where(sq.Gt{"total_tasks": 0}). // sweeps have no tasks of their own
+		Where(idleFor(age)).
+		OrderBy("created_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query := sq.Update("examples").
+		Set("redispatches", sq.Expr("redispatches + 1")).
+		Set("reaped_at", sq.Expr("NOW()")).
+		Where(sq.Expr("id IN (?)", stuck)).
+		Suffix("RETURNING id, tasks, redispatches").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository.ClaimStuckExamples: %w", err)
+	}
+	defer rows.Close()
+
+	examples := make([]models.Example, 0)
+	for rows.Next() {
+		var example models.Example
+		var tasks sql.NullString
+		if err := rows.Scan(&example.ID, &tasks, &example.Redispatches); err != nil {
+			return nil, fmt.Errorf("repository.ClaimStuckExamples: failed to scan row: %w", err)
+		}
+		if err := unmarshalList(tasks, &example.Tasks, "tasks"); err != nil {
+			return nil, fmt.Errorf("repository.ClaimStuckExamples: %w", err)
+		}
+		examples = append(examples, example)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository.ClaimStuckExamples: %w", err)
+	}
+
+	if len(examples) > 0 {
+		r.logger.Debug(ctx, "stuck examples claimed", "count", len(examples))
+	}
+	return examples, nil
+}
+
+// idleFor - examples that were not sent, saved no step and were not
+// claimed for longer than age
+func idleFor(age time.Duration) sq.Sqlizer {
+	return sq.Expr("GREATEST(created_at, progressed_at, reaped_at) < NOW() - make_interval(secs => ?)", age.Seconds())
 }
 
-func (r *PostgresResultRepository) GetResult(ctx context.Context, exampleID string) (float64, error) {
-	var calculated bool
+func (r *PostgresResultRepository) GetResult(ctx context.Context, exampleID string) (calculator.Value, error) {
+	var calculated, cancelled bool
 	var result sql.NullFloat64
-	var dbError sql.NullString
+	var dbError, value sql.NullString
 
-	query := sq.Select("calculated", "result", "error").
+	query := sq.Select("calculated", "cancelled", "result", "error", "result_value").
 		From("examples").
 		Where(sq.Eq{"id": exampleID}).
 		PlaceholderFormat(sq.Dollar).
 		RunWith(r.db.Db)
 
-	err := query.QueryRowContext(ctx).Scan(&calculated, &result, &dbError)
+	err := query.QueryRowContext(ctx).Scan(&calculated, &cancelled, &result, &dbError, &value)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, fmt.Errorf("example not found")
+			return calculator.Value{}, fmt.Errorf("example not found")
 		}
-		return 0, fmt.Errorf("failed to query: %w", err)
+		return calculator.Value{}, fmt.Errorf("failed to query: %w", err)
 	}
 
 	if !calculated {
-		return 0, fmt.Errorf("calculation not completed yet")
+		return calculator.Value{}, fmt.Errorf("calculation not completed yet")
+	}
+
+	if cancelled {
+		return calculator.Value{}, models.ErrCancelled
 	}
 
 	if dbError.Valid {
-		return 0, fmt.Errorf("calculation failed: %s", dbError.String)
+		return calculator.Value{}, fmt.Errorf("calculation failed: %s", dbError.String)
+	}
+
+	if value.Valid {
+		var decoded calculator.Value
+		if err := json.Unmarshal([]byte(value.String), &decoded); err != nil {
+			return calculator.Value{}, fmt.Errorf("failed to decode result: %w", err)
+		}
+		r.logger.Debug(ctx, "successful receipt of the result", "exampleId", exampleID, "result", decoded.String())
+		return decoded, nil
 	}
 
 	if !result.Valid {
-		return 0, fmt.Errorf("result is not available")
+		return calculator.Value{}, fmt.Errorf("result is not available")
 	}
 
 	r.logger.Debug(ctx, "successful receipt of the result", "exampleId", exampleID, "result", result)
 
-	return result.Float64, nil
+	return calculator.NumberValue(result.Float64), nil
 }
 
 func (r *PostgresResultRepository) GetExamplesByUserID(ctx context.Context, userID string) ([]models.Example, error) {
 	// build query with squirrel
-	query := sq.Select("id", "expression", "calculated", "result", "error", "created_at").
+	query := sq.Select("id", "expression", "calculated", "result", "error", "result_value", "seed", "rates_snapshot", "ieee", "syntax", "user_variables", "user_functions", "refs", "cancelled", "deadline", "created_at").
 		From("examples").
-		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Eq{"user_id": userID, "parent_id": nil}). // sweep points are shown inside the sweep
 		OrderBy("created_at DESC").
 		PlaceholderFormat(sq.Dollar)
 
@@ -138,7 +381,8 @@ func (r *PostgresResultRepository) GetExamplesByUserID(ctx context.Context, user
 	for rows.Next() {
 		var example models.Example
 		var result sql.NullFloat64
-		var dbError sql.NullString
+		var dbError, value, userVariables, userFunctions, references sql.NullString
+		var deadline sql.NullTime
 
 		err := rows.Scan(
 			&example.ID,
@@ -146,12 +390,36 @@ func (r *PostgresResultRepository) GetExamplesByUserID(ctx context.Context, user
 			&example.Calculated,
 			&result,
 			&dbError,
+			&value,
+			&example.Seed,
+			&example.RatesSnapshot,
+			&example.IEEE,
+			&example.Syntax,
+			&userVariables,
+			&userFunctions,
+			&references,
+			&example.Cancelled,
+			&deadline,
 			&example.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
+		if example.UserVariables, err = unmarshalVariables(userVariables); err != nil {
+			return nil, err
+		}
+		if err := unmarshalList(userFunctions, &example.UserFunctions, "functions"); err != nil {
+			return nil, err
+		}
+		if err := unmarshalList(references, &example.References, "references"); err != nil {
+			return nil, err
+		}
+
+		if example.ResultText, example.ResultKind, err = resultText(value); err != nil {
+			return nil, err
+		}
+
 		// if there's a result - save it
 		if result.Valid {
 			example.Result = &result.Float64
@@ -162,6 +430,10 @@ func (r *PostgresResultRepository) GetExamplesByUserID(ctx context.Context, user
 			example.Error = &dbError.String
 		}
 
+		if deadline.Valid {
+			example.Deadline = &deadline.Time
+		}
+
 		examples = append(examples, example)
 	}
 
@@ -174,3 +446,345 @@ func (r *PostgresResultRepository) GetExamplesByUserID(ctx context.Context, user
 
 	return examples, nil
 }
+
+func (r *PostgresResultRepository) GetExampleByID(ctx context.Context, exampleID string) (*models.Example, error) {
+	query := sq.Select(exampleColumns...).
+		From("examples").
+		Where(sq.Eq{"id": exampleID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	example, err := scanExample(query.QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("example not found")
+		}
+		return nil, fmt.Errorf("repository.GetExampleByID: %w", err)
+	}
+
+	r.logger.Debug(ctx, "successful receipt of the example", "exampleId", exampleID)
+
+	return example, nil
+}
+
+// GetTasks returns the tasks the example was planned into, nil if it was
+// sent before they were saved
+func (r *PostgresResultRepository) GetTasks(ctx context.Context, exampleID string) ([]*models.Task, error) {
+	query := sq.Select("tasks").
+		From("examples").
+		Where(sq.Eq{"id": exampleID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	var data sql.NullString
+	if err := query.QueryRowContext(ctx).Scan(&data); err != nil {
+		return nil, fmt.Errorf("repository.GetTasks: %w", err)
+	}
+	var tasks []*models.Task
+	if err := unmarshalList(data, &tasks, "tasks"); err != nil {
+		return nil, fmt.Errorf("repository.GetTasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetLastExample returns the latest example of the user, sweep points
+// are not counted
+func (r *PostgresResultRepository) GetLastExample(ctx context.Context, userID string) (*models.Example, error) {
+	query := sq.Select(exampleColumns...).
+		From("examples").
+		Where(sq.Eq{"user_id": userID, "parent_id": nil}).
+		OrderBy("created_at DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	example, err := scanExample(query.QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("example not found")
+		}
+		return nil, fmt.Errorf("repository.GetLastExample: %w", err)
+	}
+	return example, nil
+}
+
+// GetDependents returns examples whose tasks are held until the example
+// is calculated
+func (r *PostgresResultRepository) GetDependents(ctx context.Context, exampleID string) ([]models.Example, error) {
+	query := sq.Select(exampleColumns...).
+		From("examples").
+		Where(sq.Expr("depends_on @> jsonb_build_array(?::text)", exampleID)).
+		Where(sq.NotEq{"held_tasks": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(r.db.Db).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependents: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []models.Example
+	for rows.Next() {
+		example, err := scanExample(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		examples = append(examples, *example)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return examples, nil
+}
+
+// TakeHeldTasks clears the held tasks of the example and returns them,
+// false if they were taken before: only one caller sends them
+func (r *PostgresResultRepository) TakeHeldTasks(ctx context.Context, exampleID string) ([]*models.Task, bool, error) {
+	// the row is locked, a concurrent call waits and finds NULL
+	const query = `
+		WITH held AS (
+			SELECT id, held_tasks FROM examples
+			WHERE id = $1 AND held_tasks IS NOT NULL
+			FOR UPDATE
+		)
+		UPDATE examples SET held_tasks = NULL
+		FROM held WHERE examples.id = held.id
+		RETURNING held.held_tasks`
+
+	var data string
+	if err := r.db.Db.QueryRowContext(ctx, query, exampleID).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("repository.TakeHeldTasks: %w", err)
+	}
+
+	var tasks []*models.Task
+	if err := json.Unmarshal([]byte(data), &tasks); err != nil {
+		return nil, false, fmt.Errorf("repository.TakeHeldTasks: failed to decode tasks: %w", err)
+	}
+	r.logger.Debug(ctx, "held tasks taken", "exampleId", exampleID, "count", len(tasks))
+	return tasks, true, nil
+}
+
+func (r *PostgresResultRepository) GetExamplesByParentID(ctx context.Context, parentID string) ([]models.Example, error) {
+	query := sq.Select(exampleColumns...).
+		From("examples").
+		Where(sq.Eq{"parent_id": parentID}).
+		OrderBy("position").
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(r.db.Db).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sweep points: %w", err)
+	}
+	defer rows.Close()
+
+	var examples []models.Example
+	for rows.Next() {
+		example, err := scanExample(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		examples = append(examples, *example)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	r.logger.Debug(ctx, "successful receipt of sweep points", "parentId", parentID, "count", len(examples))
+
+	return examples, nil
+}
+
+// MarkCalculated marks an example without its own result (a sweep) as finished
+func (r *PostgresResultRepository) MarkCalculated(ctx context.Context, exampleID string) error {
+	query := sq.Update("examples").
+		Set("calculated", true).
+		Where(sq.Eq{"id": exampleID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if _, err := query.ExecContext(ctx); err != nil {
+		return fmt.Errorf("repository.MarkCalculated: %w", err)
+	}
+
+	r.logger.Debug(ctx, "example marked as calculated", "exampleId", exampleID)
+
+	return nil
+}
+
+// columns read by scanExample, order matters
+var exampleColumns = []string{
+	"id", "expression", "response", "user_id", "calculated", "result", "error",
+	"result_value", "seed", "rates_snapshot", "ieee", "syntax", "locale", "bindings", "user_variables", "user_functions", "refs", "depends_on", "parent_id",
+	"position", "variables", "total_tasks", "done_tasks", "cancelled", "deadline", "created_at",
+}
+
+// rowScanner - common part of *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExample(row rowScanner) (*models.Example, error) {
+	var example models.Example
+	var result sql.NullFloat64
+	var dbError, value, bindings, userVariables, userFunctions, references, dependsOn, parentID, variables sql.NullString
+	var position sql.NullInt64
+	var deadline sql.NullTime
+
+	err := row.Scan(
+		&example.ID,
+		&example.Expression,
+		&example.Response,
+		&example.UserID,
+		&example.Calculated,
+		&result,
+		&dbError,
+		&value,
+		&example.Seed,
+		&example.RatesSnapshot,
+		&example.IEEE,
+		&example.Syntax,
+		&example.Locale,
+		&bindings,
+		&userVariables,
+		&userFunctions,
+		&references,
+		&dependsOn,
+		&parentID,
+		&position,
+		&variables,
+		&example.TotalTasks,
+		&example.DoneTasks,
+		&example.Cancelled,
+		&deadline,
+		&example.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Valid {
+		example.Result = &result.Float64
+	}
+	if dbError.Valid {
+		example.Error = &dbError.String
+	}
+	if example.ResultText, example.ResultKind, err = resultText(value); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		example.ParentID = &parentID.String
+	}
+	example.Position = int(position.Int64)
+	if deadline.Valid {
+		example.Deadline = &deadline.Time
+	}
+	if example.Variables, err = unmarshalVariables(variables); err != nil {
+		return nil, err
+	}
+	if example.UserVariables, err = unmarshalVariables(userVariables); err != nil {
+		return nil, err
+	}
+	if err := unmarshalList(bindings, &example.Bindings, "bindings"); err != nil {
+		return nil, err
+	}
+	if err := unmarshalList(userFunctions, &example.UserFunctions, "functions"); err != nil {
+		return nil, err
+	}
+	if err := unmarshalList(references, &example.References, "references"); err != nil {
+		return nil, err
+	}
+	if err := unmarshalList(dependsOn, &example.DependsOn, "dependencies"); err != nil {
+		return nil, err
+	}
+
+	return &example, nil
+}
+
+// marshalVariables encodes variables for the JSONB column, nil means NULL
+func marshalVariables(variables map[string]float64) (*string, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode variables: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// unmarshalVariables decodes the JSONB column, nil for NULL
+func unmarshalVariables(value sql.NullString) (map[string]float64, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	var variables map[string]float64
+	if err := json.Unmarshal([]byte(value.String), &variables); err != nil {
+		return nil, fmt.Errorf("failed to decode variables: %w", err)
+	}
+	return variables, nil
+}
+
+// unmarshalList decodes a JSONB column into items, NULL leaves them nil
+func unmarshalList[T any](value sql.NullString, items *[]T, what string) error {
+	if !value.Valid {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value.String), items); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	return nil
+}
+
+// marshalList encodes a list for the JSONB column, nil means NULL
+func marshalList[T any](items []T, what string) (*string, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// marshalHeldTasks - NULL if the tasks are sent, an empty list is still held
+func marshalHeldTasks(tasks []*models.Task) (*string, error) {
+	if tasks == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode held tasks: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// resultText - readable form and kind of a result stored in "result_value",
+// nil for NULL
+func resultText(value sql.NullString) (*string, *string, error) {
+	if !value.Valid {
+		return nil, nil, nil
+	}
+	var decoded calculator.Value
+	if err := json.Unmarshal([]byte(value.String), &decoded); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	text := decoded.String()
+	kind := string(decoded.Kind)
+	return &text, &kind, nil
+}
+
+// syntaxOf - syntax of the saved expression, infix if not set
+func syntaxOf(example *models.Example) string {
+	if example.Syntax == "" {
+		return string(calculator.SyntaxInfix)
+	}
+	return example.Syntax
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIdleFor(t *testing.T) {
+	tests := []struct {
+		name string
+		age  time.Duration
+		args []interface{}
+	}{
+		{"minutes", 2 * time.Minute, []interface{}{120.0}},
+		{"fractions of a second", 1500 * time.Millisecond, []interface{}{1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := idleFor(tt.age).ToSql()
+			if err != nil {
+				t.Fatalf("idleFor() error: %v", err)
+			}
+			// a saved step or a claim counts as progress, not only sending
+			expected := "GREATEST(created_at, progressed_at, reaped_at) < NOW() - make_interval(secs => ?)"
+			if query != expected {
+				t.Errorf("idleFor() = %q, expected %q", query, expected)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("idleFor() args = %v, expected %v", args, tt.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+type PostgresPendingTaskRepository struct {
+	db     *postgres.DB
+	logger logger.Logger
+}
+
+func NewPostgresPendingTaskRepository(db *postgres.DB, logger logger.Logger) *PostgresPendingTaskRepository {
+	return &PostgresPendingTaskRepository{db: db, logger: logger}
+}
+
+// SavePendingTasks saves tasks of the example that can't be sent yet
+func (r *PostgresPendingTaskRepository) SavePendingTasks(ctx context.Context, exampleID string, tasks []models.PendingTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	query := sq.Insert("pending_tasks").
+		Columns("example_id", "variable", "task", "waiting_for").
+		Suffix("ON CONFLICT (example_id, variable) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	for _, pending := range tasks {
+		task, err := json.Marshal(pending.Task)
+		if err != nil {
+			return fmt.Errorf("repository.SavePendingTasks: failed to encode task: %w", err)
+		}
+		waiting, err := json.Marshal(pending.WaitingFor)
+		if err != nil {
+			return fmt.Errorf("repository.SavePendingTasks: failed to encode inputs: %w", err)
+		}
+		query = query.Values(exampleID, pending.Task.Variable, string(task), string(waiting))
+	}
+
+	if _, err := query.ExecContext(ctx); err != nil {
+		return fmt.Errorf("repository.SavePendingTasks: %w", err)
+	}
+
+	r.logger.Debug(ctx, "pending tasks saved", "exampleId", exampleID, "count", len(tasks))
+	return nil
+}
+
+// CompleteTask removes the calculated variable from the inputs the tasks of
+// the example wait for and passes the tasks that wait for nothing now to
+// send. The changes are committed only if send succeeds, so a failed send
+// leaves the tasks pending. Each task is sent once, even if its inputs are
+// completed concurrently.
+func (r *PostgresPendingTaskRepository) CompleteTask(ctx context.Context, exampleID, variable string, send func([]*models.Task) error) error {
+	// rows are locked, a concurrent call waits and sees what is left
+	const query = `
+		WITH done AS (
+			SELECT example_id, variable, waiting_for - $2::text AS waiting_for
+			FROM pending_tasks
+			WHERE example_id = $1 AND waiting_for @> jsonb_build_array($2::text)
+			FOR UPDATE
+		), ready AS (
+			DELETE FROM pending_tasks p USING done
+			WHERE p.example_id = done.example_id AND p.variable = done.variable
+				AND done.waiting_for = '[]'::jsonb
+			RETURNING p.task
+		), waiting AS (
+			UPDATE pending_tasks p SET waiting_for = done.waiting_for FROM done
+			WHERE p.example_id = done.example_id AND p.variable = done.variable
+				AND done.waiting_for <> '[]'::jsonb
+		)
+		SELECT task FROM ready`
+
+	tx, err := r.db.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("repository.CompleteTask: %w", err)
+	}
+	defer tx.Rollback()
+
+	tasks, err := readyTasks(tx.QueryContext(ctx, query, exampleID, variable))
+	if err != nil {
+		return fmt.Errorf("repository.CompleteTask: %w", err)
+	}
+	if err := send(tasks); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("repository.CompleteTask: %w", err)
+	}
+
+	r.logger.Debug(ctx, "task completed", "exampleId", exampleID, "variable", variable, "ready", len(tasks))
+	return nil
+}
+
+// readyTasks - decodes the tasks returned by the query of CompleteTask
+func readyTasks(rows *sql.Rows, err error) ([]*models.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make([]*models.Task, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		var task models.Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			return nil, fmt.Errorf("failed to decode task: %w", err)
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, rows.Err()
+}
+
+// DropPendingTasks removes the tasks of a failed example, they never get their inputs
+func (r *PostgresPendingTaskRepository) DropPendingTasks(ctx context.Context, exampleID string) error {
+	query := sq.Delete("pending_tasks").
+		Where(sq.Eq{"example_id": exampleID}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if _, err := query.ExecContext(ctx); err != nil {
+		return fmt.Errorf("repository.DropPendingTasks: %w", err)
+	}
+	return nil
+}
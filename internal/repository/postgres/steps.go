@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+// SaveStep saves a calculated task, counts it in done_tasks of its example
+// and marks the example as progressed. A retried task is saved and counted once.
+func (r *PostgresResultRepository) SaveStep(ctx context.Context, task models.Task, args []calculator.Value, result calculator.Value) error {
+	operands, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("repository.SaveStep: failed to encode operands: %w", err)
+	}
+	value, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("repository.SaveStep: failed to encode result: %w", err)
+	}
+
+	// numbers are duplicated to value1, value2 and result as before
+	var value1, value2 *float64
+	if len(args) == 2 {
+		value1, value2 = stepNumber(args[0]), stepNumber(args[1])
+	}
+
+	step := sq.Insert("tasks").
+		Columns("id", "example_id", "value1", "value2", "result", "sign", "variable", `"order"`, "operands", "result_value").
+		Values(
+			uuid.New().String(),
+			task.ExampleID,
+			value1,
+			value2,
+			stepNumber(result),
+			task.Sign,
+			task.Variable,
+			task.Index,
+			string(operands),
+			string(value),
+		).
+		Suffix(`ON CONFLICT (example_id, "order") DO NOTHING RETURNING example_id`)
+
+	// nothing is returned for a step saved before, then nothing is counted
+	query := sq.Update("examples").
+		PrefixExpr(sq.Expr("WITH step AS (?)", step)).
+		Set("done_tasks", sq.Expr("done_tasks + 1")).
+		Set("progressed_at", sq.Expr("NOW()")).
+		Where("id IN (SELECT example_id FROM step)").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if _, err := query.ExecContext(ctx); err != nil {
+		return fmt.Errorf("repository.SaveStep: %w", err)
+	}
+	r.logger.Debug(ctx, "save step", "exampleId", task.ExampleID, "order", task.Index)
+	return nil
+}
+
+// GetSteps returns saved steps of the example ordered by the index of the task
+func (r *PostgresResultRepository) GetSteps(ctx context.Context, exampleID string) ([]models.Step, error) {
+	query := sq.Select("id", "example_id", "value1", "value2", "result", "sign", "variable", `"order"`, "operands", "result_value").
+		From("tasks").
+		Where(sq.Eq{"example_id": exampleID}).
+		OrderBy(`"order"`).
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(r.db.Db).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query steps: %w", err)
+	}
+	defer rows.Close()
+
+	steps := make([]models.Step, 0)
+	for rows.Next() {
+		var step models.Step
+		var operands, value sql.NullString
+		err := rows.Scan(
+			&step.ID,
+			&step.ExampleID,
+			&step.Value1,
+			&step.Value2,
+			&step.Result,
+			&step.Sign,
+			&step.Variable,
+			&step.Order,
+			&operands,
+			&value,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan step: %w", err)
+		}
+		if err := decodeStep(&step, operands, value); err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return steps, nil
+}
+
+// decodeStep fills readable operands and result. Steps saved before values
+// became JSON only have numbers.
+func decodeStep(step *models.Step, operands, value sql.NullString) error {
+	if !operands.Valid || !value.Valid {
+		for _, number := range []*float64{step.Value1, step.Value2} {
+			if number != nil {
+				step.Operands = append(step.Operands, calculator.NumberValue(*number).String())
+			}
+		}
+		if step.Result != nil {
+			step.ResultText = calculator.NumberValue(*step.Result).String()
+		}
+		return nil
+	}
+
+	var args []calculator.Value
+	if err := json.Unmarshal([]byte(operands.String), &args); err != nil {
+		return fmt.Errorf("failed to decode operands: %w", err)
+	}
+	var result calculator.Value
+	if err := json.Unmarshal([]byte(value.String), &result); err != nil {
+		return fmt.Errorf("failed to decode step result: %w", err)
+	}
+
+	step.Operands = make([]string, len(args))
+	for i, arg := range args {
+		step.Operands[i] = arg.Literal()
+	}
+	step.ResultText = result.String()
+	return nil
+}
+
+// stepNumber - the value for a FLOAT8 column, nil if it is not a finite number
+func stepNumber(v calculator.Value) *float64 {
+	number, ok := v.Exact()
+	if !ok || math.IsInf(number, 0) || math.IsNaN(number) {
+		return nil
+	}
+	return &number
+}
@@ -77,7 +77,7 @@ func (r *AuthUserRepository) GetByEmail(ctx context.Context, email string) (*mod
 
 func (r *AuthUserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	// получаем пользователя по id
-	query := sq.Select("id", "username", "email", "password_hash", "role", "created_at", "updated_at").
+	query := sq.Select("id", "email", "password_hash", "role", "created_at", "updated_at").
 		From("users").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar)
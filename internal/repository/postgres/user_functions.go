@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+type PostgresUserFunctionRepository struct {
+	db     *postgres.DB
+	logger logger.Logger
+}
+
+func NewPostgresUserFunctionRepository(db *postgres.DB, logger logger.Logger) *PostgresUserFunctionRepository {
+	return &PostgresUserFunctionRepository{db: db, logger: logger}
+}
+
+// SetFunction creates the function or replaces its definition
+func (r *PostgresUserFunctionRepository) SetFunction(ctx context.Context, userID string, function models.UserFunction) (*models.UserFunction, error) {
+	params, err := json.Marshal(function.Params)
+	if err != nil {
+		return nil, fmt.Errorf("repository.SetFunction: failed to encode params: %w", err)
+	}
+
+	query := sq.Insert("user_functions").
+		Columns("user_id", "name", "params", "body").
+		Values(userID, function.Name, string(params), function.Body).
+		Suffix("ON CONFLICT (user_id, name) DO UPDATE SET params = EXCLUDED.params, body = EXCLUDED.body RETURNING updated_at").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if err := query.QueryRowContext(ctx).Scan(&function.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("repository.SetFunction: %w", err)
+	}
+
+	r.logger.Debug(ctx, "function saved", "userId", userID, "name", function.Name)
+	return &function, nil
+}
+
+// GetFunctions returns functions of the user sorted by name
+func (r *PostgresUserFunctionRepository) GetFunctions(ctx context.Context, userID string) ([]models.UserFunction, error) {
+	query := sq.Select("name", "params", "body", "updated_at").
+		From("user_functions").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("name").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository.GetFunctions: %w", err)
+	}
+	defer rows.Close()
+
+	functions := make([]models.UserFunction, 0)
+	for rows.Next() {
+		var function models.UserFunction
+		var params []byte
+		if err := rows.Scan(&function.Name, &params, &function.Body, &function.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("repository.GetFunctions: failed to scan row: %w", err)
+		}
+		if err := json.Unmarshal(params, &function.Params); err != nil {
+			return nil, fmt.Errorf("repository.GetFunctions: failed to decode params of %s: %w", function.Name, err)
+		}
+		functions = append(functions, function)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository.GetFunctions: %w", err)
+	}
+	return functions, nil
+}
+
+// DeleteFunction removes the function, false if there was none
+func (r *PostgresUserFunctionRepository) DeleteFunction(ctx context.Context, userID, name string) (bool, error) {
+	query := sq.Delete("user_functions").
+		Where(sq.Eq{"user_id": userID, "name": name}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	res, err := query.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("repository.DeleteFunction: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.DeleteFunction: %w", err)
+	}
+
+	r.logger.Debug(ctx, "function deleted", "userId", userID, "name", name, "deleted", affected > 0)
+	return affected > 0, nil
+}
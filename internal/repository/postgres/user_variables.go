@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+type PostgresUserVariableRepository struct {
+	db     *postgres.DB
+	logger logger.Logger
+}
+
+func NewPostgresUserVariableRepository(db *postgres.DB, logger logger.Logger) *PostgresUserVariableRepository {
+	return &PostgresUserVariableRepository{db: db, logger: logger}
+}
+
+// SetVariable creates the variable or changes its value
+func (r *PostgresUserVariableRepository) SetVariable(ctx context.Context, userID string, variable models.UserVariable) (*models.UserVariable, error) {
+	query := sq.Insert("user_variables").
+		Columns("user_id", "name", "value").
+		Values(userID, variable.Name, variable.Value).
+		Suffix("ON CONFLICT (user_id, name) DO UPDATE SET value = EXCLUDED.value RETURNING updated_at").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if err := query.QueryRowContext(ctx).Scan(&variable.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("repository.SetVariable: %w", err)
+	}
+
+	r.logger.Debug(ctx, "variable saved", "userId", userID, "name", variable.Name)
+	return &variable, nil
+}
+
+// GetVariables returns variables of the user sorted by name
+func (r *PostgresUserVariableRepository) GetVariables(ctx context.Context, userID string) ([]models.UserVariable, error) {
+	query := sq.Select("name", "value", "updated_at").
+		From("user_variables").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("name").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository.GetVariables: %w", err)
+	}
+	defer rows.Close()
+
+	variables := make([]models.UserVariable, 0)
+	for rows.Next() {
+		var variable models.UserVariable
+		if err := rows.Scan(&variable.Name, &variable.Value, &variable.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("repository.GetVariables: failed to scan row: %w", err)
+		}
+		variables = append(variables, variable)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository.GetVariables: %w", err)
+	}
+	return variables, nil
+}
+
+// DeleteVariable removes the variable, false if there was none
+func (r *PostgresUserVariableRepository) DeleteVariable(ctx context.Context, userID, name string) (bool, error) {
+	query := sq.Delete("user_variables").
+		Where(sq.Eq{"user_id": userID, "name": name}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	res, err := query.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("repository.DeleteVariable: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.DeleteVariable: %w", err)
+	}
+
+	r.logger.Debug(ctx, "variable deleted", "userId", userID, "name", name, "deleted", affected > 0)
+	return affected > 0, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+var ErrModuleNotFound = errors.New("module not found")
+
+type PostgresWasmModuleRepository struct {
+	db     *postgres.DB
+	logger logger.Logger
+}
+
+func NewPostgresWasmModuleRepository(db *postgres.DB, logger logger.Logger) *PostgresWasmModuleRepository {
+	return &PostgresWasmModuleRepository{db: db, logger: logger}
+}
+
+// SaveModule creates the module or replaces its code and operations
+func (r *PostgresWasmModuleRepository) SaveModule(ctx context.Context, module *models.WasmModule) error {
+	operations, err := json.Marshal(module.Operations)
+	if err != nil {
+		return fmt.Errorf("repository.SaveModule: failed to encode operations: %w", err)
+	}
+
+	query := sq.Insert("wasm_modules").
+		Columns("name", "code", "hash", "operations", "uploaded_by").
+		Values(module.Name, module.Code, module.Hash, string(operations), module.UploadedBy).
+		Suffix("ON CONFLICT (name) DO UPDATE SET code = EXCLUDED.code, hash = EXCLUDED.hash, " +
+			"operations = EXCLUDED.operations, uploaded_by = EXCLUDED.uploaded_by RETURNING updated_at").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	if err := query.QueryRowContext(ctx).Scan(&module.UpdatedAt); err != nil {
+		return fmt.Errorf("repository.SaveModule: %w", err)
+	}
+
+	r.logger.Debug(ctx, "module saved", "name", module.Name, "hash", module.Hash)
+	return nil
+}
+
+// GetModules returns modules sorted by name, without their code
+func (r *PostgresWasmModuleRepository) GetModules(ctx context.Context) ([]models.WasmModule, error) {
+	query := sq.Select("name", "hash", "operations", "COALESCE(uploaded_by, '')", "updated_at").
+		From("wasm_modules").
+		OrderBy("name").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository.GetModules: %w", err)
+	}
+	defer rows.Close()
+
+	modules := make([]models.WasmModule, 0)
+	for rows.Next() {
+		var module models.WasmModule
+		var operations []byte
+		if err := rows.Scan(&module.Name, &module.Hash, &operations, &module.UploadedBy, &module.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("repository.GetModules: failed to scan row: %w", err)
+		}
+		if err := json.Unmarshal(operations, &module.Operations); err != nil {
+			return nil, fmt.Errorf("repository.GetModules: failed to decode operations of %s: %w", module.Name, err)
+		}
+		modules = append(modules, module)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository.GetModules: %w", err)
+	}
+	return modules, nil
+}
+
+// GetModuleCode returns the code of the module and its hash
+func (r *PostgresWasmModuleRepository) GetModuleCode(ctx context.Context, name string) ([]byte, string, error) {
+	query := sq.Select("code", "hash").
+		From("wasm_modules").
+		Where(sq.Eq{"name": name}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	var code []byte
+	var hash string
+	err := query.QueryRowContext(ctx).Scan(&code, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("repository.GetModuleCode: %w: %s", ErrModuleNotFound, name)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("repository.GetModuleCode: %w", err)
+	}
+	return code, hash, nil
+}
+
+// DeleteModule removes the module, false if there was none
+func (r *PostgresWasmModuleRepository) DeleteModule(ctx context.Context, name string) (bool, error) {
+	query := sq.Delete("wasm_modules").
+		Where(sq.Eq{"name": name}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db.Db)
+
+	res, err := query.ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("repository.DeleteModule: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository.DeleteModule: %w", err)
+	}
+
+	r.logger.Debug(ctx, "module deleted", "name", name, "deleted", affected > 0)
+	return affected > 0, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tainj/distributed_calculator2/pkg/db/cache"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// cancelledKey - marks a cancelled example until the key expires
+func cancelledKey(exampleID string) string {
+	return "cancelled:" + exampleID
+}
+
+// RedisCancellationRepository keeps a key per cancelled example, workers
+// look for it before they calculate a task. The key lives as long as
+// an example may be calculated, then no task of it is left.
+type RedisCancellationRepository struct {
+	cache  *cache.CACHE
+	ttl    time.Duration // 0 - the key never expires
+	logger logger.Logger
+}
+
+func NewRedisCancellationRepository(cache *cache.CACHE, ttl time.Duration, logger logger.Logger) *RedisCancellationRepository {
+	return &RedisCancellationRepository{cache: cache, ttl: ttl, logger: logger}
+}
+
+func (r *RedisCancellationRepository) Cancel(ctx context.Context, exampleID string) error {
+	if err := r.cache.Client.Set(ctx, cancelledKey(exampleID), 1, r.ttl).Err(); err != nil {
+		return fmt.Errorf("repository.Cancel: %w", err)
+	}
+
+	r.logger.Debug(ctx, "example marked as cancelled", "exampleId", exampleID, "ttl", r.ttl)
+	return nil
+}
+
+func (r *RedisCancellationRepository) IsCancelled(ctx context.Context, exampleID string) (bool, error) {
+	found, err := r.cache.Client.Exists(ctx, cancelledKey(exampleID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("repository.IsCancelled: %w", err)
+	}
+	return found > 0, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tainj/distributed_calculator2/pkg/db/cache"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// acquire - takes the lock if it is free, prolongs it if the owner holds it
+var acquire = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0`)
+
+// release - frees the lock only if the owner holds it
+var release = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisLockRepository keeps locks under lock:<name>, a lock expires after
+// its ttl if the owner is gone
+type RedisLockRepository struct {
+	cache  *cache.CACHE
+	logger logger.Logger
+}
+
+func NewRedisLockRepository(cache *cache.CACHE, logger logger.Logger) *RedisLockRepository {
+	return &RedisLockRepository{cache: cache, logger: logger}
+}
+
+// Acquire takes the lock for ttl, true if the owner holds it now
+func (r *RedisLockRepository) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	taken, err := acquire.Run(ctx, r.cache.Client, []string{"lock:" + name}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("repository.Acquire: %w", err)
+	}
+	return taken == 1, nil
+}
+
+// Release frees the lock, a lock taken by another owner is kept
+func (r *RedisLockRepository) Release(ctx context.Context, name, owner string) error {
+	if err := release.Run(ctx, r.cache.Client, []string{"lock:" + name}, owner).Err(); err != nil {
+		return fmt.Errorf("repository.Release: %w", err)
+	}
+
+	r.logger.Debug(ctx, "lock released", "name", name, "owner", owner)
+	return nil
+}
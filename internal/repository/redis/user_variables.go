@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/db/cache"
+	"github.com/tainj/distributed_calculator2/pkg/logger"
+)
+
+// UserVariableStore - where variables are kept, the cache is in front of it
+type UserVariableStore interface {
+	SetVariable(ctx context.Context, userID string, variable models.UserVariable) (*models.UserVariable, error)
+	GetVariables(ctx context.Context, userID string) ([]models.UserVariable, error)
+	DeleteVariable(ctx context.Context, userID, name string) (bool, error)
+}
+
+// variablesTTL - how long a cached list lives; it is dropped on changes
+// anyway, the TTL only bounds keys nobody reads any more
+const variablesTTL = 10 * time.Minute
+
+// CachedUserVariableRepository keeps all variables of a user in Redis under
+// variables:<user id>:<generation>. Changes go to the store and bump the
+// generation, so a reader that loaded the store before a change fills a key
+// that is never read again instead of bringing old values back.
+type CachedUserVariableRepository struct {
+	store  UserVariableStore
+	cache  *cache.CACHE
+	logger logger.Logger
+}
+
+func NewCachedUserVariableRepository(store UserVariableStore, cache *cache.CACHE, logger logger.Logger) *CachedUserVariableRepository {
+	return &CachedUserVariableRepository{store: store, cache: cache, logger: logger}
+}
+
+func variablesKey(userID string, generation int64) string {
+	return fmt.Sprintf("variables:%s:%d", userID, generation)
+}
+
+func generationKey(userID string) string {
+	return "variables_generation:" + userID
+}
+
+func (r *CachedUserVariableRepository) SetVariable(ctx context.Context, userID string, variable models.UserVariable) (*models.UserVariable, error) {
+	saved, err := r.store.SetVariable(ctx, userID, variable)
+	if err != nil {
+		return nil, err
+	}
+	r.invalidate(ctx, userID)
+	return saved, nil
+}
+
+func (r *CachedUserVariableRepository) GetVariables(ctx context.Context, userID string) ([]models.UserVariable, error) {
+	// the generation is read before the store, a change after it bumps it
+	generation, err := r.cache.Client.Get(ctx, generationKey(userID)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		r.logger.Warn(ctx, "failed to read generation of cached variables", "userId", userID, "error", err)
+		return r.store.GetVariables(ctx, userID)
+	}
+	key := variablesKey(userID, generation)
+
+	var variables []models.UserVariable
+	err = r.cache.GetByKey(ctx, key, &variables)
+	if err == nil {
+		return variables, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		r.logger.Warn(ctx, "failed to read cached variables", "userId", userID, "error", err)
+	}
+
+	variables, err = r.store.GetVariables(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cache.SetByKeyWithTTL(ctx, key, variables, variablesTTL); err != nil {
+		r.logger.Warn(ctx, "failed to cache variables", "userId", userID, "error", err)
+	}
+	return variables, nil
+}
+
+func (r *CachedUserVariableRepository) DeleteVariable(ctx context.Context, userID, name string) (bool, error) {
+	deleted, err := r.store.DeleteVariable(ctx, userID, name)
+	if err != nil {
+		return false, err
+	}
+	r.invalidate(ctx, userID)
+	return deleted, nil
+}
+
+// invalidate - the next read loads the variables from the store again.
+// A stale cache would calculate with old values, so a failure is logged loudly.
+func (r *CachedUserVariableRepository) invalidate(ctx context.Context, userID string) {
+	if err := r.cache.Client.Incr(ctx, generationKey(userID)).Err(); err != nil {
+		r.logger.Error(ctx, "failed to drop cached variables", "userId", userID, "error", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/redis/go-redis/v9"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/db/cache"
 	"github.com/tainj/distributed_calculator2/pkg/logger"
 )
@@ -17,12 +19,51 @@ func NewRedisResultRepository(cache *cache.CACHE, logger logger.Logger) *RedisRe
 	return &RedisResultRepository{cache: cache, logger: logger}
 }
 
-func (r *RedisResultRepository) SetResult(ctx context.Context, variable string, result float64) error {
+func (r *RedisResultRepository) SetResult(ctx context.Context, variable string, result calculator.Value) error {
 	err := r.cache.SetByKey(ctx, fmt.Sprintf("result:%s", variable), result)
 	if err != nil {
 		return fmt.Errorf("repository.SetResult: %w", err)
 	}
 
-	r.logger.Debug(ctx, "set result", "variable", variable, "result", result)
+	r.logger.Debug(ctx, "set result", "variable", variable, "result", result.String())
 	return nil
 }
+
+// DeleteResults removes results of the variables, missing ones are skipped
+func (r *RedisResultRepository) DeleteResults(ctx context.Context, variables ...string) error {
+	if len(variables) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(variables))
+	for _, variable := range variables {
+		keys = append(keys, fmt.Sprintf("result:%s", variable))
+	}
+	if err := r.cache.Client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("repository.DeleteResults: %w", err)
+	}
+
+	r.logger.Debug(ctx, "deleted results", "count", len(keys))
+	return nil
+}
+
+// MissingResults returns the variables that have no result, in their order
+func (r *RedisResultRepository) MissingResults(ctx context.Context, variables ...string) ([]string, error) {
+	pipe := r.cache.Client.Pipeline()
+	exists := make([]*redis.IntCmd, 0, len(variables))
+	for _, variable := range variables {
+		exists = append(exists, pipe.Exists(ctx, fmt.Sprintf("result:%s", variable)))
+	}
+	if len(exists) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("repository.MissingResults: %w", err)
+		}
+	}
+
+	missing := make([]string, 0)
+	for i, cmd := range exists {
+		if cmd.Val() == 0 {
+			missing = append(missing, variables[i])
+		}
+	}
+	return missing, nil
+}
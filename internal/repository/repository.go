@@ -2,20 +2,79 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
 )
 
 type VariableRepository interface {
-	SetResult(ctx context.Context, variable string, result float64) error
+	SetResult(ctx context.Context, variable string, result calculator.Value) error
+	DeleteResults(ctx context.Context, variables ...string) error
+	MissingResults(ctx context.Context, variables ...string) ([]string, error)
+}
+
+type CancellationRepository interface {
+	Cancel(ctx context.Context, exampleID string) error
+	IsCancelled(ctx context.Context, exampleID string) (bool, error)
 }
 
 type ExampleRepository interface {
 	SaveExample(ctx context.Context, example *models.Example) error
-	UpdateExample(ctx context.Context, exampleId string, result float64) error
-	UpdateExampleWithError(ctx context.Context, exampleID, errorMsg string) error
-	GetResult(ctx context.Context, exampleID string) (float64, error)
+	UpdateExample(ctx context.Context, exampleId string, result calculator.Value) error
+	UpdateExampleWithError(ctx context.Context, exampleID, errorMsg string) (bool, error)
+	ReopenExample(ctx context.Context, exampleID, errorMsg string) (bool, error)
+	CancelExample(ctx context.Context, exampleID string) (bool, error)
+	ExpireExamples(ctx context.Context, errorMsg string) ([]string, error)
+	ClaimStuckExamples(ctx context.Context, age time.Duration, limit int) ([]models.Example, error)
+	GetResult(ctx context.Context, exampleID string) (calculator.Value, error)
 	GetExamplesByUserID(ctx context.Context, userID string) ([]models.Example, error)
+	GetExampleByID(ctx context.Context, exampleID string) (*models.Example, error)
+	GetLastExample(ctx context.Context, userID string) (*models.Example, error)
+	GetDependents(ctx context.Context, exampleID string) ([]models.Example, error)
+	TakeHeldTasks(ctx context.Context, exampleID string) ([]*models.Task, bool, error)
+	GetTasks(ctx context.Context, exampleID string) ([]*models.Task, error)
+	GetExamplesByParentID(ctx context.Context, parentID string) ([]models.Example, error)
+	MarkCalculated(ctx context.Context, exampleID string) error
+	SaveStep(ctx context.Context, task models.Task, args []calculator.Value, result calculator.Value) error
+	GetSteps(ctx context.Context, exampleID string) ([]models.Step, error)
+}
+
+type PendingTaskRepository interface {
+	SavePendingTasks(ctx context.Context, exampleID string, tasks []models.PendingTask) error
+	CompleteTask(ctx context.Context, exampleID, variable string, send func([]*models.Task) error) error
+	DropPendingTasks(ctx context.Context, exampleID string) error
+}
+
+type DeadLetterRepository interface {
+	SaveDeadLetter(ctx context.Context, letter models.DeadLetter) error
+	GetDeadLetters(ctx context.Context, limit int) ([]models.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+	MarkReplayed(ctx context.Context, id string) error
+}
+
+type LockRepository interface {
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, owner string) error
+}
+
+type UserVariableRepository interface {
+	SetVariable(ctx context.Context, userID string, variable models.UserVariable) (*models.UserVariable, error)
+	GetVariables(ctx context.Context, userID string) ([]models.UserVariable, error)
+	DeleteVariable(ctx context.Context, userID, name string) (bool, error)
+}
+
+type UserFunctionRepository interface {
+	SetFunction(ctx context.Context, userID string, function models.UserFunction) (*models.UserFunction, error)
+	GetFunctions(ctx context.Context, userID string) ([]models.UserFunction, error)
+	DeleteFunction(ctx context.Context, userID, name string) (bool, error)
+}
+
+type WasmModuleRepository interface {
+	SaveModule(ctx context.Context, module *models.WasmModule) error
+	GetModules(ctx context.Context) ([]models.WasmModule, error)
+	GetModuleCode(ctx context.Context, name string) ([]byte, string, error)
+	DeleteModule(ctx context.Context, name string) (bool, error)
 }
 
 type UserRepository interface {
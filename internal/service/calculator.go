@@ -4,111 +4,409 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tainj/distributed_calculator2/internal/auth"
+	"github.com/tainj/distributed_calculator2/internal/dependency"
 	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/internal/operations"
+	"github.com/tainj/distributed_calculator2/internal/orchestrator"
+	"github.com/tainj/distributed_calculator2/internal/rates"
 	repo "github.com/tainj/distributed_calculator2/internal/repository"
+	"github.com/tainj/distributed_calculator2/internal/valueprovider"
 	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/logger"
-	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
 )
 
 // calculator service — orchestrator
 // sends tasks to Kafka, saves examples
 type CalculatorService struct {
-	userRepo     repo.UserRepository
-	repoExamples repo.ExampleRepository
-	kafkaQueue   kafka.TaskQueue
-	jwtService   auth.JWTService
-	logger       logger.Logger
+	userRepo      repo.UserRepository
+	repoExamples  repo.ExampleRepository
+	variables     repo.UserVariableRepository
+	functions     repo.UserFunctionRepository
+	deadLetters   repo.DeadLetterRepository
+	cancellations repo.CancellationRepository
+	results       repo.VariableRepository // results of tasks in Redis
+	orchestrator  *orchestrator.Orchestrator
+	jwtService    auth.JWTService
+	rates         rates.Provider
+	limits        map[models.Role]calculator.Limits
+	deadlines     orchestrator.DeadlineConfig
+	values        valueprovider.Provider
+	releaser      *dependency.Releaser
+	operations    *operations.Registry
+	logger        logger.Logger
 }
 
 // newcalculator service
 func NewCalculatorService(
 	userRepo repo.UserRepository,
 	exampleRepo repo.ExampleRepository,
+	variableRepo repo.UserVariableRepository,
+	functionRepo repo.UserFunctionRepository,
+	deadLetterRepo repo.DeadLetterRepository,
+	cancellationRepo repo.CancellationRepository,
+	resultRepo repo.VariableRepository,
 	jwtService auth.JWTService,
-	kafkaQueue kafka.TaskQueue,
+	taskOrchestrator *orchestrator.Orchestrator,
+	rateProvider rates.Provider,
+	limits map[models.Role]calculator.Limits,
+	deadlineConfig orchestrator.DeadlineConfig,
+	values valueprovider.Provider,
+	releaser *dependency.Releaser,
+	operationRegistry *operations.Registry,
 	logger logger.Logger,
 ) *CalculatorService {
 	return &CalculatorService{
-		kafkaQueue:   kafkaQueue,
-		repoExamples: exampleRepo,
-		userRepo:     userRepo,
-		jwtService:   jwtService,
-		logger:       logger.With("layer", "service"),
+		orchestrator:  taskOrchestrator,
+		repoExamples:  exampleRepo,
+		variables:     variableRepo,
+		functions:     functionRepo,
+		deadLetters:   deadLetterRepo,
+		cancellations: cancellationRepo,
+		results:       resultRepo,
+		userRepo:      userRepo,
+		jwtService:    jwtService,
+		rates:         rateProvider,
+		limits:        limits,
+		deadlines:     deadlineConfig,
+		values:        values,
+		releaser:      releaser,
+		operations:    operationRegistry,
+		logger:        logger.With("layer", "service"),
 	}
 }
 
 // calculate — starts the calculation of the expression
 func (s *CalculatorService) Calculate(ctx context.Context, example *models.Example) (*models.Example, error) {
 	s.logger.Debug(ctx, "calculate request received", "example_id", example.ID, "user_id", example.UserID, "expression", example.Expression)
+
+	syntax, err := calculator.ParseSyntax(example.Syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3,14 and 1 000 of the locale become 3.14 and 1000
+	infix, errLocale := canonical(example, syntax)
+	if errors.Is(errLocale, calculator.ErrUnknownLocale) {
+		return nil, errLocale
+	}
+
+	// too big expressions are rejected before anything is saved
+	limits, err := s.limitsFor(ctx, example.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("calculate: %w", err)
+	}
+	if err := limits.CheckInput(infix); err != nil {
+		return nil, err
+	}
+	deadline, err := s.deadlineOf(example.Deadline)
+	if err != nil {
+		return nil, err
+	}
+
 	exampleID := uuid.New().String()
 
 	resultExample := &models.Example{
 		ID:         exampleID,
-		Expression: example.Expression,
+		Expression: infix,
 		UserID:     example.UserID,
+		Seed:       seedOf(example),
+		IEEE:       example.IEEE,
+		Syntax:     string(syntax),
+		Locale:     example.Locale,
+		Deadline:   deadline,
+	}
+	if errLocale != nil {
+		return s.saveWithError(ctx, resultExample, errLocale)
+	}
+
+	// convert every statement to Polish notation and plan its steps,
+	// a plain expression is a script of one statement
+	plan, err := calculator.PlanScript(infix, syntax, calculator.Scope{})
+	if err != nil {
+		return s.saveWithError(ctx, resultExample, err)
+	}
+
+	// names the script does not assign are references to earlier examples
+	// and saved variables of the user, unknown functions are the functions
+	// the user defined; values and definitions are kept with the example
+	if len(plan.Identifiers) > 0 || len(plan.Functions) > 0 {
+		var scope calculator.Scope
+		var functions []models.UserFunction
+		if len(plan.Functions) > 0 {
+			if functions, err = s.functions.GetFunctions(ctx, example.UserID); err != nil {
+				return nil, fmt.Errorf("calculate: %w", err)
+			}
+			scope.Functions = definitions(functions)
+		}
+		if len(plan.Identifiers) > 0 {
+			scope.Values, err = s.inputs(ctx, resultExample, plan.Identifiers)
+			if errors.Is(err, calculator.ErrUnknownReference) {
+				return s.saveWithError(ctx, resultExample, err)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("calculate: %w", err)
+			}
+		}
+		plan, err = calculator.PlanScript(infix, syntax, scope)
+		if errors.Is(err, calculator.ErrLimitExceeded) {
+			return nil, err // functions nested too deep or expanded too much
+		}
+		if err != nil {
+			return s.saveWithError(ctx, resultExample, err)
+		}
+		resultExample.UserVariables = userVariables(plan.Inputs)
+		resultExample.UserFunctions = usedFunctions(functions, plan.Expanded)
+	}
+	if names := plan.Identifiers; len(names) > 0 {
+		return s.saveWithError(ctx, resultExample, fmt.Errorf("%w: %s", calculator.ErrUnknownVariable, names[0]))
+	}
+	if len(plan.Functions) > 0 {
+		// the rest are custom operations of uploaded modules
+		names, err := s.operations.Unknown(ctx, plan.Functions)
+		if err != nil {
+			return nil, fmt.Errorf("calculate: %w", err)
+		}
+		if len(names) > 0 {
+			return s.saveWithError(ctx, resultExample, fmt.Errorf("%w: %s", calculator.ErrUnknownFunction, names[0]))
+		}
+	}
+	table, err := s.ratesFor(ctx, resultExample, plan.Currencies)
+	if err != nil {
+		return s.saveWithError(ctx, resultExample, err)
+	}
+
+	if err := limits.CheckTasks(plan.Tasks); err != nil {
+		return nil, err
+	}
+
+	resultExample.Bindings = plan.Bindings
+	if err := s.dispatch(ctx, resultExample, plan.Tasks, plan.Variable, table); err != nil {
+		return nil, fmt.Errorf("calculate: %w", err)
 	}
+	s.logger.Debug(ctx, "example saved and tasks sent to kafka", "example_id", resultExample.ID)
+	return resultExample, nil
+}
+
+// canonical - the expression in the syntax of the calculator, the original
+// one if the locale can't read it
+func canonical(example *models.Example, syntax calculator.Syntax) (string, error) {
+	locale, err := calculator.LocaleByName(example.Locale)
+	if err != nil {
+		return example.Expression, err
+	}
+	infix, err := locale.Canonical(example.Expression, syntax)
+	if err != nil {
+		return example.Expression, err
+	}
+	return infix, nil
+}
 
-	// creating an expression parser
-	expr := calculator.NewExpression(example.Expression)
+// saveWithError - saves an example that can't be calculated
+func (s *CalculatorService) saveWithError(ctx context.Context, example *models.Example, err error) (*models.Example, error) {
+	errString := err.Error()
+	example.Error = &errString
 
-	// convert to Polish notation
-	if _, err := expr.Convert(); err != nil {
-		errString := err.Error()
-		resultExample.Error = &errString
+	s.logger.Warn(ctx, "saving example with error",
+		"exampleId", example.ID,
+		"expression", example.Expression,
+		"error", example.Error,
+	)
+
+	if errSave := s.repoExamples.SaveExample(ctx, example); errSave != nil {
+		return nil, fmt.Errorf("calculate: save example: %v", errSave)
+	}
+	return example, nil
+}
 
-		s.logger.Warn(ctx, "saving example with error",
-			"exampleId", resultExample.ID,
-			"expression", resultExample.Expression,
-			"error", resultExample.Error,
-		)
+// ratesFor - currency rates for an expression with money, nil without money.
+// The snapshot ID is recorded on the example.
+func (s *CalculatorService) ratesFor(ctx context.Context, example *models.Example, codes []string) (map[string]float64, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
 
-		if errSave := s.repoExamples.SaveExample(ctx, resultExample); errSave != nil {
-			return nil, fmt.Errorf("calculate: save example: %v", errSave)
+	snapshot, err := s.rates.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		if _, ok := snapshot.Rates[code]; !ok {
+			return nil, fmt.Errorf("%w: %s", calculator.ErrUnknownCurrency, code)
 		}
-		return resultExample, nil
 	}
 
-	// counting steps and the final variable
-	results, variable := expr.Calculate()
+	example.RatesSnapshot = &snapshot.ID
+	return snapshot.Rates, nil
+}
+
+// limitsFor - limits of the role of the user, roles without their own
+// limits get the limits of users
+func (s *CalculatorService) limitsFor(ctx context.Context, userID string) (calculator.Limits, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return calculator.Limits{}, fmt.Errorf("load user: %w", err)
+	}
+	if limits, ok := s.limits[user.Role]; ok {
+		return limits, nil
+	}
+	return s.limits[models.UserRole], nil
+}
 
+// dispatch - saves the example with its steps and sends each step
+// to kafka. Conversions of money get the rates. Steps of an example that
+// uses results of examples not calculated yet are held, see dependency.Releaser.
+func (s *CalculatorService) dispatch(ctx context.Context, example *models.Example, results []*models.Task, variable string, table map[string]float64) error {
 	// filling in the results
-	resultExample.SimpleExamples = results
-	resultExample.Response = variable // was missing this!
+	example.SimpleExamples = results
+	example.Response = variable
+	held := len(example.DependsOn) > 0
 
-	if err := s.repoExamples.SaveExample(ctx, resultExample); err != nil {
-		return nil, fmt.Errorf("calculate: save example: %v", err)
+	// a single number - the answer is ready, workers are not needed;
+	// a script may end with a literal after statements that need them
+	var literal *calculator.Value
+	value, ok := calculator.ParseLiteral(variable)
+	if len(results) == 0 && !ok && !held {
+		// the result of a calculated example as it is: ans
+		resolved, err := s.values.Resolve(ctx, variable)
+		if err != nil {
+			return fmt.Errorf("parse single operand %q: %w", variable, err)
+		}
+		value, ok = resolved, true
+	}
+	if ok {
+		if number, exact := value.Exact(); exact {
+			example.Result = &number
+		} else {
+			literal = &value
+		}
 	}
 
-	// send each step to kafka
+	tasks := make([]*models.Task, 0, len(results))
 	for i, task := range results {
 		kafkaTask := &models.Task{
 			Num1:      task.Num1,
 			Num2:      task.Num2,
 			Sign:      task.Sign,
+			Args:      task.Args,
+			Seed:      *example.Seed,
+			IEEE:      example.IEEE,
 			Variable:  task.Variable,
-			ExampleID: exampleID,
+			ExampleID: example.ID,
 			Index:     i,
 			IsFinal:   task.Variable == variable,
+			Deadline:  example.Deadline,
 		}
 
-		if err := s.kafkaQueue.SendTask(kafkaTask); err != nil {
-			return nil, fmt.Errorf("failed to send task to kafka: %w", err)
+		if task.Sign == "in" {
+			kafkaTask.Rates = table
 		}
+		tasks = append(tasks, kafkaTask)
 	}
-	s.logger.Debug(ctx, "example saved and tasks sent to kafka", "example_id", resultExample.ID)
-	return resultExample, nil
+	example.TotalTasks = len(tasks)
+	example.Tasks = tasks
+	if held {
+		example.HeldTasks = tasks
+	}
+
+	if err := s.repoExamples.SaveExample(ctx, example); err != nil {
+		return fmt.Errorf("save example: %v", err)
+	}
+
+	// a long integer is stored like a result of workers, without losing digits
+	if literal != nil {
+		if err := s.repoExamples.UpdateExample(ctx, example.ID, *literal); err != nil {
+			return fmt.Errorf("save single operand: %w", err)
+		}
+		example.Calculated = true
+	}
+
+	if held {
+		// the examples it waits for may have finished before it was saved
+		return s.releaser.Release(ctx, example)
+	}
+
+	// steps are sent to kafka once their operands are calculated
+	if err := s.orchestrator.Dispatch(ctx, tasks); err != nil {
+		return fmt.Errorf("failed to send tasks to kafka: %w", err)
+	}
+	return nil
+}
+
+// deadlineOf - the deadline given in the request or CALCULATION_TIMEOUT
+// from now, nil if the timeout is 0. A deadline that has passed or is
+// later than CALCULATION_MAX_TIMEOUT is ErrInvalidDeadline.
+func (s *CalculatorService) deadlineOf(requested *time.Time) (*time.Time, error) {
+	now := time.Now()
+	if requested == nil {
+		if s.deadlines.Default <= 0 {
+			return nil, nil
+		}
+		deadline := now.Add(s.deadlines.Default)
+		return &deadline, nil
+	}
+	if !requested.After(now) {
+		return nil, fmt.Errorf("%w: %s has passed", models.ErrInvalidDeadline, requested.Format(time.RFC3339))
+	}
+	if s.deadlines.Max > 0 && requested.Sub(now) > s.deadlines.Max {
+		return nil, fmt.Errorf("%w: later than %s from now", models.ErrInvalidDeadline, s.deadlines.Max)
+	}
+	return requested, nil
+}
+
+// seedOf - seed given in the request or a new random one,
+// it is saved with the example to reproduce the result
+func seedOf(example *models.Example) *int64 {
+	if example.Seed != nil {
+		return example.Seed
+	}
+	seed := rand.Int64()
+	return &seed
+}
+
+// RefreshRates - reloads currency rates, admins only
+func (s *CalculatorService) RefreshRates(ctx context.Context, userID string) (*rates.Snapshot, error) {
+	if err := s.admin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.rates.Refresh(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("refresh rates: %w", err)
+	}
+	s.logger.Info(ctx, "currency rates refreshed", "snapshot", snapshot.ID, "currencies", len(snapshot.Rates), "user_id", userID)
+	return snapshot, nil
 }
 
 // GetResult - gets final result by id
-func (s *CalculatorService) GetResult(ctx context.Context, exampleID string) (float64, error) {
+func (s *CalculatorService) GetResult(ctx context.Context, exampleID string) (calculator.Value, error) {
 	return s.repoExamples.GetResult(ctx, exampleID)
 }
 
+// Bindings - values of the names assigned by the script of the example,
+// in the order of assignment. Values are read from the results of workers,
+// nil while the task of a name is not calculated.
+func (s *CalculatorService) Bindings(ctx context.Context, exampleID string) ([]calculator.BoundValue, error) {
+	example, err := s.repoExamples.GetExampleByID(ctx, exampleID)
+	if err != nil {
+		return nil, fmt.Errorf("bindings: %w", err)
+	}
+
+	values := make([]calculator.BoundValue, 0, len(example.Bindings))
+	for _, binding := range example.Bindings {
+		bound := calculator.BoundValue{Name: binding.Name}
+		if value, err := s.values.Resolve(ctx, binding.Ref); err == nil {
+			bound.Value = &value
+		}
+		values = append(values, bound)
+	}
+	return values, nil
+}
+
 // Register - registers a new user
 func (s *CalculatorService) Register(ctx context.Context, userRequest *models.UserCredentials) (*models.User, error) {
 	// check if email already exists
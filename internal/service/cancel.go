@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// Cancel - stops the calculation of the example of the user, a sweep is
+// cancelled with all its points. False if the example is already calculated.
+//
+// The example is marked as cancelled in Redis first, so workers skip its
+// tasks that are already in kafka; tasks waiting for others are dropped and
+// results of all its tasks are removed from Redis. Results that workers
+// report later are dropped by the consumer.
+func (s *CalculatorService) Cancel(ctx context.Context, userID, exampleID string) (bool, error) {
+	example, err := s.repoExamples.GetExampleByID(ctx, exampleID)
+	if err != nil || example.UserID != userID {
+		return false, ErrExampleNotFound
+	}
+	if example.Calculated {
+		return false, nil
+	}
+
+	if example.ParentID == nil {
+		points, err := s.repoExamples.GetExamplesByParentID(ctx, exampleID)
+		if err != nil {
+			return false, fmt.Errorf("cancel: %w", err)
+		}
+		for _, point := range points {
+			if point.Calculated {
+				continue
+			}
+			if _, err := s.cancel(ctx, point.ID); err != nil {
+				return false, err
+			}
+		}
+	}
+	return s.cancel(ctx, exampleID)
+}
+
+// cancel - cancels one example, examples using its result fail
+func (s *CalculatorService) cancel(ctx context.Context, exampleID string) (bool, error) {
+	if err := s.cancellations.Cancel(ctx, exampleID); err != nil {
+		return false, fmt.Errorf("cancel %s: %w", exampleID, err)
+	}
+	cancelled, err := s.repoExamples.CancelExample(ctx, exampleID)
+	if err != nil {
+		return false, fmt.Errorf("cancel %s: %w", exampleID, err)
+	}
+	if !cancelled {
+		return false, nil // calculated in between
+	}
+
+	if err := s.orchestrator.Failed(ctx, exampleID); err != nil {
+		return false, fmt.Errorf("cancel %s: %w", exampleID, err)
+	}
+	variables, err := s.variablesOf(ctx, exampleID)
+	if err != nil {
+		return false, fmt.Errorf("cancel %s: %w", exampleID, err)
+	}
+	if err := s.results.DeleteResults(ctx, variables...); err != nil {
+		return false, fmt.Errorf("cancel %s: %w", exampleID, err)
+	}
+	if err := s.releaser.Finished(ctx, exampleID); err != nil {
+		s.logger.Error(ctx, "failed to release dependent examples", "example_id", exampleID, "error", err)
+	}
+
+	s.logger.Info(ctx, "example cancelled", "example_id", exampleID, "results_deleted", len(variables))
+	return true, nil
+}
+
+// variablesOf - variables of all tasks of the example: workers may still
+// save results of tasks that are not calculated yet. Examples sent before
+// tasks were saved have only their calculated steps.
+func (s *CalculatorService) variablesOf(ctx context.Context, exampleID string) ([]string, error) {
+	tasks, err := s.repoExamples.GetTasks(ctx, exampleID)
+	if err != nil {
+		return nil, err
+	}
+	if tasks != nil {
+		variables := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			variables = append(variables, task.Variable)
+		}
+		return variables, nil
+	}
+
+	steps, err := s.repoExamples.GetSteps(ctx, exampleID)
+	if err != nil {
+		return nil, err
+	}
+	variables := make([]string, 0, len(steps))
+	for _, step := range steps {
+		variables = append(variables, step.Variable)
+	}
+	return variables, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+)
+
+const (
+	// defaultDeadLetters - letters listed when the request sets no limit
+	defaultDeadLetters = 100
+	// maxDeadLetters - letters listed at most
+	maxDeadLetters = 1000
+)
+
+// GetDeadLetters - the latest tasks workers gave up on, admins only
+func (s *CalculatorService) GetDeadLetters(ctx context.Context, userID string, limit int) ([]models.DeadLetter, error) {
+	if err := s.admin(ctx, userID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeadLetters
+	}
+	letters, err := s.deadLetters.GetDeadLetters(ctx, min(limit, maxDeadLetters))
+	if err != nil {
+		return nil, fmt.Errorf("dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// ReplayDeadLetter - sends the task of the letter to workers again, admins
+// only. The example is reopened if it still has the error of this letter;
+// examples that failed because of it are not.
+func (s *CalculatorService) ReplayDeadLetter(ctx context.Context, userID, id string) (*models.DeadLetter, error) {
+	if err := s.admin(ctx, userID); err != nil {
+		return nil, err
+	}
+	letter, err := s.deadLetters.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	if letter == nil {
+		return nil, fmt.Errorf("%w: no dead letter %s", models.ErrNotReplayable, id)
+	}
+	if letter.ExampleID == "" {
+		return nil, fmt.Errorf("%w: the message is not a task", models.ErrNotReplayable)
+	}
+
+	reopened, err := s.repoExamples.ReopenExample(ctx, letter.ExampleID, letter.ExampleError())
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	if err := s.orchestrator.Resend([]byte(letter.Payload)); err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	if err := s.deadLetters.MarkReplayed(ctx, letter.ID); err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	s.logger.Info(ctx, "dead letter replayed", "id", letter.ID, "example_id", letter.ExampleID, "reopened", reopened, "user_id", userID)
+	return s.deadLetters.GetDeadLetter(ctx, letter.ID)
+}
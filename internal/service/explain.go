@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+var ErrExampleNotFound = errors.New("example not found")
+
+// Explain — steps of the example saved by workers, each with the part of the
+// expression it calculates: Step 1: 2 * 2 = 4, Step 2: 2 + 4 = 6
+func (s *CalculatorService) Explain(ctx context.Context, userID, exampleID string) (*models.Explanation, error) {
+	example, err := s.repoExamples.GetExampleByID(ctx, exampleID)
+	if err != nil || example.UserID != userID {
+		return nil, ErrExampleNotFound
+	}
+
+	explanation := &models.Explanation{
+		Steps:     make([]models.ExplainStep, 0),
+		Completed: example.Calculated,
+		Error:     example.Error,
+	}
+
+	// planning is deterministic: the tasks are the same as when the example
+	// was sent, saved steps are matched to them by index
+	tasks, ok := s.replan(example)
+	if !ok {
+		return explanation, nil // nothing was calculated, Error says why
+	}
+	sources := calculator.Sources(tasks)
+	locale, _ := calculator.LocaleByName(example.Locale) // saved only if it is known
+
+	steps, err := s.repoExamples.GetSteps(ctx, exampleID)
+	if err != nil {
+		return nil, fmt.Errorf("explain: %w", err)
+	}
+	for _, step := range steps {
+		if step.Order >= len(sources) {
+			continue // planned by another version of the calculator
+		}
+		if sources[step.Order] == "" {
+			continue // internal step of an aggregate, shown by the aggregate
+		}
+		number := len(explanation.Steps) + 1
+		operation := calculator.FormatStep(step.Sign, step.Operands)
+		if calculator.IsInternal(step.Sign) {
+			operation = sources[step.Order] // _mean of moments is mean of the items
+		}
+		expression := locale.Localize(sources[step.Order])
+		operation = locale.Localize(operation)
+		result := locale.Localize(step.ResultText)
+		explanation.Steps = append(explanation.Steps, models.ExplainStep{
+			Number:     number,
+			Expression: expression,
+			Operation:  operation,
+			Result:     result,
+			Text:       fmt.Sprintf("Step %d: %s = %s", number, operation, result),
+		})
+	}
+	return explanation, nil
+}
+
+// replan - tasks of the example planned again, false if it can't be parsed
+func (s *CalculatorService) replan(example *models.Example) ([]*models.Task, bool) {
+	syntax := calculator.Syntax(example.Syntax)
+	if len(example.Variables) == 0 {
+		plan, err := calculator.PlanScript(example.Expression, syntax, calculator.Scope{
+			Values:    snapshot(example),
+			Functions: definitions(example.UserFunctions),
+		})
+		if err != nil {
+			return nil, false
+		}
+		return plan.Tasks, true
+	}
+
+	// a point of a sweep
+	expr := calculator.NewExpression(example.Expression)
+	expr.Syntax = syntax
+	if _, err := expr.Convert(); err != nil {
+		return nil, false
+	}
+	expr, _, err := expr.Expand(definitions(example.UserFunctions))
+	if err != nil {
+		return nil, false
+	}
+	tasks, _ := expr.Substitute(example.Variables).Substitute(example.UserVariables).Calculate()
+	return tasks, true
+}
+
+// snapshot - refs of the inputs as they were when the example was sent
+func snapshot(example *models.Example) map[string]string {
+	values := make(map[string]string, len(example.UserVariables)+len(example.References))
+	for name, value := range example.UserVariables {
+		values[name] = calculator.NumberLiteral(value)
+	}
+	for _, reference := range example.References {
+		values[reference.Name] = reference.Ref
+	}
+	return values
+}
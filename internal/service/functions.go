@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+// DefineFunction - saves a function for later expressions of the user:
+// f(x, y) = x^2 + 3*y. Calls are inlined when an expression is sent and
+// examples keep the definitions they were calculated with.
+func (s *CalculatorService) DefineFunction(ctx context.Context, userID, definition string) (*models.UserFunction, error) {
+	def, err := calculator.ParseDefinition(definition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidFunction, err)
+	}
+	if err := checkNameLength(def.Name); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidFunction, err)
+	}
+
+	functions, err := s.functions.GetFunctions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("define function: %w", err)
+	}
+	defs := definitions(functions)
+	if err := def.Validate(defs); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidFunction, err)
+	}
+
+	// a new definition must not break the functions that call it
+	defs[def.Name] = def
+	for _, other := range defs {
+		if other.Name == def.Name {
+			continue
+		}
+		if err := other.Validate(defs); err != nil {
+			return nil, fmt.Errorf("%w: %s would break %s: %w", models.ErrInvalidFunction, def.Name, other.Name, err)
+		}
+	}
+
+	saved, err := s.functions.SetFunction(ctx, userID, models.UserFunction{Name: def.Name, Params: def.Params, Body: def.Body})
+	if err != nil {
+		return nil, fmt.Errorf("define function: %w", err)
+	}
+	s.logger.Debug(ctx, "function saved", "user_id", userID, "name", def.Name)
+	return saved, nil
+}
+
+// GetFunctions - functions of the user sorted by name
+func (s *CalculatorService) GetFunctions(ctx context.Context, userID string) ([]models.UserFunction, error) {
+	functions, err := s.functions.GetFunctions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get functions: %w", err)
+	}
+	return functions, nil
+}
+
+// DeleteFunction - removes a function, false if there was none.
+// Functions that call it fail when they are used.
+func (s *CalculatorService) DeleteFunction(ctx context.Context, userID, name string) (bool, error) {
+	if err := checkNameLength(name); err != nil {
+		return false, fmt.Errorf("%w: %w", models.ErrInvalidFunction, err)
+	}
+	deleted, err := s.functions.DeleteFunction(ctx, userID, name)
+	if err != nil {
+		return false, fmt.Errorf("delete function: %w", err)
+	}
+	return deleted, nil
+}
+
+// definitions - functions by name in the form of the calculator
+func definitions(functions []models.UserFunction) map[string]calculator.FunctionDef {
+	defs := make(map[string]calculator.FunctionDef, len(functions))
+	for _, function := range functions {
+		defs[function.Name] = calculator.FunctionDef{Name: function.Name, Params: function.Params, Body: function.Body}
+	}
+	return defs
+}
+
+// usedFunctions - the functions with the given names, as they are kept
+// with an example
+func usedFunctions(functions []models.UserFunction, names []string) []models.UserFunction {
+	var used []models.UserFunction
+	for _, function := range functions {
+		for _, name := range names {
+			if function.Name == name {
+				used = append(used, function)
+			}
+		}
+	}
+	return used
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+)
+
+// UploadModule - saves a WebAssembly module with custom operations,
+// admins only. Its exported functions of numbers can be called in
+// expressions by name, workers run them in a sandbox.
+func (s *CalculatorService) UploadModule(ctx context.Context, userID string, module *models.WasmModule) (*models.WasmModule, error) {
+	if err := s.admin(ctx, userID); err != nil {
+		return nil, err
+	}
+	module.UploadedBy = userID
+	if err := s.operations.Upload(ctx, module); err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// GetModules - uploaded modules and their operations
+func (s *CalculatorService) GetModules(ctx context.Context) ([]models.WasmModule, error) {
+	return s.operations.Modules(ctx)
+}
+
+// DeleteModule - removes a module, admins only
+func (s *CalculatorService) DeleteModule(ctx context.Context, userID, name string) (bool, error) {
+	if err := s.admin(ctx, userID); err != nil {
+		return false, err
+	}
+	deleted, err := s.operations.Delete(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	s.logger.Info(ctx, "module deleted", "name", name, "deleted", deleted, "user_id", userID)
+	return deleted, nil
+}
+
+// admin - ErrForbidden unless the user is an admin
+func (s *CalculatorService) admin(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("load user: %w", err)
+	}
+	if user.Role != models.AdminRole {
+		return models.ErrForbidden
+	}
+	return nil
+}
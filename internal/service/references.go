@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+// inputs - refs of the names an expression uses without assigning them:
+// references to earlier examples of the user (ans, @last, result("id"))
+// and saved variables. References are recorded on the example, examples
+// that are not calculated yet become its dependencies.
+func (s *CalculatorService) inputs(ctx context.Context, example *models.Example, names []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, name := range names {
+		if !calculator.IsReference(name) {
+			continue
+		}
+		referenced, err := s.reference(ctx, example.UserID, name)
+		if err != nil {
+			return nil, err
+		}
+
+		// a calculated number is substituted, other results are read by workers
+		ref := referenced.Response
+		if referenced.Calculated && referenced.Result != nil {
+			ref = calculator.NumberLiteral(*referenced.Result)
+		}
+		if !referenced.Calculated {
+			example.DependsOn = append(example.DependsOn, referenced.ID)
+		}
+		example.References = append(example.References, models.Reference{Name: name, ExampleID: referenced.ID, Ref: ref})
+		values[name] = ref
+	}
+
+	variables, err := s.variableValues(ctx, example.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range variables {
+		values[name] = calculator.NumberLiteral(value)
+	}
+	return values, nil
+}
+
+// reference - the example a reference points to; it must belong to the
+// user and have a result or be still calculated
+func (s *CalculatorService) reference(ctx context.Context, userID, name string) (*models.Example, error) {
+	var example *models.Example
+	var err error
+	if id := calculator.ReferenceID(name); id == "" {
+		example, err = s.repoExamples.GetLastExample(ctx, userID)
+	} else {
+		example, err = s.repoExamples.GetExampleByID(ctx, id)
+	}
+
+	switch {
+	case err != nil || example.UserID != userID:
+		return nil, fmt.Errorf("%w: %s, no such example", calculator.ErrUnknownReference, name)
+	case example.Error != nil:
+		return nil, fmt.Errorf("%w: %s failed: %s", calculator.ErrUnknownReference, name, *example.Error)
+	case example.Response == "":
+		return nil, fmt.Errorf("%w: %s has no result", calculator.ErrUnknownReference, name) // a sweep
+	}
+	return example, nil
+}
+
+// userVariables - values of saved variables among the inputs of a plan,
+// references are not variables
+func userVariables(inputs map[string]string) map[string]float64 {
+	var variables map[string]float64
+	for name, ref := range inputs {
+		if calculator.IsReference(name) {
+			continue
+		}
+		value, err := strconv.ParseFloat(ref, 64)
+		if err != nil {
+			continue
+		}
+		if variables == nil {
+			variables = make(map[string]float64)
+		}
+		variables[name] = value
+	}
+	return variables
+}
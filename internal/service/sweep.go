@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+const (
+	maxSweepVariables = 2
+	maxSweepPoints    = 10000 // each point is a separate example with its own tasks
+)
+
+var (
+	ErrSweepNotFound = errors.New("sweep not found")
+	ErrInvalidSweep  = errors.New("invalid sweep")
+)
+
+// Sweep — evaluates the expression on every point of the grid.
+// One parent example is created, each grid point becomes its child example
+func (s *CalculatorService) Sweep(ctx context.Context, example *models.Example, ranges []models.SweepRange) (*models.Example, error) {
+	s.logger.Debug(ctx, "sweep request received", "user_id", example.UserID, "expression", example.Expression, "ranges", len(ranges))
+
+	points, err := sweepGrid(ranges)
+	if err != nil {
+		return nil, err
+	}
+
+	syntax, err := calculator.ParseSyntax(example.Syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	infix, errLocale := canonical(example, syntax)
+	if errors.Is(errLocale, calculator.ErrUnknownLocale) {
+		return nil, errLocale
+	}
+
+	limits, err := s.limitsFor(ctx, example.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("sweep: %w", err)
+	}
+	if err := limits.CheckInput(infix); err != nil {
+		return nil, err
+	}
+
+	parent := &models.Example{
+		ID:         uuid.New().String(),
+		Expression: infix,
+		UserID:     example.UserID,
+		Seed:       seedOf(example), // shared by the points: same random numbers on every point
+		IEEE:       example.IEEE,
+		Syntax:     string(syntax),
+		Locale:     example.Locale,
+	}
+	if errLocale != nil {
+		return s.saveWithError(ctx, parent, errLocale)
+	}
+
+	// parse once, every point only substitutes values
+	expr := calculator.NewExpression(infix)
+	expr.Syntax = syntax
+	if _, err := expr.Convert(); err != nil {
+		return s.saveWithError(ctx, parent, err)
+	}
+
+	// functions of the user are inlined once for all points
+	if len(expr.UnknownFunctions()) > 0 {
+		functions, err := s.functions.GetFunctions(ctx, example.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("sweep: %w", err)
+		}
+		expanded, names, err := expr.Expand(definitions(functions))
+		if errors.Is(err, calculator.ErrLimitExceeded) {
+			return nil, err
+		}
+		if err != nil {
+			return s.saveWithError(ctx, parent, err)
+		}
+		expr = expanded
+		parent.UserFunctions = usedFunctions(functions, names)
+	}
+
+	// names that are not swept are saved variables of the user
+	var values map[string]float64
+	for _, name := range expr.Identifiers() {
+		if _, ok := points[0][name]; ok {
+			continue
+		}
+		if values == nil {
+			if values, err = s.variableValues(ctx, example.UserID); err != nil {
+				return nil, fmt.Errorf("sweep: %w", err)
+			}
+		}
+		value, ok := values[name]
+		if !ok {
+			return s.saveWithError(ctx, parent, fmt.Errorf("%w: %s", calculator.ErrUnknownVariable, name))
+		}
+		if parent.UserVariables == nil {
+			parent.UserVariables = make(map[string]float64)
+		}
+		parent.UserVariables[name] = value
+	}
+	expr = expr.Substitute(parent.UserVariables)
+	if names := expr.UnknownFunctions(); len(names) > 0 {
+		if names, err = s.operations.Unknown(ctx, names); err != nil {
+			return nil, fmt.Errorf("sweep: %w", err)
+		}
+		if len(names) > 0 {
+			return s.saveWithError(ctx, parent, fmt.Errorf("%w: %s", calculator.ErrUnknownFunction, names[0]))
+		}
+	}
+	table, err := s.ratesFor(ctx, parent, expr.Currencies())
+	if err != nil {
+		return s.saveWithError(ctx, parent, err)
+	}
+
+	// every point has the same number of steps, but the exponents
+	// depend on the values, so each point is checked on its own
+	type planned struct {
+		tasks    []*models.Task
+		variable string
+	}
+	plans := make([]planned, len(points))
+	for i, variables := range points {
+		tasks, variable := expr.Substitute(variables).Calculate()
+		if i == 0 && limits.MaxTasks > 0 && len(points)*len(tasks) > limits.MaxTasks {
+			return nil, fmt.Errorf("%w: sweep needs %d steps, at most %d are allowed",
+				calculator.ErrLimitExceeded, len(points)*len(tasks), limits.MaxTasks)
+		}
+		if err := limits.CheckTasks(tasks); err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		plans[i] = planned{tasks: tasks, variable: variable}
+	}
+
+	if err := s.repoExamples.SaveExample(ctx, parent); err != nil {
+		return nil, fmt.Errorf("sweep: save example: %w", err)
+	}
+
+	// points have the default deadline, the sweep is finished when they are
+	deadline, err := s.deadlineOf(nil)
+	if err != nil {
+		return nil, fmt.Errorf("sweep: %w", err)
+	}
+
+	for i, variables := range points {
+		child := &models.Example{
+			ID:            uuid.New().String(),
+			Expression:    infix,
+			UserID:        example.UserID,
+			ParentID:      &parent.ID,
+			Position:      i,
+			Variables:     variables,
+			Seed:          parent.Seed,
+			RatesSnapshot: parent.RatesSnapshot,
+			IEEE:          parent.IEEE,
+			Syntax:        parent.Syntax,
+			Locale:        parent.Locale,
+			UserVariables: parent.UserVariables,
+			UserFunctions: parent.UserFunctions,
+			Deadline:      deadline,
+		}
+		if err := s.dispatch(ctx, child, plans[i].tasks, plans[i].variable, table); err != nil {
+			return nil, fmt.Errorf("sweep: point %d: %w", i, err)
+		}
+	}
+
+	s.logger.Debug(ctx, "sweep saved and tasks sent to kafka", "example_id", parent.ID, "points", len(points))
+	return parent, nil
+}
+
+// GetSweepResult — returns the sweep and its points in grid order
+func (s *CalculatorService) GetSweepResult(ctx context.Context, userID, sweepID string) (*models.Example, []models.Example, error) {
+	parent, err := s.repoExamples.GetExampleByID(ctx, sweepID)
+	if err != nil || parent.UserID != userID {
+		return nil, nil, ErrSweepNotFound
+	}
+
+	points, err := s.repoExamples.GetExamplesByParentID(ctx, sweepID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sweep result: %w", err)
+	}
+
+	// the sweep itself has no result, it is finished when all points are
+	if !parent.Calculated && len(points) > 0 && allCalculated(points) {
+		if err := s.repoExamples.MarkCalculated(ctx, parent.ID); err != nil {
+			return nil, nil, fmt.Errorf("sweep result: %w", err)
+		}
+		parent.Calculated = true
+	}
+
+	return parent, points, nil
+}
+
+func allCalculated(examples []models.Example) bool {
+	for _, example := range examples {
+		if !example.Calculated {
+			return false
+		}
+	}
+	return true
+}
+
+// sweepGrid - all combinations of range values, the last range changes fastest
+func sweepGrid(ranges []models.SweepRange) ([]map[string]float64, error) {
+	if len(ranges) == 0 || len(ranges) > maxSweepVariables {
+		return nil, fmt.Errorf("%w: expected 1 or %d variables, got %d", ErrInvalidSweep, maxSweepVariables, len(ranges))
+	}
+
+	total := 1
+	seen := make(map[string]bool)
+	for _, r := range ranges {
+		if r.Variable == "" {
+			return nil, fmt.Errorf("%w: variable name is empty", ErrInvalidSweep)
+		}
+		if seen[r.Variable] {
+			return nil, fmt.Errorf("%w: variable %s is used twice", ErrInvalidSweep, r.Variable)
+		}
+		seen[r.Variable] = true
+
+		if r.Steps < 0 {
+			return nil, fmt.Errorf("%w: steps for %s must not be negative", ErrInvalidSweep, r.Variable)
+		}
+		total *= r.Steps + 1
+		if total > maxSweepPoints {
+			return nil, fmt.Errorf("%w: more than %d points", ErrInvalidSweep, maxSweepPoints)
+		}
+	}
+
+	points := []map[string]float64{{}}
+	for _, r := range ranges {
+		next := make([]map[string]float64, 0, len(points)*(r.Steps+1))
+		for _, point := range points {
+			for step := 0; step <= r.Steps; step++ {
+				values := make(map[string]float64, len(point)+1)
+				for name, value := range point {
+					values[name] = value
+				}
+				values[r.Variable] = rangeValue(r, step)
+				next = append(next, values)
+			}
+		}
+		points = next
+	}
+	return points, nil
+}
+
+// rangeValue - value of the variable on the given step, ends are exact
+func rangeValue(r models.SweepRange, step int) float64 {
+	if step == 0 {
+		return r.From
+	}
+	if step == r.Steps {
+		return r.To
+	}
+	return r.From + (r.To-r.From)*float64(step)/float64(r.Steps)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+// maxNameLength - names of variables and functions are VARCHAR(64)
+const maxNameLength = 64
+
+// SetVariable - saves a named value for later expressions of the user.
+// Examples keep the values they were calculated with, so changing
+// a variable does not change the history.
+func (s *CalculatorService) SetVariable(ctx context.Context, userID string, variable models.UserVariable) (*models.UserVariable, error) {
+	if err := calculator.CheckName(variable.Name); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidVariable, err)
+	}
+	if err := checkNameLength(variable.Name); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidVariable, err)
+	}
+	if math.IsInf(variable.Value, 0) || math.IsNaN(variable.Value) {
+		return nil, fmt.Errorf("%w: value of %s must be a finite number", models.ErrInvalidVariable, variable.Name)
+	}
+
+	saved, err := s.variables.SetVariable(ctx, userID, variable)
+	if err != nil {
+		return nil, fmt.Errorf("set variable: %w", err)
+	}
+	s.logger.Debug(ctx, "variable saved", "user_id", userID, "name", variable.Name)
+	return saved, nil
+}
+
+// GetVariables - saved variables of the user sorted by name
+func (s *CalculatorService) GetVariables(ctx context.Context, userID string) ([]models.UserVariable, error) {
+	variables, err := s.variables.GetVariables(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get variables: %w", err)
+	}
+	return variables, nil
+}
+
+// DeleteVariable - removes a saved variable, false if there was none
+func (s *CalculatorService) DeleteVariable(ctx context.Context, userID, name string) (bool, error) {
+	if err := checkNameLength(name); err != nil {
+		return false, fmt.Errorf("%w: %w", models.ErrInvalidVariable, err)
+	}
+	deleted, err := s.variables.DeleteVariable(ctx, userID, name)
+	if err != nil {
+		return false, fmt.Errorf("delete variable: %w", err)
+	}
+	return deleted, nil
+}
+
+// variableValues - saved variables of the user by name
+func (s *CalculatorService) variableValues(ctx context.Context, userID string) (map[string]float64, error) {
+	variables, err := s.variables.GetVariables(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("variables of the user: %w", err)
+	}
+	values := make(map[string]float64, len(variables))
+	for _, variable := range variables {
+		values[variable.Name] = variable.Value
+	}
+	return values, nil
+}
+
+// checkNameLength - a longer name of a variable or a function can't be
+// saved, so it can't be deleted either
+func checkNameLength(name string) error {
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return fmt.Errorf("name is longer than %d characters", maxNameLength)
+	}
+	return nil
+}
@@ -2,23 +2,47 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/tainj/distributed_calculator2/internal/auth"
 	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/internal/rates"
 	client "github.com/tainj/distributed_calculator2/pkg/api"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Service - business logic interface
 // to be able to mock in tests
 type Service interface {
 	Calculate(ctx context.Context, example *models.Example) (*models.Example, error)
-	GetResult(ctx context.Context, exampleID string) (float64, error)
+	GetResult(ctx context.Context, exampleID string) (calculator.Value, error)
+	Bindings(ctx context.Context, exampleID string) ([]calculator.BoundValue, error)
 	Register(ctx context.Context, user *models.UserCredentials) (*models.User, error)
 	Login(ctx context.Context, user *models.UserCredentials) (*models.LoginResponse, error)
 	GetExamplesByUserID(ctx context.Context, userID string) ([]models.Example, error)
+	Sweep(ctx context.Context, example *models.Example, ranges []models.SweepRange) (*models.Example, error)
+	GetSweepResult(ctx context.Context, userID, sweepID string) (*models.Example, []models.Example, error)
+	RefreshRates(ctx context.Context, userID string) (*rates.Snapshot, error)
+	SetVariable(ctx context.Context, userID string, variable models.UserVariable) (*models.UserVariable, error)
+	GetVariables(ctx context.Context, userID string) ([]models.UserVariable, error)
+	DeleteVariable(ctx context.Context, userID, name string) (bool, error)
+	DefineFunction(ctx context.Context, userID, definition string) (*models.UserFunction, error)
+	GetFunctions(ctx context.Context, userID string) ([]models.UserFunction, error)
+	DeleteFunction(ctx context.Context, userID, name string) (bool, error)
+	UploadModule(ctx context.Context, userID string, module *models.WasmModule) (*models.WasmModule, error)
+	GetModules(ctx context.Context) ([]models.WasmModule, error)
+	DeleteModule(ctx context.Context, userID, name string) (bool, error)
+	GetDeadLetters(ctx context.Context, userID string, limit int) ([]models.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, userID, id string) (*models.DeadLetter, error)
+	Explain(ctx context.Context, userID, exampleID string) (*models.Explanation, error)
+	Cancel(ctx context.Context, userID, exampleID string) (bool, error)
 }
 
 // CalculatorService — gRPC сервер
@@ -34,11 +58,25 @@ func NewCalculatorService(srv Service) *CalculatorService {
 
 // Calculate — обрабатывает запрос на вычисление
 func (s *CalculatorService) Calculate(ctx context.Context, req *client.CalculateRequest) (*client.CalculateResponse, error) {
+	deadline, err := deadlineOf(req.GetDeadline())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// вызываем бизнес-логику
 	resp, err := s.service.Calculate(ctx, &models.Example{
 		Expression: req.GetExpression(),
 		UserID:     auth.UserIDFromCtx(ctx), // берём user_id из контекста
+		Seed:       req.Seed,                // nil — сервис выберет сам
+		IEEE:       req.GetIeee(),           // Infinity и NaN вместо ошибок
+		Locale:     req.GetLocale(),         // 3,14 и 1 000 вместо 3.14 и 1000
+		Syntax:     req.GetSyntax(),         // инфиксная запись, RPN или S-выражение
+		Deadline:   deadline,                // nil — срок по умолчанию
 	})
+	if invalidRequest(err) {
+		// слишком большое выражение, неизвестная локаль или синтаксис — ничего не сохранено
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Calculate: %w", err)
 	}
@@ -46,7 +84,9 @@ func (s *CalculatorService) Calculate(ctx context.Context, req *client.Calculate
 	// извлекаем id
 	r := pointer.Get(resp)
 	return &client.CalculateResponse{
-		TaskId: r.ID,
+		TaskId:   r.ID,
+		Seed:     pointer.Get(r.Seed),
+		Deadline: timeOf(r.Deadline),
 	}, nil
 }
 
@@ -54,22 +94,391 @@ func (s *CalculatorService) Calculate(ctx context.Context, req *client.Calculate
 func (s *CalculatorService) GetResult(ctx context.Context, req *client.GetResultRequest) (*client.GetResultResponse, error) {
 	taskID := req.GetTaskId()
 
+	format, err := formatOf(req.GetFormat())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// значения имён скрипта — и при ошибке, чтобы было видно, что успело посчитаться
+	bindings := s.bindings(ctx, taskID, format)
+
 	// получаем результат
 	result, err := s.service.GetResult(ctx, taskID)
 	if err != nil {
 		// ошибка — кладём в oneof
 		return &client.GetResultResponse{
 			Result: &client.GetResultResponse_Error{
-				Error: err.Error(),
+				Error: format.Locale.Translate(err.Error()),
+			},
+			Bindings:  bindings,
+			Cancelled: errors.Is(err, models.ErrCancelled), // отменён пользователем
+		}, nil
+	}
+
+	// списки, матрицы и большие целые — текстом, чтобы не потерять цифры
+	number, ok := result.Exact()
+	if !ok {
+		return &client.GetResultResponse{
+			Result: &client.GetResultResponse_Text{
+				Text: result.String(),
 			},
+			Formatted: format.Value(result),
+			Bindings:  bindings,
 		}, nil
 	}
 
 	// успех — возвращаем значение
 	return &client.GetResultResponse{
 		Result: &client.GetResultResponse_Value{
-			Value: result,
+			Value: number,
 		},
+		Formatted: format.Value(result),
+		Bindings:  bindings,
+	}, nil
+}
+
+// Cancel — отменяет пример, воркеры пропускают его оставшиеся задачи
+func (s *CalculatorService) Cancel(ctx context.Context, req *client.CancelRequest) (*client.CancelResponse, error) {
+	cancelled, err := s.service.Cancel(ctx, auth.UserIDFromCtx(ctx), req.GetTaskId())
+	if err != nil {
+		// ошибка — в теле ответа, как в GetResult
+		return &client.CancelResponse{
+			Error: pointer.ToString(err.Error()),
+		}, nil
+	}
+	return &client.CancelResponse{Cancelled: cancelled}, nil
+}
+
+// bindings — значения имён, присвоенных скриптом; пустой список у обычных выражений
+func (s *CalculatorService) bindings(ctx context.Context, taskID string, format calculator.Format) []*client.NamedValue {
+	values, err := s.service.Bindings(ctx, taskID)
+	if err != nil {
+		return nil // пример не найден — об этом скажет GetResult
+	}
+	named := make([]*client.NamedValue, 0, len(values))
+	for _, value := range values {
+		item := &client.NamedValue{Name: value.Name}
+		if value.Value != nil {
+			item.Value = pointer.To(format.Value(*value.Value))
+			item.Ready = true
+		}
+		named = append(named, item)
+	}
+	return named
+}
+
+// Sweep — запускает вычисление выражения на сетке значений переменных
+func (s *CalculatorService) Sweep(ctx context.Context, req *client.SweepRequest) (*client.SweepResponse, error) {
+	// переводим диапазоны во внутренние модели
+	ranges := make([]models.SweepRange, 0, len(req.GetRanges()))
+	for _, r := range req.GetRanges() {
+		ranges = append(ranges, models.SweepRange{
+			Variable: r.GetVariable(),
+			From:     r.GetFrom(),
+			To:       r.GetTo(),
+			Steps:    int(r.GetSteps()),
+		})
+	}
+
+	resp, err := s.service.Sweep(ctx, &models.Example{
+		Expression: req.GetExpression(),
+		UserID:     auth.UserIDFromCtx(ctx),
+		Seed:       req.Seed,
+		IEEE:       req.GetIeee(),
+		Locale:     req.GetLocale(),
+		Syntax:     req.GetSyntax(),
+	}, ranges)
+	if invalidRequest(err) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Sweep: %w", err)
+	}
+
+	return &client.SweepResponse{
+		TaskId: resp.ID,
+		Seed:   pointer.Get(resp.Seed),
+	}, nil
+}
+
+// GetSweepResult — возвращает точки sweep в порядке сетки
+func (s *CalculatorService) GetSweepResult(ctx context.Context, req *client.GetSweepResultRequest) (*client.GetSweepResultResponse, error) {
+	format, err := formatOf(req.GetFormat())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	parent, children, err := s.service.GetSweepResult(ctx, auth.UserIDFromCtx(ctx), req.GetTaskId())
+	if err != nil {
+		// ошибка — в теле ответа, как в GetResult
+		return &client.GetSweepResultResponse{
+			Error: pointer.ToString(format.Locale.Translate(err.Error())),
+		}, nil
+	}
+
+	// выражение не разобралось — точек нет
+	if parent.Error != nil {
+		return &client.GetSweepResultResponse{
+			Completed: true,
+			Error:     translated(format, parent.Error),
+		}, nil
+	}
+
+	points := make([]*client.SweepPoint, 0, len(children))
+	for _, child := range children {
+		points = append(points, &client.SweepPoint{
+			Variables:  child.Variables,
+			Calculated: child.Calculated,
+			Value:      child.Result, // может быть nil
+			Text:       child.ResultText,
+			Formatted:  formatted(format, child.Result, child.ResultText),
+			Error:      translated(format, child.Error),
+		})
+	}
+
+	return &client.GetSweepResultResponse{
+		Points:    points,
+		Completed: parent.Calculated,
+	}, nil
+}
+
+// Explain — пошаговое решение примера
+func (s *CalculatorService) Explain(ctx context.Context, req *client.ExplainRequest) (*client.ExplainResponse, error) {
+	explanation, err := s.service.Explain(ctx, auth.UserIDFromCtx(ctx), req.GetTaskId())
+	if err != nil {
+		// ошибка — в теле ответа, как в GetResult
+		return &client.ExplainResponse{
+			Error: pointer.ToString(err.Error()),
+		}, nil
+	}
+
+	steps := make([]*client.ExplainStep, 0, len(explanation.Steps))
+	lines := make([]string, 0, len(explanation.Steps))
+	for _, step := range explanation.Steps {
+		steps = append(steps, &client.ExplainStep{
+			Number:     int32(step.Number),
+			Expression: step.Expression,
+			Operation:  step.Operation,
+			Result:     step.Result,
+			Text:       step.Text,
+		})
+		lines = append(lines, step.Text)
+	}
+
+	return &client.ExplainResponse{
+		Steps:     steps,
+		Text:      strings.Join(lines, "\n"),
+		Completed: explanation.Completed,
+		Error:     explanation.Error,
+	}, nil
+}
+
+// SetVariable — сохраняет переменную пользователя для следующих выражений
+func (s *CalculatorService) SetVariable(ctx context.Context, req *client.SetVariableRequest) (*client.SetVariableResponse, error) {
+	variable, err := s.service.SetVariable(ctx, auth.UserIDFromCtx(ctx), models.UserVariable{
+		Name:  req.GetName(),
+		Value: req.GetValue(),
+	})
+	if errors.Is(err, models.ErrInvalidVariable) {
+		// имя функции, единицы или константы, не число
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SetVariable: %w", err)
+	}
+
+	return &client.SetVariableResponse{
+		Variable: variableOf(*variable),
+	}, nil
+}
+
+// GetVariables — сохранённые переменные пользователя по алфавиту
+func (s *CalculatorService) GetVariables(ctx context.Context, req *client.GetVariablesRequest) (*client.GetVariablesResponse, error) {
+	variables, err := s.service.GetVariables(ctx, auth.UserIDFromCtx(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("GetVariables: %w", err)
+	}
+
+	resp := make([]*client.Variable, 0, len(variables))
+	for _, variable := range variables {
+		resp = append(resp, variableOf(variable))
+	}
+	return &client.GetVariablesResponse{
+		Variables: resp,
+	}, nil
+}
+
+// DeleteVariable — удаляет сохранённую переменную
+func (s *CalculatorService) DeleteVariable(ctx context.Context, req *client.DeleteVariableRequest) (*client.DeleteVariableResponse, error) {
+	deleted, err := s.service.DeleteVariable(ctx, auth.UserIDFromCtx(ctx), req.GetName())
+	if errors.Is(err, models.ErrInvalidVariable) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DeleteVariable: %w", err)
+	}
+	return &client.DeleteVariableResponse{
+		Deleted: deleted,
+	}, nil
+}
+
+// DefineFunction — сохраняет функцию пользователя для следующих выражений
+func (s *CalculatorService) DefineFunction(ctx context.Context, req *client.DefineFunctionRequest) (*client.DefineFunctionResponse, error) {
+	function, err := s.service.DefineFunction(ctx, auth.UserIDFromCtx(ctx), req.GetDefinition())
+	if errors.Is(err, models.ErrInvalidFunction) {
+		// не разбирается, рекурсия или неизвестные имена
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DefineFunction: %w", err)
+	}
+
+	return &client.DefineFunctionResponse{
+		Function: functionOf(*function),
+	}, nil
+}
+
+// GetFunctions — функции пользователя по алфавиту
+func (s *CalculatorService) GetFunctions(ctx context.Context, req *client.GetFunctionsRequest) (*client.GetFunctionsResponse, error) {
+	functions, err := s.service.GetFunctions(ctx, auth.UserIDFromCtx(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("GetFunctions: %w", err)
+	}
+
+	resp := make([]*client.UserFunction, 0, len(functions))
+	for _, function := range functions {
+		resp = append(resp, functionOf(function))
+	}
+	return &client.GetFunctionsResponse{
+		Functions: resp,
+	}, nil
+}
+
+// DeleteFunction — удаляет функцию пользователя
+func (s *CalculatorService) DeleteFunction(ctx context.Context, req *client.DeleteFunctionRequest) (*client.DeleteFunctionResponse, error) {
+	deleted, err := s.service.DeleteFunction(ctx, auth.UserIDFromCtx(ctx), req.GetName())
+	if errors.Is(err, models.ErrInvalidFunction) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DeleteFunction: %w", err)
+	}
+	return &client.DeleteFunctionResponse{
+		Deleted: deleted,
+	}, nil
+}
+
+// UploadModule — загружает модуль WebAssembly с операциями, только для админов
+func (s *CalculatorService) UploadModule(ctx context.Context, req *client.UploadModuleRequest) (*client.UploadModuleResponse, error) {
+	module, err := s.service.UploadModule(ctx, auth.UserIDFromCtx(ctx), &models.WasmModule{
+		Name: req.GetName(),
+		Code: req.GetWasm(),
+	})
+	if errors.Is(err, models.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, models.ErrInvalidModule) {
+		// не wasm, импорты, нет операций или имя занято
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("UploadModule: %w", err)
+	}
+
+	return &client.UploadModuleResponse{
+		Module: moduleOf(*module),
+	}, nil
+}
+
+// GetModules — загруженные модули и их операции
+func (s *CalculatorService) GetModules(ctx context.Context, req *client.GetModulesRequest) (*client.GetModulesResponse, error) {
+	modules, err := s.service.GetModules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetModules: %w", err)
+	}
+
+	resp := make([]*client.WasmModule, 0, len(modules))
+	for _, module := range modules {
+		resp = append(resp, moduleOf(module))
+	}
+	return &client.GetModulesResponse{
+		Modules: resp,
+	}, nil
+}
+
+// DeleteModule — удаляет модуль, только для админов
+func (s *CalculatorService) DeleteModule(ctx context.Context, req *client.DeleteModuleRequest) (*client.DeleteModuleResponse, error) {
+	deleted, err := s.service.DeleteModule(ctx, auth.UserIDFromCtx(ctx), req.GetName())
+	if errors.Is(err, models.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DeleteModule: %w", err)
+	}
+	return &client.DeleteModuleResponse{
+		Deleted: deleted,
+	}, nil
+}
+
+// GetDeadLetters — задачи, от которых отказались воркеры, только для админов
+func (s *CalculatorService) GetDeadLetters(ctx context.Context, req *client.GetDeadLettersRequest) (*client.GetDeadLettersResponse, error) {
+	letters, err := s.service.GetDeadLetters(ctx, auth.UserIDFromCtx(ctx), int(req.GetLimit()))
+	if errors.Is(err, models.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetDeadLetters: %w", err)
+	}
+
+	resp := make([]*client.DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		resp = append(resp, deadLetterOf(letter))
+	}
+	return &client.GetDeadLettersResponse{
+		Letters: resp,
+	}, nil
+}
+
+// ReplayDeadLetter — отправляет задачу воркерам ещё раз, только для админов
+func (s *CalculatorService) ReplayDeadLetter(ctx context.Context, req *client.ReplayDeadLetterRequest) (*client.ReplayDeadLetterResponse, error) {
+	letter, err := s.service.ReplayDeadLetter(ctx, auth.UserIDFromCtx(ctx), req.GetId())
+	if errors.Is(err, models.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, models.ErrNotReplayable) {
+		// нет такого письма или это не задача
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ReplayDeadLetter: %w", err)
+	}
+	return &client.ReplayDeadLetterResponse{
+		Letter: deadLetterOf(*letter),
+	}, nil
+}
+
+// RefreshRates — перечитывает курсы валют, только для админов
+func (s *CalculatorService) RefreshRates(ctx context.Context, req *client.RefreshRatesRequest) (*client.RefreshRatesResponse, error) {
+	snapshot, err := s.service.RefreshRates(ctx, auth.UserIDFromCtx(ctx))
+	if errors.Is(err, models.ErrForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("RefreshRates: %w", err)
+	}
+
+	// коды валют по алфавиту
+	currencies := make([]string, 0, len(snapshot.Rates))
+	for code := range snapshot.Rates {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+
+	return &client.RefreshRatesResponse{
+		SnapshotId: snapshot.ID,
+		Base:       snapshot.Base,
+		Currencies: currencies,
+		LoadedAt:   snapshot.LoadedAt.Format(time.RFC3339),
 	}, nil
 }
 
@@ -121,6 +530,11 @@ func (s *CalculatorService) Login(ctx context.Context, req *client.LoginRequest)
 
 // GetAllExamples — возвращает все вычисления пользователя
 func (s *CalculatorService) GetAllExamples(ctx context.Context, req *client.GetAllExamplesRequest) (*client.GetAllExamplesResponse, error) {
+	format, err := formatOf(req.GetFormat())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	// берём user_id из контекста
 	userId := auth.UserIDFromCtx(ctx)
 
@@ -134,12 +548,26 @@ func (s *CalculatorService) GetAllExamples(ctx context.Context, req *client.GetA
 	examples := make([]*client.Example, 0)
 	for _, example := range resp {
 		examples = append(examples, &client.Example{
-			Id:         example.ID,
-			Expression: example.Expression,
-			Calculated: example.Calculated,
-			Result:     example.Result, // может быть nil
-			Error:      example.Error,
-			CreatedAt:  example.CreatedAt.Format(time.RFC3339), // нормальный формат времени
+			Id:            example.ID,
+			Expression:    example.Expression,
+			Calculated:    example.Calculated,
+			Result:        example.Result, // может быть nil
+			ResultText:    example.ResultText,
+			ResultKind:    example.ResultKind,
+			Formatted:     formatted(format, example.Result, example.ResultText),
+			Error:         translated(format, example.Error),
+			Seed:          example.Seed,
+			RatesSnapshot: example.RatesSnapshot,
+			Ieee:          example.IEEE,
+			Syntax:        example.Syntax,
+			UserVariables: example.UserVariables,                  // значения на момент вычисления
+			References:    referencesOf(example.References),       // на какие примеры ссылается
+			UserFunctions: definitionsOf(example.UserFunctions),   // определения на момент вычисления
+			TotalTasks:    int32(example.TotalTasks),              // прогресс: всего шагов
+			DoneTasks:     int32(example.DoneTasks),               // и уже посчитанных
+			Cancelled:     example.Cancelled,                      // отменён пользователем
+			Deadline:      optionalTimeOf(example.Deadline),       // после него — ошибка по таймауту
+			CreatedAt:     example.CreatedAt.Format(time.RFC3339), // нормальный формат времени
 		})
 	}
 
@@ -148,3 +576,162 @@ func (s *CalculatorService) GetAllExamples(ctx context.Context, req *client.GetA
 		Examples: examples,
 	}, nil
 }
+
+// referencesOf — ссылки на другие примеры: имя → id примера
+func referencesOf(references []models.Reference) map[string]string {
+	if len(references) == 0 {
+		return nil
+	}
+	ids := make(map[string]string, len(references))
+	for _, reference := range references {
+		ids[reference.Name] = reference.ExampleID
+	}
+	return ids
+}
+
+// variableOf — переменная пользователя в gRPC
+func variableOf(variable models.UserVariable) *client.Variable {
+	return &client.Variable{
+		Name:      variable.Name,
+		Value:     variable.Value,
+		UpdatedAt: variable.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// functionOf — функция пользователя в gRPC
+func functionOf(function models.UserFunction) *client.UserFunction {
+	return &client.UserFunction{
+		Name:       function.Name,
+		Params:     function.Params,
+		Body:       function.Body,
+		Definition: definition(function),
+		UpdatedAt:  function.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// definitionsOf — определения функций: f(x, y) = x^2 + 3*y
+func definitionsOf(functions []models.UserFunction) []string {
+	definitions := make([]string, 0, len(functions))
+	for _, function := range functions {
+		definitions = append(definitions, definition(function))
+	}
+	return definitions
+}
+
+func definition(function models.UserFunction) string {
+	return calculator.FunctionDef{Name: function.Name, Params: function.Params, Body: function.Body}.String()
+}
+
+// moduleOf — модуль с операциями в gRPC
+func moduleOf(module models.WasmModule) *client.WasmModule {
+	operations := make([]*client.Operation, 0, len(module.Operations))
+	for _, operation := range module.Operations {
+		operations = append(operations, &client.Operation{
+			Name:  operation.Name,
+			Arity: int32(operation.Arity),
+		})
+	}
+	return &client.WasmModule{
+		Name:       module.Name,
+		Hash:       module.Hash,
+		Operations: operations,
+		UploadedBy: module.UploadedBy,
+		UpdatedAt:  module.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// deadLetterOf — письмо из очереди недоставленных в gRPC
+func deadLetterOf(letter models.DeadLetter) *client.DeadLetter {
+	resp := &client.DeadLetter{
+		Id:        letter.ID,
+		ExampleId: letter.ExampleID,
+		Variable:  letter.Variable,
+		Payload:   letter.Payload,
+		Reason:    letter.Reason,
+		Attempts:  int32(letter.Attempts),
+		WorkerId:  letter.WorkerID,
+		FailedAt:  letter.FailedAt.Format(time.RFC3339),
+	}
+	if letter.ReplayedAt != nil {
+		resp.ReplayedAt = pointer.ToString(letter.ReplayedAt.Format(time.RFC3339))
+	}
+	return resp
+}
+
+// invalidRequest — ошибки запроса, после которых ничего не сохранено
+func invalidRequest(err error) bool {
+	return errors.Is(err, calculator.ErrLimitExceeded) ||
+		errors.Is(err, calculator.ErrUnknownLocale) ||
+		errors.Is(err, calculator.ErrUnknownSyntax) ||
+		errors.Is(err, models.ErrInvalidDeadline)
+}
+
+// deadlineOf — срок из запроса в RFC 3339, nil если его нет
+func deadlineOf(text string) (*time.Time, error) {
+	if text == "" {
+		return nil, nil
+	}
+	deadline, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not an RFC 3339 time", models.ErrInvalidDeadline, text)
+	}
+	return &deadline, nil
+}
+
+// timeOf — время в RFC 3339, пустая строка если его нет
+func timeOf(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// optionalTimeOf — то же для optional полей
+func optionalTimeOf(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	return pointer.ToString(t.Format(time.RFC3339))
+}
+
+// formatOf — параметры форматирования из запроса, пустые — как без формата
+func formatOf(f *client.ResultFormat) (calculator.Format, error) {
+	format := calculator.Format{
+		Digits:         int(f.GetSignificantDigits()),
+		Rounding:       calculator.Rounding(f.GetRounding()),
+		Notation:       calculator.Notation(f.GetNotation()),
+		MaxDenominator: f.GetMaxDenominator(),
+	}
+	if f == nil {
+		return format, nil
+	}
+	locale, err := calculator.LocaleByName(f.GetLocale())
+	if err != nil {
+		return format, err
+	}
+	format.Locale = locale
+	if f.SignificantDigits != nil && f.GetSignificantDigits() <= 0 {
+		format.Digits = -1 // явный ноль — ошибка, а не «сколько нужно»
+	}
+	if f.DecimalPlaces != nil {
+		format.Decimals = pointer.ToInt(int(f.GetDecimalPlaces()))
+	}
+	return format, format.Validate()
+}
+
+// formatted — сохранённый результат по формату; нечисловые результаты
+// хранятся текстом и возвращаются как есть
+func formatted(format calculator.Format, number *float64, text *string) *string {
+	if number != nil {
+		return pointer.ToString(format.Number(*number))
+	}
+	return text
+}
+
+// translated — сообщение об ошибке на языке локали из формата
+func translated(format calculator.Format, message *string) *string {
+	if message == nil {
+		return nil
+	}
+	return pointer.ToString(format.Locale.Translate(*message))
+}
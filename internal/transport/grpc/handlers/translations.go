@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"regexp"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+// ошибки сервиса калькулятор не знает — переводы добавляются здесь,
+// до первого запроса
+func init() {
+	// задача, от которой отказались воркеры: "task failed: <variable> after 5 attempts: <reason>"
+	calculator.AddTranslation("ru", regexp.QuoteMeta(models.ErrTaskFailed.Error())+`: (.+?) after (\d+) attempts`, "задача $1 не выполнена после $2 попыток")
+	// пример отменён пользователем
+	calculator.AddTranslation("ru", regexp.QuoteMeta(models.ErrCancelled.Error()), "пример отменён")
+	// пример не вычислен к сроку
+	calculator.AddTranslation("ru", regexp.QuoteMeta(models.ErrTimeout.Error()), "время вычисления истекло")
+	// задачи примера потеряны и не могут быть отправлены снова
+	calculator.AddTranslation("ru", regexp.QuoteMeta(models.ErrStuck.Error()), "пример завис")
+}
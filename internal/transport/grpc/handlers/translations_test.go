@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
+)
+
+func TestTranslations(t *testing.T) {
+	ru, _ := calculator.LocaleByName("ru")
+	en, _ := calculator.LocaleByName("en")
+
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			"task failed",
+			models.DeadLetter{Variable: "x3", Attempts: 5, Reason: "redis is down"}.ExampleError(),
+			"задача x3 не выполнена после 5 попыток: redis is down",
+		},
+		{
+			"task failed, variable with spaces",
+			models.DeadLetter{Variable: "total sum", Attempts: 2, Reason: "timeout after 3 attempts"}.ExampleError(),
+			"задача total sum не выполнена после 2 попыток: timeout after 3 attempts",
+		},
+		{
+			"example cancelled",
+			models.ErrCancelled.Error(),
+			"пример отменён",
+		},
+		{
+			"calculation timed out",
+			models.ErrTimeout.Error(),
+			"время вычисления истекло",
+		},
+		{
+			"example stuck",
+			fmt.Sprintf("%s: its tasks are not known", models.ErrStuck),
+			"пример завис: its tasks are not known",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ru.Translate(tt.message); got != tt.expected {
+				t.Errorf("Translate(%q) = %q, expected %q", tt.message, got, tt.expected)
+			}
+			if got := en.Translate(tt.message); got != tt.message {
+				t.Errorf("Translate(%q) in en = %q, expected the message", tt.message, got)
+			}
+		})
+	}
+}
@@ -2,15 +2,16 @@ package valueprovider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/db/cache"
 )
 
 type Provider interface {
-	Resolve(ctx context.Context, ref string) (float64, error)
+	Resolve(ctx context.Context, ref string) (calculator.Value, error)
 }
 
 type RedisValueProvider struct {
@@ -21,20 +22,20 @@ func NewRedisValueProvider(cache *cache.CACHE) *RedisValueProvider {
 	return &RedisValueProvider{cache: cache}
 }
 
-func (r *RedisValueProvider) Resolve(ctx context.Context, ref string) (float64, error) {
+func (r *RedisValueProvider) Resolve(ctx context.Context, ref string) (calculator.Value, error) {
 	// try as number
-	if n, err := strconv.ParseFloat(ref, 64); err == nil {
-		return n, nil
+	if value, ok := calculator.ParseLiteral(ref); ok {
+		return value, nil
 	}
 
 	// if not a number - assume it's a variable
 	key := "result:" + ref
-	var result float64
+	var result calculator.Value
 	if err := r.cache.GetByKey(ctx, key, &result); err != nil {
-		if err == redis.Nil {
-			return 0, fmt.Errorf("variable %s not ready yet", ref) // ← not fatal, but error
+		if errors.Is(err, redis.Nil) {
+			return calculator.Value{}, fmt.Errorf("variable %s not ready yet", ref) // ← not fatal, but error
 		}
-		return 0, fmt.Errorf("failed to resolve variable %s: %w", ref, err)
+		return calculator.Value{}, fmt.Errorf("failed to resolve variable %s: %w", ref, err)
 	}
 
 	return result, nil
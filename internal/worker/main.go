@@ -7,21 +7,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/tainj/distributed_calculator2/internal/latency"
 	"github.com/tainj/distributed_calculator2/internal/models"
+	"github.com/tainj/distributed_calculator2/internal/operations"
 	repo "github.com/tainj/distributed_calculator2/internal/repository"
 	"github.com/tainj/distributed_calculator2/internal/valueprovider"
 	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/logger"
 	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
+	"github.com/tainj/distributed_calculator2/pkg/retry"
 )
 
 type Worker struct {
-	exampleRepo   repo.ExampleRepository
+	id            string // host:port, sent with results
 	cacheRepo     repo.VariableRepository
+	cancellations repo.CancellationRepository // examples whose tasks are skipped
 	kafkaQueue    kafka.TaskQueue
+	results       kafka.Producer // completion events for the server
+	deadLetters   kafka.Producer // tasks that failed all attempts
 	valueProvider valueprovider.Provider
+	operations    *operations.Registry // custom operations of uploaded modules
+	retry         retry.Config
+	latency       latency.Config // simulated time of operations
 	logger        logger.Logger
 
 	// for graceful shutdown
@@ -35,19 +48,34 @@ type Worker struct {
 }
 
 func NewWorker(
-	exampleRepo repo.ExampleRepository,
 	cacheRepo repo.VariableRepository,
+	cancellations repo.CancellationRepository,
 	kafkaQueue kafka.TaskQueue,
+	results kafka.Producer,
+	deadLetters kafka.Producer,
 	valueProvider valueprovider.Provider,
+	operationRegistry *operations.Registry,
+	retryPolicy retry.Config,
+	latencyConfig latency.Config,
 	logger logger.Logger,
 	port string,
 ) *Worker {
 	ctx, cancel := context.WithCancel(context.Background())
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
 	return &Worker{
-		exampleRepo:   exampleRepo,
+		id:            host + ":" + port,
 		cacheRepo:     cacheRepo,
+		cancellations: cancellations,
 		kafkaQueue:    kafkaQueue,
+		results:       results,
+		deadLetters:   deadLetters,
 		valueProvider: valueProvider,
+		operations:    operationRegistry,
+		retry:         retryPolicy,
+		latency:       latencyConfig,
 		logger:        logger,
 		ctx:           ctx,
 		cancel:        cancel,
@@ -66,7 +94,7 @@ func (w *Worker) Start() {
 
 func (w *Worker) consumeLoop() {
 	defer w.wg.Done()
-	w.logger.Info(w.ctx, "worker started, reading tasks from kafka...")
+	w.logger.Info(w.ctx, "worker started, reading tasks from kafka...", "latency", fmt.Sprintf("%+v", w.latency))
 
 	for {
 		select {
@@ -83,32 +111,43 @@ func (w *Worker) consumeLoop() {
 
 			var task models.Task
 			if err := json.Unmarshal(jsonData, &task); err != nil {
+				// reading it again won't help
 				w.logger.Error(w.ctx, "failed to unmarshal task", "error", err)
+				w.deadLetter(message, task, 1, fmt.Errorf("invalid task: %w", err))
 				continue
 			}
 			w.logger.Debug(w.ctx, "received task", "raw_json", string(jsonData))
 			w.logger.Debug(w.ctx, "unmarshaled task", "task", fmt.Sprintf("%+v", task))
 
-			// process task
-			result, err := w.ProcessTask(w.ctx, task)
-
-			// business errors: division by zero, syntax
-			if err != nil && (err == calculator.ErrDivisionByZero || err == calculator.ErrCovertExample) {
-				w.logger.Debug(w.ctx, "business error in task", "task Variable", task.Variable, "error", err)
-				if errDB := w.exampleRepo.UpdateExampleWithError(w.ctx, task.ExampleID, err.Error()); errDB != nil {
-					w.logger.Error(w.ctx, "failed to save error to db", "error", errDB)
-					continue
-				}
-				if errCommit := w.kafkaQueue.Commit(message); errCommit != nil {
-					w.logger.Error(w.ctx, "failed to commit after error", "error", errCommit)
-					continue
+			// the example was cancelled or has timed out - the task is
+			// committed without calculating
+			if w.cancelled(task) || w.expired(task) {
+				if err := w.kafkaQueue.Commit(message); err != nil {
+					w.logger.Error(w.ctx, "failed to commit message", "error", err)
 				}
 				continue
 			}
 
-			// infra errors: redis, network - don't commit, Kafka will retry
+			// process task; infra errors (redis, network) are retried with
+			// backoff, the server saves the result or the error and sends
+			// the next tasks
+			attempts, err := retry.Do(w.ctx, w.retry, func() error {
+				result, err := w.ProcessTask(w.ctx, task)
+				if err != nil {
+					w.logger.Warn(w.ctx, "infra error, will retry", "variable", task.Variable, "error", err)
+					return err
+				}
+				if err := w.results.SendTask(result); err != nil {
+					return fmt.Errorf("send task result: %w", err)
+				}
+				return nil
+			})
+			if err != nil && w.ctx.Err() != nil {
+				continue // shutdown, not committed
+			}
 			if err != nil {
-				w.logger.Error(w.ctx, "infra error, will retry", "error", err)
+				w.logger.Error(w.ctx, "task failed, sending to dead letters", "variable", task.Variable, "attempts", attempts, "error", err)
+				w.deadLetter(message, task, attempts, err)
 				continue
 			}
 
@@ -117,55 +156,133 @@ func (w *Worker) consumeLoop() {
 				w.logger.Error(w.ctx, "failed to commit message", "error", err)
 				continue
 			}
-
-			// if final - save result
-			if task.IsFinal {
-				if err := w.handleFinalTask(w.ctx, task, result); err != nil {
-					w.logger.Error(w.ctx, "failed to save final result", "error", err)
-				}
-			}
 		}
 	}
 }
 
-func (w *Worker) ProcessTask(ctx context.Context, task models.Task) (float64, error) {
-	w.logger.Info(ctx, "processing task", "task", fmt.Sprintf("%+v", task))
-	val1, err := w.valueProvider.Resolve(ctx, task.Num1)
+// cancelled - whether the example of the task is marked as cancelled.
+// If Redis can't tell, the task is calculated: the server drops results
+// of cancelled examples anyway.
+func (w *Worker) cancelled(task models.Task) bool {
+	cancelled, err := w.cancellations.IsCancelled(w.ctx, task.ExampleID)
 	if err != nil {
-		return 0, fmt.Errorf("resolve num1 (%s): %w", task.Num1, err)
+		w.logger.Warn(w.ctx, "failed to check cancellation", "example_id", task.ExampleID, "error", err)
+		return false
 	}
-	val2, err := w.valueProvider.Resolve(ctx, task.Num2)
-	if err != nil {
-		return 0, fmt.Errorf("resolve num2 (%s): %w", task.Num2, err)
+	if cancelled {
+		w.logger.Info(w.ctx, "task of cancelled example skipped", "example_id", task.ExampleID, "variable", task.Variable)
 	}
+	return cancelled
+}
 
-	calc := calculator.NewNode(val1, val2, task.Sign)
-	result, err := calc.Calculate()
+// expired - whether the deadline of the example has passed, the server
+// fails such examples with a timeout
+func (w *Worker) expired(task models.Task) bool {
+	if task.Deadline == nil || time.Now().Before(*task.Deadline) {
+		return false
+	}
+	w.logger.Info(w.ctx, "task of expired example dropped", "example_id", task.ExampleID, "variable", task.Variable, "deadline", task.Deadline)
+	return true
+}
+
+// ProcessTask calculates the task and returns its completion event. Business
+// errors (division by zero, syntax, bad arguments) are put in the event,
+// other errors are returned.
+func (w *Worker) ProcessTask(ctx context.Context, task models.Task) (*models.TaskResult[calculator.Value], error) {
+	start := time.Now()
+	args, value, err := w.calculate(ctx, task)
+	result := &models.TaskResult[calculator.Value]{Task: task, Args: args, WorkerID: w.id}
+	switch {
+	case err != nil && calculator.IsBusinessError(err):
+		w.logger.Debug(ctx, "business error in task", "task Variable", task.Variable, "error", err)
+		result.Error = err.Error()
+	case err != nil:
+		return nil, err
+	default:
+		result.Value = &value
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// calculate - operands of the task and its result, the result is kept in
+// Redis for the tasks using it
+func (w *Worker) calculate(ctx context.Context, task models.Task) ([]calculator.Value, calculator.Value, error) {
+	w.logger.Info(ctx, "processing task", "task", fmt.Sprintf("%+v", task))
+
+	// operators take num1 and num2, functions - any number of args
+	refs := []string{task.Num1, task.Num2}
+	if !calculator.IsOperator(task.Sign) {
+		refs = task.Args
+	}
+
+	args := make([]calculator.Value, 0, len(refs))
+	for i, ref := range refs {
+		value, err := w.valueProvider.Resolve(ctx, ref)
+		if err != nil {
+			return nil, calculator.Value{}, fmt.Errorf("resolve operand %d (%s): %w", i+1, ref, err)
+		}
+		args = append(args, value)
+	}
+
+	call := calculator.NewCall(task.Sign, args)
+	call.Seed = calculator.TaskSeed(task.Seed, task.Index) // the same numbers on any worker
+	call.Rates = task.Rates
+	call.IEEE = task.IEEE
+	if !calculator.IsOperator(task.Sign) && !calculator.IsFunction(task.Sign) {
+		// a custom operation, it runs in the sandbox
+		custom, ok, err := w.operations.Operation(ctx, task.Sign)
+		if err != nil {
+			return nil, calculator.Value{}, fmt.Errorf("load operation %s: %w", task.Sign, err)
+		}
+		if !ok {
+			return nil, calculator.Value{}, fmt.Errorf("%w: %s", calculator.ErrUnknownFunction, task.Sign)
+		}
+		call.Custom = custom
+	}
+
+	// simulated time of the operation, for demos and load tests
+	if _, err := w.latency.Wait(ctx, task.Sign); err != nil {
+		return nil, calculator.Value{}, fmt.Errorf("wait for %s: %w", task.Sign, err)
+	}
+	result, err := call.Evaluate()
 	if err != nil {
-		return 0, err
+		return args, calculator.Value{}, err
 	}
 
 	if err := w.cacheRepo.SetResult(ctx, task.Variable, result); err != nil {
-		return 0, fmt.Errorf("save result to Redis: %w", err)
+		return nil, calculator.Value{}, fmt.Errorf("save result to Redis: %w", err)
 	}
 
 	w.logger.Info(ctx, "task processed",
-		"num1", val1,
 		"sign", task.Sign,
-		"num2", val2,
-		"result", result,
+		"operands", len(args),
+		"result", result.String(),
 		"response", task.Variable,
 	)
-	return result, nil
+	return args, result, nil
 }
 
-func (w *Worker) handleFinalTask(ctx context.Context, task models.Task, result float64) error {
-	w.logger.Info(ctx, "trying to save final result", "example_id", task.ExampleID, "result", result)
-	if err := w.exampleRepo.UpdateExample(ctx, task.ExampleID, result); err != nil {
-		return fmt.Errorf("update example in DB: %w", err)
+// deadLetter - sends the message to the dead letter topic and commits it;
+// the server fails the example of the task
+func (w *Worker) deadLetter(message kafkago.Message, task models.Task, attempts int, reason error) {
+	letter := models.DeadLetter{
+		ID:        uuid.New().String(),
+		ExampleID: task.ExampleID,
+		Variable:  task.Variable,
+		Payload:   string(message.Value),
+		Reason:    reason.Error(),
+		Attempts:  attempts,
+		WorkerID:  w.id,
+		FailedAt:  time.Now(),
+	}
+	if _, err := retry.Do(w.ctx, w.retry, func() error { return w.deadLetters.SendTask(letter) }); err != nil {
+		w.logger.Error(w.ctx, "failed to send dead letter, task is not committed", "payload", letter.Payload, "error", err)
+		return
+	}
+	if err := w.kafkaQueue.Commit(message); err != nil {
+		w.logger.Error(w.ctx, "failed to commit dead letter", "error", err)
 	}
-	w.logger.Info(ctx, "final result saved", "example", task.ExampleID, "result", result)
-	return nil
 }
 
 // startHTTPServer - starts /health endpoint
@@ -0,0 +1,12 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+DROP INDEX IF EXISTS idx_examples_parent_id;
+
+-- children go away together with the column
+DELETE FROM examples WHERE parent_id IS NOT NULL;
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS variables,
+DROP COLUMN IF EXISTS position,
+DROP COLUMN IF EXISTS parent_id;
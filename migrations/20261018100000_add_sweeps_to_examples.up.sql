@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- 1. Sweep points are stored as child examples of the sweep example
+ALTER TABLE examples
+ADD COLUMN parent_id VARCHAR(64) REFERENCES examples(id) ON DELETE CASCADE,
+ADD COLUMN position INTEGER, -- index of the point in the sweep grid
+ADD COLUMN variables JSONB; -- values of the variables, e.g. {"x": 1.5}
+
+-- 2. Children are always requested by parent
+CREATE INDEX idx_examples_parent_id ON examples(parent_id);
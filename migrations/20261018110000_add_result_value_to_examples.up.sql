@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Results that are not numbers (lists, matrices) are stored as JSON,
+-- e.g. {"kind": "matrix", "matrix": [[1, 2], [3, 4]]}; "result" stays NULL for them
+ALTER TABLE examples
+ADD COLUMN result_value JSONB;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Seed of rand(), randint(), normal(): the same seed reproduces the result
+ALTER TABLE examples
+ADD COLUMN seed BIGINT;
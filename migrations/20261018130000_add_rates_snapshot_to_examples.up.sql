@@ -0,0 +1,6 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- ID of the currency rates snapshot used by money conversions (for audit)
+ALTER TABLE examples
+ADD COLUMN rates_snapshot VARCHAR(64);
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- IEEE mode: results may be Infinity and NaN instead of overflow and domain errors
+ALTER TABLE examples
+ADD COLUMN ieee BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,14 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+DROP INDEX IF EXISTS idx_tasks_example_order;
+
+DELETE FROM tasks WHERE value1 IS NULL OR value2 IS NULL OR result IS NULL OR LENGTH(sign) > 2;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS result_value,
+DROP COLUMN IF EXISTS operands,
+ALTER COLUMN sign TYPE VARCHAR(2),
+ALTER COLUMN value1 SET NOT NULL,
+ALTER COLUMN value2 SET NOT NULL,
+ALTER COLUMN result SET NOT NULL;
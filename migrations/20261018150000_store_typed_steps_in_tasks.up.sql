@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- 1. Steps are saved by workers for explanations. Operands and results
+-- that are not numbers (lists, money, dates) are stored as JSON,
+-- value1, value2 and result stay NULL for them; functions have any number of operands
+ALTER TABLE tasks
+ALTER COLUMN value1 DROP NOT NULL,
+ALTER COLUMN value2 DROP NOT NULL,
+ALTER COLUMN result DROP NOT NULL,
+ALTER COLUMN sign TYPE VARCHAR(32),
+ADD COLUMN operands JSONB,
+ADD COLUMN result_value JSONB;
+
+-- 2. A retried task doesn't add the step twice
+CREATE UNIQUE INDEX idx_tasks_example_order ON tasks(example_id, "order");
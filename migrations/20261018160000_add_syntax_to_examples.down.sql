@@ -0,0 +1,5 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS syntax;
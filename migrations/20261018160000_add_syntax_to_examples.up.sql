@@ -0,0 +1,6 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- syntax of the expression: infix, rpn (3 4 + 2 *) or sexpr ((* (+ 3 4) 2))
+ALTER TABLE examples
+ADD COLUMN syntax VARCHAR(16) NOT NULL DEFAULT 'infix';
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- names assigned by a script and the refs of their values: [{"name": "r", "ref": "3"}]
+ALTER TABLE examples
+ADD COLUMN bindings JSONB;
@@ -0,0 +1,21 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- named values saved by users: price * (1 + tax_rate)
+CREATE TABLE user_variables (
+    user_id VARCHAR(64) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, name)
+);
+
+CREATE TRIGGER update_user_variables_updated_at
+    BEFORE UPDATE ON user_variables
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- values of the variables used by an example when it was sent: {"tax_rate": 0.2}
+ALTER TABLE examples
+ADD COLUMN user_variables JSONB;
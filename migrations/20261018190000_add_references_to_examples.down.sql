@@ -0,0 +1,9 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+DROP INDEX IF EXISTS idx_examples_depends_on;
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS held_tasks,
+DROP COLUMN IF EXISTS depends_on,
+DROP COLUMN IF EXISTS refs;
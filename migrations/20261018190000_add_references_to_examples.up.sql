@@ -0,0 +1,10 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- earlier examples used by ans, @last and result("id"): [{"name": "ans", "example_id": "...", "ref": "..."}]
+ALTER TABLE examples
+ADD COLUMN refs JSONB,
+ADD COLUMN depends_on JSONB,  -- ids of examples that were not calculated when it was sent
+ADD COLUMN held_tasks JSONB;  -- tasks waiting for them, NULL once they are sent
+
+CREATE INDEX idx_examples_depends_on ON examples USING GIN (depends_on);
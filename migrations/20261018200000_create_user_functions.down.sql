@@ -0,0 +1,7 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS user_functions;
+
+DROP TABLE IF EXISTS user_functions;
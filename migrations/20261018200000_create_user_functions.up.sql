@@ -0,0 +1,23 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- functions defined by users: f(x, y) = x^2 + 3*y
+CREATE TABLE user_functions (
+    user_id VARCHAR(64) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    params JSONB NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, name)
+);
+
+CREATE TRIGGER update_user_functions_updated_at
+    BEFORE UPDATE ON user_functions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- definitions of the functions inlined into an example when it was sent:
+-- [{"name": "f", "params": ["x", "y"], "body": "x^2 + 3*y"}]
+ALTER TABLE examples
+ADD COLUMN user_functions JSONB;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- WebAssembly modules with custom operations, uploaded by admins
+CREATE TABLE wasm_modules (
+    name VARCHAR(64) PRIMARY KEY,
+    code BYTEA NOT NULL,
+    hash CHAR(64) NOT NULL,
+    -- exported functions of numbers: [{"name": "interp", "arity": 3}]
+    operations JSONB NOT NULL,
+    uploaded_by VARCHAR(64) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_wasm_modules_updated_at
+    BEFORE UPDATE ON wasm_modules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
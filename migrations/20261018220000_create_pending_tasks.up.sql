@@ -0,0 +1,13 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- tasks of an example that wait for results of its other tasks,
+-- a task is sent to workers and deleted when waiting_for gets empty
+CREATE TABLE pending_tasks (
+    example_id VARCHAR(64) NOT NULL REFERENCES examples(id) ON DELETE CASCADE,
+    variable VARCHAR(64) NOT NULL,
+    task JSONB NOT NULL,
+    waiting_for JSONB NOT NULL, -- variables of the tasks it needs: ["x1", "x2"]
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (example_id, variable)
+);
@@ -0,0 +1,6 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS done_tasks,
+DROP COLUMN IF EXISTS total_tasks;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- progress of an example: tasks sent to workers and tasks calculated,
+-- done_tasks grows when the server saves a step reported by a worker
+ALTER TABLE examples
+ADD COLUMN total_tasks INT NOT NULL DEFAULT 0,
+ADD COLUMN done_tasks INT NOT NULL DEFAULT 0;
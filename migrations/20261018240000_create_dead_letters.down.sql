@@ -0,0 +1,6 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+DROP INDEX IF EXISTS idx_dead_letters_failed_at;
+
+DROP TABLE IF EXISTS dead_letters;
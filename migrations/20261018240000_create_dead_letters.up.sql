@@ -0,0 +1,20 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- tasks workers gave up on after all attempts and messages that are not tasks,
+-- saved from calculator_dead_letters; example_id is not a reference: a broken
+-- message may name any example
+CREATE TABLE dead_letters (
+    id VARCHAR(64) PRIMARY KEY,
+    example_id VARCHAR(64),
+    variable VARCHAR(64),
+    payload TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    attempts INT NOT NULL,
+    worker_id VARCHAR(255),
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    replayed_at TIMESTAMP WITH TIME ZONE, -- sent to workers again by an admin
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_dead_letters_failed_at ON dead_letters(failed_at);
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- an example cancelled by its user is calculated with the error
+-- "example cancelled", results of workers are not saved for it anymore
+ALTER TABLE examples
+ADD COLUMN cancelled BOOLEAN NOT NULL DEFAULT FALSE;
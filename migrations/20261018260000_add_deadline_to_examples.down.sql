@@ -0,0 +1,7 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+DROP INDEX IF EXISTS idx_examples_deadline;
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS deadline;
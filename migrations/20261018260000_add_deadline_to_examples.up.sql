@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- time by which an example must be calculated, the server fails it with
+-- "calculation timed out" after that; sweeps themselves have none
+ALTER TABLE examples
+ADD COLUMN deadline TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_examples_deadline ON examples (deadline) WHERE calculated = FALSE;
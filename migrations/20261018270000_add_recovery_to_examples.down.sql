@@ -0,0 +1,9 @@
+-- +migrate Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+DROP INDEX IF EXISTS idx_examples_not_calculated;
+
+ALTER TABLE examples
+DROP COLUMN IF EXISTS reaped_at,
+DROP COLUMN IF EXISTS redispatches,
+DROP COLUMN IF EXISTS tasks;
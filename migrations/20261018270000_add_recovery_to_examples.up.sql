@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- tasks sent to workers and how many times the reaper sent lost ones
+-- again; an example is looked at again when reaped_at is old enough
+ALTER TABLE examples
+ADD COLUMN tasks JSONB,
+ADD COLUMN redispatches INT NOT NULL DEFAULT 0,
+ADD COLUMN reaped_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_examples_not_calculated ON examples (created_at) WHERE calculated = FALSE;
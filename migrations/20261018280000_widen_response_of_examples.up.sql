@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- the answer of a single literal is the literal itself: an integer of
+-- any length or a string, not only a variable of 36 characters
+ALTER TABLE examples
+ALTER COLUMN response TYPE TEXT;
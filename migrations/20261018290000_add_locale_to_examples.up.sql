@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- locale the expression was written in, it is saved without separators
+-- of the locale and shown in them again by explain
+ALTER TABLE examples
+ADD COLUMN locale VARCHAR(16) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- when a step of the example was saved last; the reaper leaves examples
+-- that make progress alone, however long they are calculated
+ALTER TABLE examples
+ADD COLUMN progressed_at TIMESTAMPTZ;
@@ -1,18 +1,18 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.35.2
+// 	protoc-gen-go v1.36.6
 // 	protoc        v5.28.3
 // source: calculator.proto
 
 package api
 
 import (
-	reflect "reflect"
-	sync "sync"
-
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -23,11 +23,15 @@ const (
 )
 
 type CalculateRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expression    string                 `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	Seed          *int64                 `protobuf:"varint,2,opt,name=seed,proto3,oneof" json:"seed,omitempty"`  // for rand(), randint(), normal(); random if not set
+	Ieee          bool                   `protobuf:"varint,3,opt,name=ieee,proto3" json:"ieee,omitempty"`        // return Infinity and NaN instead of overflow and domain errors
+	Locale        string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`     // en, ru, de, fr: 3,14 and 1 000 in ru, arguments are separated by ";"
+	Syntax        string                 `protobuf:"bytes,5,opt,name=syntax,proto3" json:"syntax,omitempty"`     // infix (default), rpn: 3 4 + 2 *, sexpr: (* (+ 3 4) 2)
+	Deadline      string                 `protobuf:"bytes,6,opt,name=deadline,proto3" json:"deadline,omitempty"` // RFC 3339: 2026-10-18T12:00:00Z, CALCULATION_TIMEOUT from now if not set
 	unknownFields protoimpl.UnknownFields
-
-	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CalculateRequest) Reset() {
@@ -37,14 +41,2407 @@ func (x *CalculateRequest) Reset() {
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CalculateRequest) String() string {
+func (x *CalculateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateRequest) ProtoMessage() {}
+
+func (x *CalculateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateRequest.ProtoReflect.Descriptor instead.
+func (*CalculateRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CalculateRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *CalculateRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *CalculateRequest) GetIeee() bool {
+	if x != nil {
+		return x.Ieee
+	}
+	return false
+}
+
+func (x *CalculateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *CalculateRequest) GetSyntax() string {
+	if x != nil {
+		return x.Syntax
+	}
+	return ""
+}
+
+func (x *CalculateRequest) GetDeadline() string {
+	if x != nil {
+		return x.Deadline
+	}
+	return ""
+}
+
+type CalculateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Seed          int64                  `protobuf:"varint,2,opt,name=seed,proto3" json:"seed,omitempty"`        // pass it again to reproduce the result
+	Deadline      string                 `protobuf:"bytes,3,opt,name=deadline,proto3" json:"deadline,omitempty"` // the example fails with "calculation timed out" after it, empty if there is none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateResponse) Reset() {
+	*x = CalculateResponse{}
+	mi := &file_calculator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateResponse) ProtoMessage() {}
+
+func (x *CalculateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateResponse.ProtoReflect.Descriptor instead.
+func (*CalculateResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CalculateResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CalculateResponse) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *CalculateResponse) GetDeadline() string {
+	if x != nil {
+		return x.Deadline
+	}
+	return ""
+}
+
+// how results are shown, every field is optional; without fields
+// formatted is the same as text or value and errors are in English
+type ResultFormat struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SignificantDigits *int32                 `protobuf:"varint,1,opt,name=significant_digits,json=significantDigits,proto3,oneof" json:"significant_digits,omitempty"`
+	DecimalPlaces     *int32                 `protobuf:"varint,2,opt,name=decimal_places,json=decimalPlaces,proto3,oneof" json:"decimal_places,omitempty"`    // not together with significant_digits
+	Rounding          string                 `protobuf:"bytes,3,opt,name=rounding,proto3" json:"rounding,omitempty"`                                          // half_even (default), half_up, half_down, up, down, ceiling, floor
+	Notation          string                 `protobuf:"bytes,4,opt,name=notation,proto3" json:"notation,omitempty"`                                          // fixed, scientific, engineering, fraction
+	MaxDenominator    *int64                 `protobuf:"varint,5,opt,name=max_denominator,json=maxDenominator,proto3,oneof" json:"max_denominator,omitempty"` // fraction only, 10000 by default
+	Locale            string                 `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`                                              // separators of numbers and the language of errors: en, ru, de, fr
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ResultFormat) Reset() {
+	*x = ResultFormat{}
+	mi := &file_calculator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultFormat) ProtoMessage() {}
+
+func (x *ResultFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultFormat.ProtoReflect.Descriptor instead.
+func (*ResultFormat) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResultFormat) GetSignificantDigits() int32 {
+	if x != nil && x.SignificantDigits != nil {
+		return *x.SignificantDigits
+	}
+	return 0
+}
+
+func (x *ResultFormat) GetDecimalPlaces() int32 {
+	if x != nil && x.DecimalPlaces != nil {
+		return *x.DecimalPlaces
+	}
+	return 0
+}
+
+func (x *ResultFormat) GetRounding() string {
+	if x != nil {
+		return x.Rounding
+	}
+	return ""
+}
+
+func (x *ResultFormat) GetNotation() string {
+	if x != nil {
+		return x.Notation
+	}
+	return ""
+}
+
+func (x *ResultFormat) GetMaxDenominator() int64 {
+	if x != nil && x.MaxDenominator != nil {
+		return *x.MaxDenominator
+	}
+	return 0
+}
+
+func (x *ResultFormat) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type GetResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Format        *ResultFormat          `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResultRequest) Reset() {
+	*x = GetResultRequest{}
+	mi := &file_calculator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResultRequest) ProtoMessage() {}
+
+func (x *GetResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResultRequest.ProtoReflect.Descriptor instead.
+func (*GetResultRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetResultRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *GetResultRequest) GetFormat() *ResultFormat {
+	if x != nil {
+		return x.Format
+	}
+	return nil
+}
+
+type GetResultResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*GetResultResponse_Value
+	//	*GetResultResponse_Error
+	//	*GetResultResponse_Text
+	Result        isGetResultResponse_Result `protobuf_oneof:"result"`
+	Formatted     string                     `protobuf:"bytes,4,opt,name=formatted,proto3" json:"formatted,omitempty"`  // the result by the requested format
+	Bindings      []*NamedValue              `protobuf:"bytes,5,rep,name=bindings,proto3" json:"bindings,omitempty"`    // names assigned by a script
+	Cancelled     bool                       `protobuf:"varint,6,opt,name=cancelled,proto3" json:"cancelled,omitempty"` // the example was cancelled, error says so too
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResultResponse) Reset() {
+	*x = GetResultResponse{}
+	mi := &file_calculator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResultResponse) ProtoMessage() {}
+
+func (x *GetResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResultResponse.ProtoReflect.Descriptor instead.
+func (*GetResultResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetResultResponse) GetResult() isGetResultResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetResultResponse) GetValue() float64 {
+	if x != nil {
+		if x, ok := x.Result.(*GetResultResponse_Value); ok {
+			return x.Value
+		}
+	}
+	return 0
+}
+
+func (x *GetResultResponse) GetError() string {
+	if x != nil {
+		if x, ok := x.Result.(*GetResultResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return ""
+}
+
+func (x *GetResultResponse) GetText() string {
+	if x != nil {
+		if x, ok := x.Result.(*GetResultResponse_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *GetResultResponse) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+func (x *GetResultResponse) GetBindings() []*NamedValue {
+	if x != nil {
+		return x.Bindings
+	}
+	return nil
+}
+
+func (x *GetResultResponse) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+type isGetResultResponse_Result interface {
+	isGetResultResponse_Result()
+}
+
+type GetResultResponse_Value struct {
+	Value float64 `protobuf:"fixed64,1,opt,name=value,proto3,oneof"`
+}
+
+type GetResultResponse_Error struct {
+	Error string `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+type GetResultResponse_Text struct {
+	Text string `protobuf:"bytes,3,opt,name=text,proto3,oneof"` // results that are not numbers: lists, matrices
+}
+
+func (*GetResultResponse_Value) isGetResultResponse_Result() {}
+
+func (*GetResultResponse_Error) isGetResultResponse_Result() {}
+
+func (*GetResultResponse_Text) isGetResultResponse_Result() {}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // a sweep is cancelled with all its points
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_calculator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type CancelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cancelled     bool                   `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"` // false if the example was already calculated
+	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`    // the example is not found
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelResponse) Reset() {
+	*x = CancelResponse{}
+	mi := &file_calculator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelResponse) ProtoMessage() {}
+
+func (x *CancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelResponse.ProtoReflect.Descriptor instead.
+func (*CancelResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CancelResponse) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+func (x *CancelResponse) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+// NamedValue - a name assigned by a script and its value
+type NamedValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         *string                `protobuf:"bytes,2,opt,name=value,proto3,oneof" json:"value,omitempty"` // by the requested format, not set until it is calculated
+	Ready         bool                   `protobuf:"varint,3,opt,name=ready,proto3" json:"ready,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NamedValue) Reset() {
+	*x = NamedValue{}
+	mi := &file_calculator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NamedValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NamedValue) ProtoMessage() {}
+
+func (x *NamedValue) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamedValue.ProtoReflect.Descriptor instead.
+func (*NamedValue) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NamedValue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NamedValue) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+func (x *NamedValue) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+type GetAllExamplesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        *ResultFormat          `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllExamplesRequest) Reset() {
+	*x = GetAllExamplesRequest{}
+	mi := &file_calculator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllExamplesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllExamplesRequest) ProtoMessage() {}
+
+func (x *GetAllExamplesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllExamplesRequest.ProtoReflect.Descriptor instead.
+func (*GetAllExamplesRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetAllExamplesRequest) GetFormat() *ResultFormat {
+	if x != nil {
+		return x.Format
+	}
+	return nil
+}
+
+type GetAllExamplesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Examples      []*Example             `protobuf:"bytes,1,rep,name=examples,proto3" json:"examples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllExamplesResponse) Reset() {
+	*x = GetAllExamplesResponse{}
+	mi := &file_calculator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllExamplesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllExamplesResponse) ProtoMessage() {}
+
+func (x *GetAllExamplesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllExamplesResponse.ProtoReflect.Descriptor instead.
+func (*GetAllExamplesResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAllExamplesResponse) GetExamples() []*Example {
+	if x != nil {
+		return x.Examples
+	}
+	return nil
+}
+
+type Example struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Expression    string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	Calculated    bool                   `protobuf:"varint,3,opt,name=calculated,proto3" json:"calculated,omitempty"`
+	Result        *float64               `protobuf:"fixed64,4,opt,name=result,proto3,oneof" json:"result,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Error         *string                `protobuf:"bytes,6,opt,name=error,proto3,oneof" json:"error,omitempty"`                             // ← New field!
+	ResultText    *string                `protobuf:"bytes,7,opt,name=result_text,json=resultText,proto3,oneof" json:"result_text,omitempty"` // result that is not a number
+	Seed          *int64                 `protobuf:"varint,8,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	ResultKind    *string                `protobuf:"bytes,9,opt,name=result_kind,json=resultKind,proto3,oneof" json:"result_kind,omitempty"`           // kind of result_text: list, matrix, int, time, duration, money
+	RatesSnapshot *string                `protobuf:"bytes,10,opt,name=rates_snapshot,json=ratesSnapshot,proto3,oneof" json:"rates_snapshot,omitempty"` // currency rates used by conversions
+	Ieee          bool                   `protobuf:"varint,11,opt,name=ieee,proto3" json:"ieee,omitempty"`
+	Formatted     *string                `protobuf:"bytes,12,opt,name=formatted,proto3,oneof" json:"formatted,omitempty"`                                                                                                    // the result by the requested format
+	Syntax        string                 `protobuf:"bytes,13,opt,name=syntax,proto3" json:"syntax,omitempty"`                                                                                                                // syntax of expression: infix, rpn, sexpr
+	UserVariables map[string]float64     `protobuf:"bytes,14,rep,name=user_variables,json=userVariables,proto3" json:"user_variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // saved variables used, as they were when it was sent
+	References    map[string]string      `protobuf:"bytes,15,rep,name=references,proto3" json:"references,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                              // ans, @last, @<id> of result("<id>") → id of the example used
+	UserFunctions []string               `protobuf:"bytes,16,rep,name=user_functions,json=userFunctions,proto3" json:"user_functions,omitempty"`                                                                             // definitions of the functions used, as they were when it was sent
+	TotalTasks    int32                  `protobuf:"varint,17,opt,name=total_tasks,json=totalTasks,proto3" json:"total_tasks,omitempty"`                                                                                     // progress: tasks sent to workers
+	DoneTasks     int32                  `protobuf:"varint,18,opt,name=done_tasks,json=doneTasks,proto3" json:"done_tasks,omitempty"`                                                                                        // and tasks calculated so far
+	Cancelled     bool                   `protobuf:"varint,19,opt,name=cancelled,proto3" json:"cancelled,omitempty"`                                                                                                         // by the user, the error is "example cancelled"
+	Deadline      *string                `protobuf:"bytes,20,opt,name=deadline,proto3,oneof" json:"deadline,omitempty"`                                                                                                      // RFC 3339, not calculated by then - "calculation timed out"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Example) Reset() {
+	*x = Example{}
+	mi := &file_calculator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Example) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Example) ProtoMessage() {}
+
+func (x *Example) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Example.ProtoReflect.Descriptor instead.
+func (*Example) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Example) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Example) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *Example) GetCalculated() bool {
+	if x != nil {
+		return x.Calculated
+	}
+	return false
+}
+
+func (x *Example) GetResult() float64 {
+	if x != nil && x.Result != nil {
+		return *x.Result
+	}
+	return 0
+}
+
+func (x *Example) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Example) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *Example) GetResultText() string {
+	if x != nil && x.ResultText != nil {
+		return *x.ResultText
+	}
+	return ""
+}
+
+func (x *Example) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *Example) GetResultKind() string {
+	if x != nil && x.ResultKind != nil {
+		return *x.ResultKind
+	}
+	return ""
+}
+
+func (x *Example) GetRatesSnapshot() string {
+	if x != nil && x.RatesSnapshot != nil {
+		return *x.RatesSnapshot
+	}
+	return ""
+}
+
+func (x *Example) GetIeee() bool {
+	if x != nil {
+		return x.Ieee
+	}
+	return false
+}
+
+func (x *Example) GetFormatted() string {
+	if x != nil && x.Formatted != nil {
+		return *x.Formatted
+	}
+	return ""
+}
+
+func (x *Example) GetSyntax() string {
+	if x != nil {
+		return x.Syntax
+	}
+	return ""
+}
+
+func (x *Example) GetUserVariables() map[string]float64 {
+	if x != nil {
+		return x.UserVariables
+	}
+	return nil
+}
+
+func (x *Example) GetReferences() map[string]string {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *Example) GetUserFunctions() []string {
+	if x != nil {
+		return x.UserFunctions
+	}
+	return nil
+}
+
+func (x *Example) GetTotalTasks() int32 {
+	if x != nil {
+		return x.TotalTasks
+	}
+	return 0
+}
+
+func (x *Example) GetDoneTasks() int32 {
+	if x != nil {
+		return x.DoneTasks
+	}
+	return 0
+}
+
+func (x *Example) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+func (x *Example) GetDeadline() string {
+	if x != nil && x.Deadline != nil {
+		return *x.Deadline
+	}
+	return ""
+}
+
+// values of one variable: steps + 1 points from "from" to "to" inclusive
+type SweepRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variable      string                 `protobuf:"bytes,1,opt,name=variable,proto3" json:"variable,omitempty"`
+	From          float64                `protobuf:"fixed64,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            float64                `protobuf:"fixed64,3,opt,name=to,proto3" json:"to,omitempty"`
+	Steps         int32                  `protobuf:"varint,4,opt,name=steps,proto3" json:"steps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepRange) Reset() {
+	*x = SweepRange{}
+	mi := &file_calculator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepRange) ProtoMessage() {}
+
+func (x *SweepRange) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepRange.ProtoReflect.Descriptor instead.
+func (*SweepRange) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SweepRange) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
+}
+
+func (x *SweepRange) GetFrom() float64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *SweepRange) GetTo() float64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *SweepRange) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+type SweepRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expression    string                 `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	Ranges        []*SweepRange          `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`    // one or two variables
+	Seed          *int64                 `protobuf:"varint,3,opt,name=seed,proto3,oneof" json:"seed,omitempty"` // the same for every point
+	Ieee          bool                   `protobuf:"varint,4,opt,name=ieee,proto3" json:"ieee,omitempty"`
+	Locale        string                 `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"` // as in CalculateRequest
+	Syntax        string                 `protobuf:"bytes,6,opt,name=syntax,proto3" json:"syntax,omitempty"` // as in CalculateRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepRequest) Reset() {
+	*x = SweepRequest{}
+	mi := &file_calculator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepRequest) ProtoMessage() {}
+
+func (x *SweepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepRequest.ProtoReflect.Descriptor instead.
+func (*SweepRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SweepRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *SweepRequest) GetRanges() []*SweepRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+func (x *SweepRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *SweepRequest) GetIeee() bool {
+	if x != nil {
+		return x.Ieee
+	}
+	return false
+}
+
+func (x *SweepRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *SweepRequest) GetSyntax() string {
+	if x != nil {
+		return x.Syntax
+	}
+	return ""
+}
+
+type SweepResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Seed          int64                  `protobuf:"varint,2,opt,name=seed,proto3" json:"seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepResponse) Reset() {
+	*x = SweepResponse{}
+	mi := &file_calculator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepResponse) ProtoMessage() {}
+
+func (x *SweepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepResponse.ProtoReflect.Descriptor instead.
+func (*SweepResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SweepResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *SweepResponse) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type GetSweepResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Format        *ResultFormat          `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSweepResultRequest) Reset() {
+	*x = GetSweepResultRequest{}
+	mi := &file_calculator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSweepResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSweepResultRequest) ProtoMessage() {}
+
+func (x *GetSweepResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSweepResultRequest.ProtoReflect.Descriptor instead.
+func (*GetSweepResultRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetSweepResultRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *GetSweepResultRequest) GetFormat() *ResultFormat {
+	if x != nil {
+		return x.Format
+	}
+	return nil
+}
+
+type SweepPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     map[string]float64     `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Calculated    bool                   `protobuf:"varint,2,opt,name=calculated,proto3" json:"calculated,omitempty"`
+	Value         *float64               `protobuf:"fixed64,3,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	Error         *string                `protobuf:"bytes,4,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Text          *string                `protobuf:"bytes,5,opt,name=text,proto3,oneof" json:"text,omitempty"`           // value that is not a number
+	Formatted     *string                `protobuf:"bytes,6,opt,name=formatted,proto3,oneof" json:"formatted,omitempty"` // the value by the requested format
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepPoint) Reset() {
+	*x = SweepPoint{}
+	mi := &file_calculator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepPoint) ProtoMessage() {}
+
+func (x *SweepPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepPoint.ProtoReflect.Descriptor instead.
+func (*SweepPoint) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SweepPoint) GetVariables() map[string]float64 {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *SweepPoint) GetCalculated() bool {
+	if x != nil {
+		return x.Calculated
+	}
+	return false
+}
+
+func (x *SweepPoint) GetValue() float64 {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return 0
+}
+
+func (x *SweepPoint) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *SweepPoint) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *SweepPoint) GetFormatted() string {
+	if x != nil && x.Formatted != nil {
+		return *x.Formatted
+	}
+	return ""
+}
+
+type GetSweepResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*SweepPoint          `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`        // in grid order
+	Completed     bool                   `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"` // all points are calculated
+	Error         *string                `protobuf:"bytes,3,opt,name=error,proto3,oneof" json:"error,omitempty"`    // expression itself is invalid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSweepResultResponse) Reset() {
+	*x = GetSweepResultResponse{}
+	mi := &file_calculator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSweepResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSweepResultResponse) ProtoMessage() {}
+
+func (x *GetSweepResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSweepResultResponse.ProtoReflect.Descriptor instead.
+func (*GetSweepResultResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetSweepResultResponse) GetPoints() []*SweepPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *GetSweepResultResponse) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *GetSweepResultResponse) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+type ExplainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainRequest) Reset() {
+	*x = ExplainRequest{}
+	mi := &file_calculator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainRequest) ProtoMessage() {}
+
+func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainRequest.ProtoReflect.Descriptor instead.
+func (*ExplainRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExplainRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// Step 1: 2 * 2 = 4
+type ExplainStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Expression    string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"` // part of the original expression: 2 * 2
+	Operation     string                 `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`   // the step with values of the operands
+	Result        string                 `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainStep) Reset() {
+	*x = ExplainStep{}
+	mi := &file_calculator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainStep) ProtoMessage() {}
+
+func (x *ExplainStep) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainStep.ProtoReflect.Descriptor instead.
+func (*ExplainStep) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExplainStep) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *ExplainStep) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *ExplainStep) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ExplainStep) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *ExplainStep) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ExplainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*ExplainStep         `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`          // in the order of calculation
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`            // all steps, one per line
+	Completed     bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"` // all steps are calculated
+	Error         *string                `protobuf:"bytes,4,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainResponse) Reset() {
+	*x = ExplainResponse{}
+	mi := &file_calculator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainResponse) ProtoMessage() {}
+
+func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ExplainResponse) GetSteps() []*ExplainStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *ExplainResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ExplainResponse) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *ExplainResponse) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+// a named value saved by the user: tax_rate = 0.2
+type Variable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variable) Reset() {
+	*x = Variable{}
+	mi := &file_calculator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variable) ProtoMessage() {}
+
+func (x *Variable) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variable.ProtoReflect.Descriptor instead.
+func (*Variable) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Variable) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variable) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Variable) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type SetVariableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariableRequest) Reset() {
+	*x = SetVariableRequest{}
+	mi := &file_calculator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariableRequest) ProtoMessage() {}
+
+func (x *SetVariableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariableRequest.ProtoReflect.Descriptor instead.
+func (*SetVariableRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetVariableRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetVariableRequest) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type SetVariableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variable      *Variable              `protobuf:"bytes,1,opt,name=variable,proto3" json:"variable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVariableResponse) Reset() {
+	*x = SetVariableResponse{}
+	mi := &file_calculator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVariableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariableResponse) ProtoMessage() {}
+
+func (x *SetVariableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariableResponse.ProtoReflect.Descriptor instead.
+func (*SetVariableResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetVariableResponse) GetVariable() *Variable {
+	if x != nil {
+		return x.Variable
+	}
+	return nil
+}
+
+type GetVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVariablesRequest) Reset() {
+	*x = GetVariablesRequest{}
+	mi := &file_calculator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVariablesRequest) ProtoMessage() {}
+
+func (x *GetVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVariablesRequest.ProtoReflect.Descriptor instead.
+func (*GetVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{23}
+}
+
+type GetVariablesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variables     []*Variable            `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty"` // sorted by name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVariablesResponse) Reset() {
+	*x = GetVariablesResponse{}
+	mi := &file_calculator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVariablesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVariablesResponse) ProtoMessage() {}
+
+func (x *GetVariablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVariablesResponse.ProtoReflect.Descriptor instead.
+func (*GetVariablesResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetVariablesResponse) GetVariables() []*Variable {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+type DeleteVariableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVariableRequest) Reset() {
+	*x = DeleteVariableRequest{}
+	mi := &file_calculator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVariableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVariableRequest) ProtoMessage() {}
+
+func (x *DeleteVariableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVariableRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVariableRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeleteVariableRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteVariableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"` // false if there was no such variable
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVariableResponse) Reset() {
+	*x = DeleteVariableResponse{}
+	mi := &file_calculator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVariableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVariableResponse) ProtoMessage() {}
+
+func (x *DeleteVariableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVariableResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVariableResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteVariableResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+// a function defined by the user: f(x, y) = x^2 + 3*y
+type UserFunction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Params        []string               `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Definition    string                 `protobuf:"bytes,4,opt,name=definition,proto3" json:"definition,omitempty"` // f(x, y) = x^2 + 3*y
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserFunction) Reset() {
+	*x = UserFunction{}
+	mi := &file_calculator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserFunction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserFunction) ProtoMessage() {}
+
+func (x *UserFunction) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserFunction.ProtoReflect.Descriptor instead.
+func (*UserFunction) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UserFunction) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserFunction) GetParams() []string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *UserFunction) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *UserFunction) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
+func (x *UserFunction) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type DefineFunctionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Definition    string                 `protobuf:"bytes,1,opt,name=definition,proto3" json:"definition,omitempty"` // f(x, y) = x^2 + 3*y, replaces a function with the same name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefineFunctionRequest) Reset() {
+	*x = DefineFunctionRequest{}
+	mi := &file_calculator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefineFunctionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefineFunctionRequest) ProtoMessage() {}
+
+func (x *DefineFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefineFunctionRequest.ProtoReflect.Descriptor instead.
+func (*DefineFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DefineFunctionRequest) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
+type DefineFunctionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      *UserFunction          `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefineFunctionResponse) Reset() {
+	*x = DefineFunctionResponse{}
+	mi := &file_calculator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefineFunctionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefineFunctionResponse) ProtoMessage() {}
+
+func (x *DefineFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefineFunctionResponse.ProtoReflect.Descriptor instead.
+func (*DefineFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DefineFunctionResponse) GetFunction() *UserFunction {
+	if x != nil {
+		return x.Function
+	}
+	return nil
+}
+
+type GetFunctionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFunctionsRequest) Reset() {
+	*x = GetFunctionsRequest{}
+	mi := &file_calculator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFunctionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFunctionsRequest) ProtoMessage() {}
+
+func (x *GetFunctionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFunctionsRequest.ProtoReflect.Descriptor instead.
+func (*GetFunctionsRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{30}
+}
+
+type GetFunctionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Functions     []*UserFunction        `protobuf:"bytes,1,rep,name=functions,proto3" json:"functions,omitempty"` // sorted by name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFunctionsResponse) Reset() {
+	*x = GetFunctionsResponse{}
+	mi := &file_calculator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFunctionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFunctionsResponse) ProtoMessage() {}
+
+func (x *GetFunctionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFunctionsResponse.ProtoReflect.Descriptor instead.
+func (*GetFunctionsResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetFunctionsResponse) GetFunctions() []*UserFunction {
+	if x != nil {
+		return x.Functions
+	}
+	return nil
+}
+
+type DeleteFunctionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFunctionRequest) Reset() {
+	*x = DeleteFunctionRequest{}
+	mi := &file_calculator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFunctionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFunctionRequest) ProtoMessage() {}
+
+func (x *DeleteFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFunctionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteFunctionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteFunctionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"` // false if there was no such function
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFunctionResponse) Reset() {
+	*x = DeleteFunctionResponse{}
+	mi := &file_calculator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFunctionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFunctionResponse) ProtoMessage() {}
+
+func (x *DeleteFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFunctionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeleteFunctionResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+// a function of a module: numbers in, a number out
+type Operation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arity         int32                  `protobuf:"varint,2,opt,name=arity,proto3" json:"arity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_calculator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Operation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Operation) GetArity() int32 {
+	if x != nil {
+		return x.Arity
+	}
+	return 0
+}
+
+type WasmModule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`             // sha256 of the code
+	Operations    []*Operation           `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"` // sorted by name
+	UploadedBy    string                 `protobuf:"bytes,4,opt,name=uploaded_by,json=uploadedBy,proto3" json:"uploaded_by,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WasmModule) Reset() {
+	*x = WasmModule{}
+	mi := &file_calculator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WasmModule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WasmModule) ProtoMessage() {}
+
+func (x *WasmModule) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WasmModule.ProtoReflect.Descriptor instead.
+func (*WasmModule) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WasmModule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WasmModule) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *WasmModule) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *WasmModule) GetUploadedBy() string {
+	if x != nil {
+		return x.UploadedBy
+	}
+	return ""
+}
+
+func (x *WasmModule) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type UploadModuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Wasm          []byte                 `protobuf:"bytes,2,opt,name=wasm,proto3" json:"wasm,omitempty"` // base64 in JSON, replaces a module with the same name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadModuleRequest) Reset() {
+	*x = UploadModuleRequest{}
+	mi := &file_calculator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadModuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadModuleRequest) ProtoMessage() {}
+
+func (x *UploadModuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadModuleRequest.ProtoReflect.Descriptor instead.
+func (*UploadModuleRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UploadModuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UploadModuleRequest) GetWasm() []byte {
+	if x != nil {
+		return x.Wasm
+	}
+	return nil
+}
+
+type UploadModuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Module        *WasmModule            `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadModuleResponse) Reset() {
+	*x = UploadModuleResponse{}
+	mi := &file_calculator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadModuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadModuleResponse) ProtoMessage() {}
+
+func (x *UploadModuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadModuleResponse.ProtoReflect.Descriptor instead.
+func (*UploadModuleResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *UploadModuleResponse) GetModule() *WasmModule {
+	if x != nil {
+		return x.Module
+	}
+	return nil
+}
+
+type GetModulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModulesRequest) Reset() {
+	*x = GetModulesRequest{}
+	mi := &file_calculator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModulesRequest) ProtoMessage() {}
+
+func (x *GetModulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModulesRequest.ProtoReflect.Descriptor instead.
+func (*GetModulesRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{38}
+}
+
+type GetModulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Modules       []*WasmModule          `protobuf:"bytes,1,rep,name=modules,proto3" json:"modules,omitempty"` // sorted by name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModulesResponse) Reset() {
+	*x = GetModulesResponse{}
+	mi := &file_calculator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModulesResponse) ProtoMessage() {}
+
+func (x *GetModulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModulesResponse.ProtoReflect.Descriptor instead.
+func (*GetModulesResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetModulesResponse) GetModules() []*WasmModule {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+type DeleteModuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteModuleRequest) Reset() {
+	*x = DeleteModuleRequest{}
+	mi := &file_calculator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteModuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CalculateRequest) ProtoMessage() {}
+func (*DeleteModuleRequest) ProtoMessage() {}
 
-func (x *CalculateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[0]
+func (x *DeleteModuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,41 +2452,40 @@ func (x *CalculateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CalculateRequest.ProtoReflect.Descriptor instead.
-func (*CalculateRequest) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use DeleteModuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteModuleRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *CalculateRequest) GetExpression() string {
+func (x *DeleteModuleRequest) GetName() string {
 	if x != nil {
-		return x.Expression
+		return x.Name
 	}
 	return ""
 }
 
-type CalculateResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type DeleteModuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"` // false if there was no such module
 	unknownFields protoimpl.UnknownFields
-
-	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CalculateResponse) Reset() {
-	*x = CalculateResponse{}
-	mi := &file_calculator_proto_msgTypes[1]
+func (x *DeleteModuleResponse) Reset() {
+	*x = DeleteModuleResponse{}
+	mi := &file_calculator_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CalculateResponse) String() string {
+func (x *DeleteModuleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CalculateResponse) ProtoMessage() {}
+func (*DeleteModuleResponse) ProtoMessage() {}
 
-func (x *CalculateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[1]
+func (x *DeleteModuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,41 +2496,49 @@ func (x *CalculateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CalculateResponse.ProtoReflect.Descriptor instead.
-func (*CalculateResponse) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use DeleteModuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteModuleResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *CalculateResponse) GetTaskId() string {
+func (x *DeleteModuleResponse) GetDeleted() bool {
 	if x != nil {
-		return x.TaskId
+		return x.Deleted
 	}
-	return ""
+	return false
 }
 
-type GetResultRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+// a task that failed all its attempts, or a message that is not a task
+type DeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExampleId     string                 `protobuf:"bytes,2,opt,name=example_id,json=exampleId,proto3" json:"example_id,omitempty"` // empty if the message is not a task
+	Variable      string                 `protobuf:"bytes,3,opt,name=variable,proto3" json:"variable,omitempty"`
+	Payload       string                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"` // the message as workers read it
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`   // the last error
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	FailedAt      string                 `protobuf:"bytes,8,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	ReplayedAt    *string                `protobuf:"bytes,9,opt,name=replayed_at,json=replayedAt,proto3,oneof" json:"replayed_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetResultRequest) Reset() {
-	*x = GetResultRequest{}
-	mi := &file_calculator_proto_msgTypes[2]
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_calculator_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetResultRequest) String() string {
+func (x *DeadLetter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResultRequest) ProtoMessage() {}
+func (*DeadLetter) ProtoMessage() {}
 
-func (x *GetResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[2]
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,45 +2549,96 @@ func (x *GetResultRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResultRequest.ProtoReflect.Descriptor instead.
-func (*GetResultRequest) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *GetResultRequest) GetTaskId() string {
+func (x *DeadLetter) GetId() string {
 	if x != nil {
-		return x.TaskId
+		return x.Id
 	}
 	return ""
 }
 
-type GetResultResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *DeadLetter) GetExampleId() string {
+	if x != nil {
+		return x.ExampleId
+	}
+	return ""
+}
 
-	// Types that are assignable to Result:
-	//
-	//	*GetResultResponse_Value
-	//	*GetResultResponse_Error
-	Result isGetResultResponse_Result `protobuf_oneof:"result"`
+func (x *DeadLetter) GetVariable() string {
+	if x != nil {
+		return x.Variable
+	}
+	return ""
 }
 
-func (x *GetResultResponse) Reset() {
-	*x = GetResultResponse{}
-	mi := &file_calculator_proto_msgTypes[3]
+func (x *DeadLetter) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetFailedAt() string {
+	if x != nil {
+		return x.FailedAt
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetReplayedAt() string {
+	if x != nil && x.ReplayedAt != nil {
+		return *x.ReplayedAt
+	}
+	return ""
+}
+
+type GetDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // 100 if not set, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeadLettersRequest) Reset() {
+	*x = GetDeadLettersRequest{}
+	mi := &file_calculator_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetResultResponse) String() string {
+func (x *GetDeadLettersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResultResponse) ProtoMessage() {}
+func (*GetDeadLettersRequest) ProtoMessage() {}
 
-func (x *GetResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[3]
+func (x *GetDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,69 +2649,84 @@ func (x *GetResultResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResultResponse.ProtoReflect.Descriptor instead.
-func (*GetResultResponse) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{3}
-}
-
-func (m *GetResultResponse) GetResult() isGetResultResponse_Result {
-	if m != nil {
-		return m.Result
-	}
-	return nil
+// Deprecated: Use GetDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*GetDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *GetResultResponse) GetValue() float64 {
-	if x, ok := x.GetResult().(*GetResultResponse_Value); ok {
-		return x.Value
+func (x *GetDeadLettersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
 	}
 	return 0
 }
 
-func (x *GetResultResponse) GetError() string {
-	if x, ok := x.GetResult().(*GetResultResponse_Error); ok {
-		return x.Error
-	}
-	return ""
+type GetDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Letters       []*DeadLetter          `protobuf:"bytes,1,rep,name=letters,proto3" json:"letters,omitempty"` // newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type isGetResultResponse_Result interface {
-	isGetResultResponse_Result()
+func (x *GetDeadLettersResponse) Reset() {
+	*x = GetDeadLettersResponse{}
+	mi := &file_calculator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type GetResultResponse_Value struct {
-	Value float64 `protobuf:"fixed64,1,opt,name=value,proto3,oneof"`
+func (x *GetDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type GetResultResponse_Error struct {
-	Error string `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+func (*GetDeadLettersResponse) ProtoMessage() {}
+
+func (x *GetDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (*GetResultResponse_Value) isGetResultResponse_Result() {}
+// Deprecated: Use GetDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*GetDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{44}
+}
 
-func (*GetResultResponse_Error) isGetResultResponse_Result() {}
+func (x *GetDeadLettersResponse) GetLetters() []*DeadLetter {
+	if x != nil {
+		return x.Letters
+	}
+	return nil
+}
 
-type GetAllExamplesRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type ReplayDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllExamplesRequest) Reset() {
-	*x = GetAllExamplesRequest{}
-	mi := &file_calculator_proto_msgTypes[4]
+func (x *ReplayDeadLetterRequest) Reset() {
+	*x = ReplayDeadLetterRequest{}
+	mi := &file_calculator_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllExamplesRequest) String() string {
+func (x *ReplayDeadLetterRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllExamplesRequest) ProtoMessage() {}
+func (*ReplayDeadLetterRequest) ProtoMessage() {}
 
-func (x *GetAllExamplesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[4]
+func (x *ReplayDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,34 +2737,40 @@ func (x *GetAllExamplesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllExamplesRequest.ProtoReflect.Descriptor instead.
-func (*GetAllExamplesRequest) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ReplayDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{45}
 }
 
-type GetAllExamplesResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *ReplayDeadLetterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
 
-	Examples []*Example `protobuf:"bytes,1,rep,name=examples,proto3" json:"examples,omitempty"`
+type ReplayDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Letter        *DeadLetter            `protobuf:"bytes,1,opt,name=letter,proto3" json:"letter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllExamplesResponse) Reset() {
-	*x = GetAllExamplesResponse{}
-	mi := &file_calculator_proto_msgTypes[5]
+func (x *ReplayDeadLetterResponse) Reset() {
+	*x = ReplayDeadLetterResponse{}
+	mi := &file_calculator_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllExamplesResponse) String() string {
+func (x *ReplayDeadLetterResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllExamplesResponse) ProtoMessage() {}
+func (*ReplayDeadLetterResponse) ProtoMessage() {}
 
-func (x *GetAllExamplesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[5]
+func (x *ReplayDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -305,46 +2781,39 @@ func (x *GetAllExamplesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllExamplesResponse.ProtoReflect.Descriptor instead.
-func (*GetAllExamplesResponse) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ReplayDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*ReplayDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *GetAllExamplesResponse) GetExamples() []*Example {
+func (x *ReplayDeadLetterResponse) GetLetter() *DeadLetter {
 	if x != nil {
-		return x.Examples
+		return x.Letter
 	}
 	return nil
 }
 
-type Example struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type RefreshRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
-
-	Id         string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Expression string   `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
-	Calculated bool     `protobuf:"varint,3,opt,name=calculated,proto3" json:"calculated,omitempty"`
-	Result     *float64 `protobuf:"fixed64,4,opt,name=result,proto3,oneof" json:"result,omitempty"`
-	CreatedAt  string   `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Error      *string  `protobuf:"bytes,6,opt,name=error,proto3,oneof" json:"error,omitempty"` // ← New field!
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Example) Reset() {
-	*x = Example{}
-	mi := &file_calculator_proto_msgTypes[6]
+func (x *RefreshRatesRequest) Reset() {
+	*x = RefreshRatesRequest{}
+	mi := &file_calculator_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Example) String() string {
+func (x *RefreshRatesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Example) ProtoMessage() {}
+func (*RefreshRatesRequest) ProtoMessage() {}
 
-func (x *Example) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[6]
+func (x *RefreshRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -355,65 +2824,90 @@ func (x *Example) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Example.ProtoReflect.Descriptor instead.
-func (*Example) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RefreshRatesRequest.ProtoReflect.Descriptor instead.
+func (*RefreshRatesRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *Example) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+type RefreshRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SnapshotId    string                 `protobuf:"bytes,1,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	Base          string                 `protobuf:"bytes,2,opt,name=base,proto3" json:"base,omitempty"`
+	Currencies    []string               `protobuf:"bytes,3,rep,name=currencies,proto3" json:"currencies,omitempty"` // sorted
+	LoadedAt      string                 `protobuf:"bytes,4,opt,name=loaded_at,json=loadedAt,proto3" json:"loaded_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Example) GetExpression() string {
+func (x *RefreshRatesResponse) Reset() {
+	*x = RefreshRatesResponse{}
+	mi := &file_calculator_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRatesResponse) ProtoMessage() {}
+
+func (x *RefreshRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_proto_msgTypes[48]
 	if x != nil {
-		return x.Expression
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Example) GetCalculated() bool {
+// Deprecated: Use RefreshRatesResponse.ProtoReflect.Descriptor instead.
+func (*RefreshRatesResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RefreshRatesResponse) GetSnapshotId() string {
 	if x != nil {
-		return x.Calculated
+		return x.SnapshotId
 	}
-	return false
+	return ""
 }
 
-func (x *Example) GetResult() float64 {
-	if x != nil && x.Result != nil {
-		return *x.Result
+func (x *RefreshRatesResponse) GetBase() string {
+	if x != nil {
+		return x.Base
 	}
-	return 0
+	return ""
 }
 
-func (x *Example) GetCreatedAt() string {
+func (x *RefreshRatesResponse) GetCurrencies() []string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Currencies
 	}
-	return ""
+	return nil
 }
 
-func (x *Example) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+func (x *RefreshRatesResponse) GetLoadedAt() string {
+	if x != nil {
+		return x.LoadedAt
 	}
 	return ""
 }
 
 type RegisterRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Email    string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RegisterRequest) Reset() {
 	*x = RegisterRequest{}
-	mi := &file_calculator_proto_msgTypes[7]
+	mi := &file_calculator_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -425,7 +2919,7 @@ func (x *RegisterRequest) String() string {
 func (*RegisterRequest) ProtoMessage() {}
 
 func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[7]
+	mi := &file_calculator_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,7 +2932,7 @@ func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
 func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{7}
+	return file_calculator_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *RegisterRequest) GetEmail() string {
@@ -456,17 +2950,16 @@ func (x *RegisterRequest) GetPassword() string {
 }
 
 type RegisterResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Success bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error   string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RegisterResponse) Reset() {
 	*x = RegisterResponse{}
-	mi := &file_calculator_proto_msgTypes[8]
+	mi := &file_calculator_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -478,7 +2971,7 @@ func (x *RegisterResponse) String() string {
 func (*RegisterResponse) ProtoMessage() {}
 
 func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[8]
+	mi := &file_calculator_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -491,7 +2984,7 @@ func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
 func (*RegisterResponse) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{8}
+	return file_calculator_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *RegisterResponse) GetSuccess() bool {
@@ -509,17 +3002,16 @@ func (x *RegisterResponse) GetError() string {
 }
 
 type LoginRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Email    string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoginRequest) Reset() {
 	*x = LoginRequest{}
-	mi := &file_calculator_proto_msgTypes[9]
+	mi := &file_calculator_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -531,7 +3023,7 @@ func (x *LoginRequest) String() string {
 func (*LoginRequest) ProtoMessage() {}
 
 func (x *LoginRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[9]
+	mi := &file_calculator_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +3036,7 @@ func (x *LoginRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
 func (*LoginRequest) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{9}
+	return file_calculator_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *LoginRequest) GetEmail() string {
@@ -562,19 +3054,18 @@ func (x *LoginRequest) GetPassword() string {
 }
 
 type LoginResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Success bool   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Token   string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
-	UserId  string `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Error   string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LoginResponse) Reset() {
 	*x = LoginResponse{}
-	mi := &file_calculator_proto_msgTypes[10]
+	mi := &file_calculator_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -586,7 +3077,7 @@ func (x *LoginResponse) String() string {
 func (*LoginResponse) ProtoMessage() {}
 
 func (x *LoginResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_calculator_proto_msgTypes[10]
+	mi := &file_calculator_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,7 +3090,7 @@ func (x *LoginResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
 func (*LoginResponse) Descriptor() ([]byte, []int) {
-	return file_calculator_proto_rawDescGZIP(), []int{10}
+	return file_calculator_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *LoginResponse) GetSuccess() bool {
@@ -632,144 +3123,446 @@ func (x *LoginResponse) GetError() string {
 
 var File_calculator_proto protoreflect.FileDescriptor
 
-var file_calculator_proto_rawDesc = []byte{
-	0x0a, 0x10, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x0a, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x1a, 0x1c,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x32, 0x0a, 0x10,
-	0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x22, 0x2c, 0x0a, 0x11, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x73, 0x6b, 0x49, 0x64, 0x22, 0x2b,
-	0x0a, 0x10, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x73, 0x6b, 0x49, 0x64, 0x22, 0x4d, 0x0a, 0x11, 0x47,
-	0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x16, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x48,
-	0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x17, 0x0a, 0x15, 0x47, 0x65,
-	0x74, 0x41, 0x6c, 0x6c, 0x45, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x22, 0x49, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x45, 0x78, 0x61,
-	0x6d, 0x70, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a,
-	0x08, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x13, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x45, 0x78, 0x61,
-	0x6d, 0x70, 0x6c, 0x65, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0xc5,
-	0x01, 0x0a, 0x07, 0x45, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78,
-	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61,
-	0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
-	0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x06, 0x72, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x19, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x88, 0x01,
-	0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x08, 0x0a, 0x06,
-	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x43, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
-	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61,
-	0x69, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12,
-	0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x42, 0x0a, 0x10, 0x52,
-	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
-	0x40, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
-	0x64, 0x22, 0x6e, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05,
-	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x32, 0xf7, 0x03, 0x0a, 0x0a, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72,
-	0x12, 0x62, 0x0a, 0x09, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e,
-	0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x75,
-	0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x63, 0x61,
-	0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93,
-	0x02, 0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x6c, 0x63, 0x75,
-	0x6c, 0x61, 0x74, 0x65, 0x12, 0x5f, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x12, 0x1c, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47,
-	0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1d, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x15,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x3a, 0x01, 0x2a, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x72,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x70, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x45,
-	0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c,
-	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x45, 0x78, 0x61, 0x6d, 0x70,
-	0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x61, 0x6c,
-	0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x45, 0x78,
-	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x3a, 0x01, 0x2a, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x65,
-	0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x5e, 0x0a, 0x08, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72,
-	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1c, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65,
-	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17,
-	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x3a, 0x01, 0x2a, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x72,
-	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x52, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
-	0x12, 0x18, 0x2e, 0x63, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x6f,
-	0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x63, 0x61, 0x6c,
-	0x63, 0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x3a, 0x01, 0x2a,
-	0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x42, 0x32, 0x5a, 0x30, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x61, 0x69, 0x6e, 0x6a, 0x2f,
-	0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x61, 0x6c, 0x63,
-	0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x32, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+const file_calculator_proto_rawDesc = "" +
+	"\n" +
+	"\x10calculator.proto\x12\n" +
+	"calculator\x1a\x1cgoogle/api/annotations.proto\"\xb4\x01\n" +
+	"\x10CalculateRequest\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12\x17\n" +
+	"\x04seed\x18\x02 \x01(\x03H\x00R\x04seed\x88\x01\x01\x12\x12\n" +
+	"\x04ieee\x18\x03 \x01(\bR\x04ieee\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\x12\x16\n" +
+	"\x06syntax\x18\x05 \x01(\tR\x06syntax\x12\x1a\n" +
+	"\bdeadline\x18\x06 \x01(\tR\bdeadlineB\a\n" +
+	"\x05_seed\"\\\n" +
+	"\x11CalculateResponse\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04seed\x18\x02 \x01(\x03R\x04seed\x12\x1a\n" +
+	"\bdeadline\x18\x03 \x01(\tR\bdeadline\"\xaa\x02\n" +
+	"\fResultFormat\x122\n" +
+	"\x12significant_digits\x18\x01 \x01(\x05H\x00R\x11significantDigits\x88\x01\x01\x12*\n" +
+	"\x0edecimal_places\x18\x02 \x01(\x05H\x01R\rdecimalPlaces\x88\x01\x01\x12\x1a\n" +
+	"\brounding\x18\x03 \x01(\tR\brounding\x12\x1a\n" +
+	"\bnotation\x18\x04 \x01(\tR\bnotation\x12,\n" +
+	"\x0fmax_denominator\x18\x05 \x01(\x03H\x02R\x0emaxDenominator\x88\x01\x01\x12\x16\n" +
+	"\x06locale\x18\x06 \x01(\tR\x06localeB\x15\n" +
+	"\x13_significant_digitsB\x11\n" +
+	"\x0f_decimal_placesB\x12\n" +
+	"\x10_max_denominator\"]\n" +
+	"\x10GetResultRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x120\n" +
+	"\x06format\x18\x02 \x01(\v2\x18.calculator.ResultFormatR\x06format\"\xd3\x01\n" +
+	"\x11GetResultResponse\x12\x16\n" +
+	"\x05value\x18\x01 \x01(\x01H\x00R\x05value\x12\x16\n" +
+	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x12\x14\n" +
+	"\x04text\x18\x03 \x01(\tH\x00R\x04text\x12\x1c\n" +
+	"\tformatted\x18\x04 \x01(\tR\tformatted\x122\n" +
+	"\bbindings\x18\x05 \x03(\v2\x16.calculator.NamedValueR\bbindings\x12\x1c\n" +
+	"\tcancelled\x18\x06 \x01(\bR\tcancelledB\b\n" +
+	"\x06result\"(\n" +
+	"\rCancelRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"S\n" +
+	"\x0eCancelResponse\x12\x1c\n" +
+	"\tcancelled\x18\x01 \x01(\bR\tcancelled\x12\x19\n" +
+	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
+	"\x06_error\"[\n" +
+	"\n" +
+	"NamedValue\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\x05value\x18\x02 \x01(\tH\x00R\x05value\x88\x01\x01\x12\x14\n" +
+	"\x05ready\x18\x03 \x01(\bR\x05readyB\b\n" +
+	"\x06_value\"I\n" +
+	"\x15GetAllExamplesRequest\x120\n" +
+	"\x06format\x18\x01 \x01(\v2\x18.calculator.ResultFormatR\x06format\"I\n" +
+	"\x16GetAllExamplesResponse\x12/\n" +
+	"\bexamples\x18\x01 \x03(\v2\x13.calculator.ExampleR\bexamples\"\xb7\a\n" +
+	"\aExample\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\x12\x1e\n" +
+	"\n" +
+	"calculated\x18\x03 \x01(\bR\n" +
+	"calculated\x12\x1b\n" +
+	"\x06result\x18\x04 \x01(\x01H\x00R\x06result\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x19\n" +
+	"\x05error\x18\x06 \x01(\tH\x01R\x05error\x88\x01\x01\x12$\n" +
+	"\vresult_text\x18\a \x01(\tH\x02R\n" +
+	"resultText\x88\x01\x01\x12\x17\n" +
+	"\x04seed\x18\b \x01(\x03H\x03R\x04seed\x88\x01\x01\x12$\n" +
+	"\vresult_kind\x18\t \x01(\tH\x04R\n" +
+	"resultKind\x88\x01\x01\x12*\n" +
+	"\x0erates_snapshot\x18\n" +
+	" \x01(\tH\x05R\rratesSnapshot\x88\x01\x01\x12\x12\n" +
+	"\x04ieee\x18\v \x01(\bR\x04ieee\x12!\n" +
+	"\tformatted\x18\f \x01(\tH\x06R\tformatted\x88\x01\x01\x12\x16\n" +
+	"\x06syntax\x18\r \x01(\tR\x06syntax\x12M\n" +
+	"\x0euser_variables\x18\x0e \x03(\v2&.calculator.Example.UserVariablesEntryR\ruserVariables\x12C\n" +
+	"\n" +
+	"references\x18\x0f \x03(\v2#.calculator.Example.ReferencesEntryR\n" +
+	"references\x12%\n" +
+	"\x0euser_functions\x18\x10 \x03(\tR\ruserFunctions\x12\x1f\n" +
+	"\vtotal_tasks\x18\x11 \x01(\x05R\n" +
+	"totalTasks\x12\x1d\n" +
+	"\n" +
+	"done_tasks\x18\x12 \x01(\x05R\tdoneTasks\x12\x1c\n" +
+	"\tcancelled\x18\x13 \x01(\bR\tcancelled\x12\x1f\n" +
+	"\bdeadline\x18\x14 \x01(\tH\aR\bdeadline\x88\x01\x01\x1a@\n" +
+	"\x12UserVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a=\n" +
+	"\x0fReferencesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\a_resultB\b\n" +
+	"\x06_errorB\x0e\n" +
+	"\f_result_textB\a\n" +
+	"\x05_seedB\x0e\n" +
+	"\f_result_kindB\x11\n" +
+	"\x0f_rates_snapshotB\f\n" +
+	"\n" +
+	"_formattedB\v\n" +
+	"\t_deadline\"b\n" +
+	"\n" +
+	"SweepRange\x12\x1a\n" +
+	"\bvariable\x18\x01 \x01(\tR\bvariable\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x01R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x01R\x02to\x12\x14\n" +
+	"\x05steps\x18\x04 \x01(\x05R\x05steps\"\xc4\x01\n" +
+	"\fSweepRequest\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12.\n" +
+	"\x06ranges\x18\x02 \x03(\v2\x16.calculator.SweepRangeR\x06ranges\x12\x17\n" +
+	"\x04seed\x18\x03 \x01(\x03H\x00R\x04seed\x88\x01\x01\x12\x12\n" +
+	"\x04ieee\x18\x04 \x01(\bR\x04ieee\x12\x16\n" +
+	"\x06locale\x18\x05 \x01(\tR\x06locale\x12\x16\n" +
+	"\x06syntax\x18\x06 \x01(\tR\x06syntaxB\a\n" +
+	"\x05_seed\"<\n" +
+	"\rSweepResponse\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04seed\x18\x02 \x01(\x03R\x04seed\"b\n" +
+	"\x15GetSweepResultRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x120\n" +
+	"\x06format\x18\x02 \x01(\v2\x18.calculator.ResultFormatR\x06format\"\xcc\x02\n" +
+	"\n" +
+	"SweepPoint\x12C\n" +
+	"\tvariables\x18\x01 \x03(\v2%.calculator.SweepPoint.VariablesEntryR\tvariables\x12\x1e\n" +
+	"\n" +
+	"calculated\x18\x02 \x01(\bR\n" +
+	"calculated\x12\x19\n" +
+	"\x05value\x18\x03 \x01(\x01H\x00R\x05value\x88\x01\x01\x12\x19\n" +
+	"\x05error\x18\x04 \x01(\tH\x01R\x05error\x88\x01\x01\x12\x17\n" +
+	"\x04text\x18\x05 \x01(\tH\x02R\x04text\x88\x01\x01\x12!\n" +
+	"\tformatted\x18\x06 \x01(\tH\x03R\tformatted\x88\x01\x01\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01B\b\n" +
+	"\x06_valueB\b\n" +
+	"\x06_errorB\a\n" +
+	"\x05_textB\f\n" +
+	"\n" +
+	"_formatted\"\x8b\x01\n" +
+	"\x16GetSweepResultResponse\x12.\n" +
+	"\x06points\x18\x01 \x03(\v2\x16.calculator.SweepPointR\x06points\x12\x1c\n" +
+	"\tcompleted\x18\x02 \x01(\bR\tcompleted\x12\x19\n" +
+	"\x05error\x18\x03 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
+	"\x06_error\")\n" +
+	"\x0eExplainRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\x8f\x01\n" +
+	"\vExplainStep\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\"\x97\x01\n" +
+	"\x0fExplainResponse\x12-\n" +
+	"\x05steps\x18\x01 \x03(\v2\x17.calculator.ExplainStepR\x05steps\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\bR\tcompleted\x12\x19\n" +
+	"\x05error\x18\x04 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
+	"\x06_error\"S\n" +
+	"\bVariable\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\tR\tupdatedAt\">\n" +
+	"\x12SetVariableRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"G\n" +
+	"\x13SetVariableResponse\x120\n" +
+	"\bvariable\x18\x01 \x01(\v2\x14.calculator.VariableR\bvariable\"\x15\n" +
+	"\x13GetVariablesRequest\"J\n" +
+	"\x14GetVariablesResponse\x122\n" +
+	"\tvariables\x18\x01 \x03(\v2\x14.calculator.VariableR\tvariables\"+\n" +
+	"\x15DeleteVariableRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"2\n" +
+	"\x16DeleteVariableResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"\x8d\x01\n" +
+	"\fUserFunction\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06params\x18\x02 \x03(\tR\x06params\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x1e\n" +
+	"\n" +
+	"definition\x18\x04 \x01(\tR\n" +
+	"definition\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"7\n" +
+	"\x15DefineFunctionRequest\x12\x1e\n" +
+	"\n" +
+	"definition\x18\x01 \x01(\tR\n" +
+	"definition\"N\n" +
+	"\x16DefineFunctionResponse\x124\n" +
+	"\bfunction\x18\x01 \x01(\v2\x18.calculator.UserFunctionR\bfunction\"\x15\n" +
+	"\x13GetFunctionsRequest\"N\n" +
+	"\x14GetFunctionsResponse\x126\n" +
+	"\tfunctions\x18\x01 \x03(\v2\x18.calculator.UserFunctionR\tfunctions\"+\n" +
+	"\x15DeleteFunctionRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"2\n" +
+	"\x16DeleteFunctionResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"5\n" +
+	"\tOperation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05arity\x18\x02 \x01(\x05R\x05arity\"\xab\x01\n" +
+	"\n" +
+	"WasmModule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash\x125\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\v2\x15.calculator.OperationR\n" +
+	"operations\x12\x1f\n" +
+	"\vuploaded_by\x18\x04 \x01(\tR\n" +
+	"uploadedBy\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"=\n" +
+	"\x13UploadModuleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04wasm\x18\x02 \x01(\fR\x04wasm\"F\n" +
+	"\x14UploadModuleResponse\x12.\n" +
+	"\x06module\x18\x01 \x01(\v2\x16.calculator.WasmModuleR\x06module\"\x13\n" +
+	"\x11GetModulesRequest\"F\n" +
+	"\x12GetModulesResponse\x120\n" +
+	"\amodules\x18\x01 \x03(\v2\x16.calculator.WasmModuleR\amodules\")\n" +
+	"\x13DeleteModuleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"0\n" +
+	"\x14DeleteModuleResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"\x95\x02\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"example_id\x18\x02 \x01(\tR\texampleId\x12\x1a\n" +
+	"\bvariable\x18\x03 \x01(\tR\bvariable\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x1b\n" +
+	"\tworker_id\x18\a \x01(\tR\bworkerId\x12\x1b\n" +
+	"\tfailed_at\x18\b \x01(\tR\bfailedAt\x12$\n" +
+	"\vreplayed_at\x18\t \x01(\tH\x00R\n" +
+	"replayedAt\x88\x01\x01B\x0e\n" +
+	"\f_replayed_at\"-\n" +
+	"\x15GetDeadLettersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"J\n" +
+	"\x16GetDeadLettersResponse\x120\n" +
+	"\aletters\x18\x01 \x03(\v2\x16.calculator.DeadLetterR\aletters\")\n" +
+	"\x17ReplayDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"J\n" +
+	"\x18ReplayDeadLetterResponse\x12.\n" +
+	"\x06letter\x18\x01 \x01(\v2\x16.calculator.DeadLetterR\x06letter\"\x15\n" +
+	"\x13RefreshRatesRequest\"\x88\x01\n" +
+	"\x14RefreshRatesResponse\x12\x1f\n" +
+	"\vsnapshot_id\x18\x01 \x01(\tR\n" +
+	"snapshotId\x12\x12\n" +
+	"\x04base\x18\x02 \x01(\tR\x04base\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x03 \x03(\tR\n" +
+	"currencies\x12\x1b\n" +
+	"\tloaded_at\x18\x04 \x01(\tR\bloadedAt\"C\n" +
+	"\x0fRegisterRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"B\n" +
+	"\x10RegisterResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"n\n" +
+	"\rLoginResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error2\xfd\x11\n" +
+	"\n" +
+	"Calculator\x12b\n" +
+	"\tCalculate\x12\x1c.calculator.CalculateRequest\x1a\x1d.calculator.CalculateResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/calculate\x12_\n" +
+	"\tGetResult\x12\x1c.calculator.GetResultRequest\x1a\x1d.calculator.GetResultResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/result\x12V\n" +
+	"\x06Cancel\x12\x19.calculator.CancelRequest\x1a\x1a.calculator.CancelResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/cancel\x12p\n" +
+	"\x0eGetAllExamples\x12!.calculator.GetAllExamplesRequest\x1a\".calculator.GetAllExamplesResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/examples\x12R\n" +
+	"\x05Sweep\x12\x18.calculator.SweepRequest\x1a\x19.calculator.SweepResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/sweep\x12t\n" +
+	"\x0eGetSweepResult\x12!.calculator.GetSweepResultRequest\x1a\".calculator.GetSweepResultResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/sweep/result\x12Z\n" +
+	"\aExplain\x12\x1a.calculator.ExplainRequest\x1a\x1b.calculator.ExplainResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/explain\x12l\n" +
+	"\vSetVariable\x12\x1e.calculator.SetVariableRequest\x1a\x1f.calculator.SetVariableResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/variables/set\x12k\n" +
+	"\fGetVariables\x12\x1f.calculator.GetVariablesRequest\x1a .calculator.GetVariablesResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/variables\x12x\n" +
+	"\x0eDeleteVariable\x12!.calculator.DeleteVariableRequest\x1a\".calculator.DeleteVariableResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/variables/delete\x12x\n" +
+	"\x0eDefineFunction\x12!.calculator.DefineFunctionRequest\x1a\".calculator.DefineFunctionResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/functions/define\x12k\n" +
+	"\fGetFunctions\x12\x1f.calculator.GetFunctionsRequest\x1a .calculator.GetFunctionsResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/functions\x12x\n" +
+	"\x0eDeleteFunction\x12!.calculator.DeleteFunctionRequest\x1a\".calculator.DeleteFunctionResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/functions/delete\x12v\n" +
+	"\fUploadModule\x12\x1f.calculator.UploadModuleRequest\x1a .calculator.UploadModuleResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/modules/upload\x12c\n" +
+	"\n" +
+	"GetModules\x12\x1d.calculator.GetModulesRequest\x1a\x1e.calculator.GetModulesResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/modules\x12v\n" +
+	"\fDeleteModule\x12\x1f.calculator.DeleteModuleRequest\x1a .calculator.DeleteModuleResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/modules/delete\x12z\n" +
+	"\x0eGetDeadLetters\x12!.calculator.GetDeadLettersRequest\x1a\".calculator.GetDeadLettersResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/admin/dead-letters\x12\x87\x01\n" +
+	"\x10ReplayDeadLetter\x12#.calculator.ReplayDeadLetterRequest\x1a$.calculator.ReplayDeadLetterResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/admin/dead-letters/replay\x12u\n" +
+	"\fRefreshRates\x12\x1f.calculator.RefreshRatesRequest\x1a .calculator.RefreshRatesResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/admin/rates/refresh\x12^\n" +
+	"\bRegister\x12\x1b.calculator.RegisterRequest\x1a\x1c.calculator.RegisterResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/register\x12R\n" +
+	"\x05Login\x12\x18.calculator.LoginRequest\x1a\x19.calculator.LoginResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/loginB2Z0github.com/tainj/distributed_calculator2/pkg/apib\x06proto3"
 
 var (
 	file_calculator_proto_rawDescOnce sync.Once
-	file_calculator_proto_rawDescData = file_calculator_proto_rawDesc
+	file_calculator_proto_rawDescData []byte
 )
 
 func file_calculator_proto_rawDescGZIP() []byte {
 	file_calculator_proto_rawDescOnce.Do(func() {
-		file_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(file_calculator_proto_rawDescData)
+		file_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_calculator_proto_rawDesc), len(file_calculator_proto_rawDesc)))
 	})
 	return file_calculator_proto_rawDescData
 }
 
-var file_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 56)
 var file_calculator_proto_goTypes = []any{
-	(*CalculateRequest)(nil),       // 0: calculator.CalculateRequest
-	(*CalculateResponse)(nil),      // 1: calculator.CalculateResponse
-	(*GetResultRequest)(nil),       // 2: calculator.GetResultRequest
-	(*GetResultResponse)(nil),      // 3: calculator.GetResultResponse
-	(*GetAllExamplesRequest)(nil),  // 4: calculator.GetAllExamplesRequest
-	(*GetAllExamplesResponse)(nil), // 5: calculator.GetAllExamplesResponse
-	(*Example)(nil),                // 6: calculator.Example
-	(*RegisterRequest)(nil),        // 7: calculator.RegisterRequest
-	(*RegisterResponse)(nil),       // 8: calculator.RegisterResponse
-	(*LoginRequest)(nil),           // 9: calculator.LoginRequest
-	(*LoginResponse)(nil),          // 10: calculator.LoginResponse
+	(*CalculateRequest)(nil),         // 0: calculator.CalculateRequest
+	(*CalculateResponse)(nil),        // 1: calculator.CalculateResponse
+	(*ResultFormat)(nil),             // 2: calculator.ResultFormat
+	(*GetResultRequest)(nil),         // 3: calculator.GetResultRequest
+	(*GetResultResponse)(nil),        // 4: calculator.GetResultResponse
+	(*CancelRequest)(nil),            // 5: calculator.CancelRequest
+	(*CancelResponse)(nil),           // 6: calculator.CancelResponse
+	(*NamedValue)(nil),               // 7: calculator.NamedValue
+	(*GetAllExamplesRequest)(nil),    // 8: calculator.GetAllExamplesRequest
+	(*GetAllExamplesResponse)(nil),   // 9: calculator.GetAllExamplesResponse
+	(*Example)(nil),                  // 10: calculator.Example
+	(*SweepRange)(nil),               // 11: calculator.SweepRange
+	(*SweepRequest)(nil),             // 12: calculator.SweepRequest
+	(*SweepResponse)(nil),            // 13: calculator.SweepResponse
+	(*GetSweepResultRequest)(nil),    // 14: calculator.GetSweepResultRequest
+	(*SweepPoint)(nil),               // 15: calculator.SweepPoint
+	(*GetSweepResultResponse)(nil),   // 16: calculator.GetSweepResultResponse
+	(*ExplainRequest)(nil),           // 17: calculator.ExplainRequest
+	(*ExplainStep)(nil),              // 18: calculator.ExplainStep
+	(*ExplainResponse)(nil),          // 19: calculator.ExplainResponse
+	(*Variable)(nil),                 // 20: calculator.Variable
+	(*SetVariableRequest)(nil),       // 21: calculator.SetVariableRequest
+	(*SetVariableResponse)(nil),      // 22: calculator.SetVariableResponse
+	(*GetVariablesRequest)(nil),      // 23: calculator.GetVariablesRequest
+	(*GetVariablesResponse)(nil),     // 24: calculator.GetVariablesResponse
+	(*DeleteVariableRequest)(nil),    // 25: calculator.DeleteVariableRequest
+	(*DeleteVariableResponse)(nil),   // 26: calculator.DeleteVariableResponse
+	(*UserFunction)(nil),             // 27: calculator.UserFunction
+	(*DefineFunctionRequest)(nil),    // 28: calculator.DefineFunctionRequest
+	(*DefineFunctionResponse)(nil),   // 29: calculator.DefineFunctionResponse
+	(*GetFunctionsRequest)(nil),      // 30: calculator.GetFunctionsRequest
+	(*GetFunctionsResponse)(nil),     // 31: calculator.GetFunctionsResponse
+	(*DeleteFunctionRequest)(nil),    // 32: calculator.DeleteFunctionRequest
+	(*DeleteFunctionResponse)(nil),   // 33: calculator.DeleteFunctionResponse
+	(*Operation)(nil),                // 34: calculator.Operation
+	(*WasmModule)(nil),               // 35: calculator.WasmModule
+	(*UploadModuleRequest)(nil),      // 36: calculator.UploadModuleRequest
+	(*UploadModuleResponse)(nil),     // 37: calculator.UploadModuleResponse
+	(*GetModulesRequest)(nil),        // 38: calculator.GetModulesRequest
+	(*GetModulesResponse)(nil),       // 39: calculator.GetModulesResponse
+	(*DeleteModuleRequest)(nil),      // 40: calculator.DeleteModuleRequest
+	(*DeleteModuleResponse)(nil),     // 41: calculator.DeleteModuleResponse
+	(*DeadLetter)(nil),               // 42: calculator.DeadLetter
+	(*GetDeadLettersRequest)(nil),    // 43: calculator.GetDeadLettersRequest
+	(*GetDeadLettersResponse)(nil),   // 44: calculator.GetDeadLettersResponse
+	(*ReplayDeadLetterRequest)(nil),  // 45: calculator.ReplayDeadLetterRequest
+	(*ReplayDeadLetterResponse)(nil), // 46: calculator.ReplayDeadLetterResponse
+	(*RefreshRatesRequest)(nil),      // 47: calculator.RefreshRatesRequest
+	(*RefreshRatesResponse)(nil),     // 48: calculator.RefreshRatesResponse
+	(*RegisterRequest)(nil),          // 49: calculator.RegisterRequest
+	(*RegisterResponse)(nil),         // 50: calculator.RegisterResponse
+	(*LoginRequest)(nil),             // 51: calculator.LoginRequest
+	(*LoginResponse)(nil),            // 52: calculator.LoginResponse
+	nil,                              // 53: calculator.Example.UserVariablesEntry
+	nil,                              // 54: calculator.Example.ReferencesEntry
+	nil,                              // 55: calculator.SweepPoint.VariablesEntry
 }
 var file_calculator_proto_depIdxs = []int32{
-	6,  // 0: calculator.GetAllExamplesResponse.examples:type_name -> calculator.Example
-	0,  // 1: calculator.Calculator.Calculate:input_type -> calculator.CalculateRequest
-	2,  // 2: calculator.Calculator.GetResult:input_type -> calculator.GetResultRequest
-	4,  // 3: calculator.Calculator.GetAllExamples:input_type -> calculator.GetAllExamplesRequest
-	7,  // 4: calculator.Calculator.Register:input_type -> calculator.RegisterRequest
-	9,  // 5: calculator.Calculator.Login:input_type -> calculator.LoginRequest
-	1,  // 6: calculator.Calculator.Calculate:output_type -> calculator.CalculateResponse
-	3,  // 7: calculator.Calculator.GetResult:output_type -> calculator.GetResultResponse
-	5,  // 8: calculator.Calculator.GetAllExamples:output_type -> calculator.GetAllExamplesResponse
-	8,  // 9: calculator.Calculator.Register:output_type -> calculator.RegisterResponse
-	10, // 10: calculator.Calculator.Login:output_type -> calculator.LoginResponse
-	6,  // [6:11] is the sub-list for method output_type
-	1,  // [1:6] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	2,  // 0: calculator.GetResultRequest.format:type_name -> calculator.ResultFormat
+	7,  // 1: calculator.GetResultResponse.bindings:type_name -> calculator.NamedValue
+	2,  // 2: calculator.GetAllExamplesRequest.format:type_name -> calculator.ResultFormat
+	10, // 3: calculator.GetAllExamplesResponse.examples:type_name -> calculator.Example
+	53, // 4: calculator.Example.user_variables:type_name -> calculator.Example.UserVariablesEntry
+	54, // 5: calculator.Example.references:type_name -> calculator.Example.ReferencesEntry
+	11, // 6: calculator.SweepRequest.ranges:type_name -> calculator.SweepRange
+	2,  // 7: calculator.GetSweepResultRequest.format:type_name -> calculator.ResultFormat
+	55, // 8: calculator.SweepPoint.variables:type_name -> calculator.SweepPoint.VariablesEntry
+	15, // 9: calculator.GetSweepResultResponse.points:type_name -> calculator.SweepPoint
+	18, // 10: calculator.ExplainResponse.steps:type_name -> calculator.ExplainStep
+	20, // 11: calculator.SetVariableResponse.variable:type_name -> calculator.Variable
+	20, // 12: calculator.GetVariablesResponse.variables:type_name -> calculator.Variable
+	27, // 13: calculator.DefineFunctionResponse.function:type_name -> calculator.UserFunction
+	27, // 14: calculator.GetFunctionsResponse.functions:type_name -> calculator.UserFunction
+	34, // 15: calculator.WasmModule.operations:type_name -> calculator.Operation
+	35, // 16: calculator.UploadModuleResponse.module:type_name -> calculator.WasmModule
+	35, // 17: calculator.GetModulesResponse.modules:type_name -> calculator.WasmModule
+	42, // 18: calculator.GetDeadLettersResponse.letters:type_name -> calculator.DeadLetter
+	42, // 19: calculator.ReplayDeadLetterResponse.letter:type_name -> calculator.DeadLetter
+	0,  // 20: calculator.Calculator.Calculate:input_type -> calculator.CalculateRequest
+	3,  // 21: calculator.Calculator.GetResult:input_type -> calculator.GetResultRequest
+	5,  // 22: calculator.Calculator.Cancel:input_type -> calculator.CancelRequest
+	8,  // 23: calculator.Calculator.GetAllExamples:input_type -> calculator.GetAllExamplesRequest
+	12, // 24: calculator.Calculator.Sweep:input_type -> calculator.SweepRequest
+	14, // 25: calculator.Calculator.GetSweepResult:input_type -> calculator.GetSweepResultRequest
+	17, // 26: calculator.Calculator.Explain:input_type -> calculator.ExplainRequest
+	21, // 27: calculator.Calculator.SetVariable:input_type -> calculator.SetVariableRequest
+	23, // 28: calculator.Calculator.GetVariables:input_type -> calculator.GetVariablesRequest
+	25, // 29: calculator.Calculator.DeleteVariable:input_type -> calculator.DeleteVariableRequest
+	28, // 30: calculator.Calculator.DefineFunction:input_type -> calculator.DefineFunctionRequest
+	30, // 31: calculator.Calculator.GetFunctions:input_type -> calculator.GetFunctionsRequest
+	32, // 32: calculator.Calculator.DeleteFunction:input_type -> calculator.DeleteFunctionRequest
+	36, // 33: calculator.Calculator.UploadModule:input_type -> calculator.UploadModuleRequest
+	38, // 34: calculator.Calculator.GetModules:input_type -> calculator.GetModulesRequest
+	40, // 35: calculator.Calculator.DeleteModule:input_type -> calculator.DeleteModuleRequest
+	43, // 36: calculator.Calculator.GetDeadLetters:input_type -> calculator.GetDeadLettersRequest
+	45, // 37: calculator.Calculator.ReplayDeadLetter:input_type -> calculator.ReplayDeadLetterRequest
+	47, // 38: calculator.Calculator.RefreshRates:input_type -> calculator.RefreshRatesRequest
+	49, // 39: calculator.Calculator.Register:input_type -> calculator.RegisterRequest
+	51, // 40: calculator.Calculator.Login:input_type -> calculator.LoginRequest
+	1,  // 41: calculator.Calculator.Calculate:output_type -> calculator.CalculateResponse
+	4,  // 42: calculator.Calculator.GetResult:output_type -> calculator.GetResultResponse
+	6,  // 43: calculator.Calculator.Cancel:output_type -> calculator.CancelResponse
+	9,  // 44: calculator.Calculator.GetAllExamples:output_type -> calculator.GetAllExamplesResponse
+	13, // 45: calculator.Calculator.Sweep:output_type -> calculator.SweepResponse
+	16, // 46: calculator.Calculator.GetSweepResult:output_type -> calculator.GetSweepResultResponse
+	19, // 47: calculator.Calculator.Explain:output_type -> calculator.ExplainResponse
+	22, // 48: calculator.Calculator.SetVariable:output_type -> calculator.SetVariableResponse
+	24, // 49: calculator.Calculator.GetVariables:output_type -> calculator.GetVariablesResponse
+	26, // 50: calculator.Calculator.DeleteVariable:output_type -> calculator.DeleteVariableResponse
+	29, // 51: calculator.Calculator.DefineFunction:output_type -> calculator.DefineFunctionResponse
+	31, // 52: calculator.Calculator.GetFunctions:output_type -> calculator.GetFunctionsResponse
+	33, // 53: calculator.Calculator.DeleteFunction:output_type -> calculator.DeleteFunctionResponse
+	37, // 54: calculator.Calculator.UploadModule:output_type -> calculator.UploadModuleResponse
+	39, // 55: calculator.Calculator.GetModules:output_type -> calculator.GetModulesResponse
+	41, // 56: calculator.Calculator.DeleteModule:output_type -> calculator.DeleteModuleResponse
+	44, // 57: calculator.Calculator.GetDeadLetters:output_type -> calculator.GetDeadLettersResponse
+	46, // 58: calculator.Calculator.ReplayDeadLetter:output_type -> calculator.ReplayDeadLetterResponse
+	48, // 59: calculator.Calculator.RefreshRates:output_type -> calculator.RefreshRatesResponse
+	50, // 60: calculator.Calculator.Register:output_type -> calculator.RegisterResponse
+	52, // 61: calculator.Calculator.Login:output_type -> calculator.LoginResponse
+	41, // [41:62] is the sub-list for method output_type
+	20, // [20:41] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_calculator_proto_init() }
@@ -777,18 +3570,28 @@ func file_calculator_proto_init() {
 	if File_calculator_proto != nil {
 		return
 	}
-	file_calculator_proto_msgTypes[3].OneofWrappers = []any{
+	file_calculator_proto_msgTypes[0].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[2].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[4].OneofWrappers = []any{
 		(*GetResultResponse_Value)(nil),
 		(*GetResultResponse_Error)(nil),
+		(*GetResultResponse_Text)(nil),
 	}
 	file_calculator_proto_msgTypes[6].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[7].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[10].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[12].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[15].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[16].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[19].OneofWrappers = []any{}
+	file_calculator_proto_msgTypes[42].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_calculator_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_calculator_proto_rawDesc), len(file_calculator_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   56,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -797,7 +3600,6 @@ func file_calculator_proto_init() {
 		MessageInfos:      file_calculator_proto_msgTypes,
 	}.Build()
 	File_calculator_proto = out.File
-	file_calculator_proto_rawDesc = nil
 	file_calculator_proto_goTypes = nil
 	file_calculator_proto_depIdxs = nil
 }
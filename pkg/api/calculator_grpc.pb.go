@@ -8,7 +8,6 @@ package api
 
 import (
 	context "context"
-
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -20,11 +19,27 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Calculator_Calculate_FullMethodName      = "/calculator.Calculator/Calculate"
-	Calculator_GetResult_FullMethodName      = "/calculator.Calculator/GetResult"
-	Calculator_GetAllExamples_FullMethodName = "/calculator.Calculator/GetAllExamples"
-	Calculator_Register_FullMethodName       = "/calculator.Calculator/Register"
-	Calculator_Login_FullMethodName          = "/calculator.Calculator/Login"
+	Calculator_Calculate_FullMethodName        = "/calculator.Calculator/Calculate"
+	Calculator_GetResult_FullMethodName        = "/calculator.Calculator/GetResult"
+	Calculator_Cancel_FullMethodName           = "/calculator.Calculator/Cancel"
+	Calculator_GetAllExamples_FullMethodName   = "/calculator.Calculator/GetAllExamples"
+	Calculator_Sweep_FullMethodName            = "/calculator.Calculator/Sweep"
+	Calculator_GetSweepResult_FullMethodName   = "/calculator.Calculator/GetSweepResult"
+	Calculator_Explain_FullMethodName          = "/calculator.Calculator/Explain"
+	Calculator_SetVariable_FullMethodName      = "/calculator.Calculator/SetVariable"
+	Calculator_GetVariables_FullMethodName     = "/calculator.Calculator/GetVariables"
+	Calculator_DeleteVariable_FullMethodName   = "/calculator.Calculator/DeleteVariable"
+	Calculator_DefineFunction_FullMethodName   = "/calculator.Calculator/DefineFunction"
+	Calculator_GetFunctions_FullMethodName     = "/calculator.Calculator/GetFunctions"
+	Calculator_DeleteFunction_FullMethodName   = "/calculator.Calculator/DeleteFunction"
+	Calculator_UploadModule_FullMethodName     = "/calculator.Calculator/UploadModule"
+	Calculator_GetModules_FullMethodName       = "/calculator.Calculator/GetModules"
+	Calculator_DeleteModule_FullMethodName     = "/calculator.Calculator/DeleteModule"
+	Calculator_GetDeadLetters_FullMethodName   = "/calculator.Calculator/GetDeadLetters"
+	Calculator_ReplayDeadLetter_FullMethodName = "/calculator.Calculator/ReplayDeadLetter"
+	Calculator_RefreshRates_FullMethodName     = "/calculator.Calculator/RefreshRates"
+	Calculator_Register_FullMethodName         = "/calculator.Calculator/Register"
+	Calculator_Login_FullMethodName            = "/calculator.Calculator/Login"
 )
 
 // CalculatorClient is the client API for Calculator service.
@@ -37,8 +52,40 @@ type CalculatorClient interface {
 	Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error)
 	// Get result - via BODY
 	GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (*GetResultResponse, error)
+	// Cancel an example: workers skip its remaining tasks
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	// Get all examples - via body
 	GetAllExamples(ctx context.Context, in *GetAllExamplesRequest, opts ...grpc.CallOption) (*GetAllExamplesResponse, error)
+	// Sweep - evaluate expression on a grid of variable values
+	Sweep(ctx context.Context, in *SweepRequest, opts ...grpc.CallOption) (*SweepResponse, error)
+	// Get sweep points - via body
+	GetSweepResult(ctx context.Context, in *GetSweepResultRequest, opts ...grpc.CallOption) (*GetSweepResultResponse, error)
+	// Explain - calculation steps of an example, via body
+	Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error)
+	// Save a variable of the user for later expressions
+	SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*SetVariableResponse, error)
+	// Get saved variables of the user - via body
+	GetVariables(ctx context.Context, in *GetVariablesRequest, opts ...grpc.CallOption) (*GetVariablesResponse, error)
+	// Delete a saved variable - via body
+	DeleteVariable(ctx context.Context, in *DeleteVariableRequest, opts ...grpc.CallOption) (*DeleteVariableResponse, error)
+	// Define a function of the user for later expressions: f(x, y) = x^2 + 3*y
+	DefineFunction(ctx context.Context, in *DefineFunctionRequest, opts ...grpc.CallOption) (*DefineFunctionResponse, error)
+	// Get functions of the user - via body
+	GetFunctions(ctx context.Context, in *GetFunctionsRequest, opts ...grpc.CallOption) (*GetFunctionsResponse, error)
+	// Delete a function - via body
+	DeleteFunction(ctx context.Context, in *DeleteFunctionRequest, opts ...grpc.CallOption) (*DeleteFunctionResponse, error)
+	// Upload a WebAssembly module with custom operations - admins only
+	UploadModule(ctx context.Context, in *UploadModuleRequest, opts ...grpc.CallOption) (*UploadModuleResponse, error)
+	// Get uploaded modules and their operations - via body
+	GetModules(ctx context.Context, in *GetModulesRequest, opts ...grpc.CallOption) (*GetModulesResponse, error)
+	// Delete a module - admins only
+	DeleteModule(ctx context.Context, in *DeleteModuleRequest, opts ...grpc.CallOption) (*DeleteModuleResponse, error)
+	// List tasks workers gave up on - admins only
+	GetDeadLetters(ctx context.Context, in *GetDeadLettersRequest, opts ...grpc.CallOption) (*GetDeadLettersResponse, error)
+	// Send the task of a dead letter to workers again - admins only
+	ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*ReplayDeadLetterResponse, error)
+	// Refresh currency rates - admins only
+	RefreshRates(ctx context.Context, in *RefreshRatesRequest, opts ...grpc.CallOption) (*RefreshRatesResponse, error)
 	// Register - via body
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	// Login - via body
@@ -73,6 +120,16 @@ func (c *calculatorClient) GetResult(ctx context.Context, in *GetResultRequest,
 	return out, nil
 }
 
+func (c *calculatorClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelResponse)
+	err := c.cc.Invoke(ctx, Calculator_Cancel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calculatorClient) GetAllExamples(ctx context.Context, in *GetAllExamplesRequest, opts ...grpc.CallOption) (*GetAllExamplesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAllExamplesResponse)
@@ -83,6 +140,156 @@ func (c *calculatorClient) GetAllExamples(ctx context.Context, in *GetAllExample
 	return out, nil
 }
 
+func (c *calculatorClient) Sweep(ctx context.Context, in *SweepRequest, opts ...grpc.CallOption) (*SweepResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SweepResponse)
+	err := c.cc.Invoke(ctx, Calculator_Sweep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) GetSweepResult(ctx context.Context, in *GetSweepResultRequest, opts ...grpc.CallOption) (*GetSweepResultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSweepResultResponse)
+	err := c.cc.Invoke(ctx, Calculator_GetSweepResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainResponse)
+	err := c.cc.Invoke(ctx, Calculator_Explain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) SetVariable(ctx context.Context, in *SetVariableRequest, opts ...grpc.CallOption) (*SetVariableResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetVariableResponse)
+	err := c.cc.Invoke(ctx, Calculator_SetVariable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) GetVariables(ctx context.Context, in *GetVariablesRequest, opts ...grpc.CallOption) (*GetVariablesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVariablesResponse)
+	err := c.cc.Invoke(ctx, Calculator_GetVariables_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) DeleteVariable(ctx context.Context, in *DeleteVariableRequest, opts ...grpc.CallOption) (*DeleteVariableResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVariableResponse)
+	err := c.cc.Invoke(ctx, Calculator_DeleteVariable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) DefineFunction(ctx context.Context, in *DefineFunctionRequest, opts ...grpc.CallOption) (*DefineFunctionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DefineFunctionResponse)
+	err := c.cc.Invoke(ctx, Calculator_DefineFunction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) GetFunctions(ctx context.Context, in *GetFunctionsRequest, opts ...grpc.CallOption) (*GetFunctionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFunctionsResponse)
+	err := c.cc.Invoke(ctx, Calculator_GetFunctions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) DeleteFunction(ctx context.Context, in *DeleteFunctionRequest, opts ...grpc.CallOption) (*DeleteFunctionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteFunctionResponse)
+	err := c.cc.Invoke(ctx, Calculator_DeleteFunction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) UploadModule(ctx context.Context, in *UploadModuleRequest, opts ...grpc.CallOption) (*UploadModuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadModuleResponse)
+	err := c.cc.Invoke(ctx, Calculator_UploadModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) GetModules(ctx context.Context, in *GetModulesRequest, opts ...grpc.CallOption) (*GetModulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetModulesResponse)
+	err := c.cc.Invoke(ctx, Calculator_GetModules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) DeleteModule(ctx context.Context, in *DeleteModuleRequest, opts ...grpc.CallOption) (*DeleteModuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteModuleResponse)
+	err := c.cc.Invoke(ctx, Calculator_DeleteModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) GetDeadLetters(ctx context.Context, in *GetDeadLettersRequest, opts ...grpc.CallOption) (*GetDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeadLettersResponse)
+	err := c.cc.Invoke(ctx, Calculator_GetDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*ReplayDeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayDeadLetterResponse)
+	err := c.cc.Invoke(ctx, Calculator_ReplayDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorClient) RefreshRates(ctx context.Context, in *RefreshRatesRequest, opts ...grpc.CallOption) (*RefreshRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshRatesResponse)
+	err := c.cc.Invoke(ctx, Calculator_RefreshRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calculatorClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterResponse)
@@ -113,8 +320,40 @@ type CalculatorServer interface {
 	Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error)
 	// Get result - via BODY
 	GetResult(context.Context, *GetResultRequest) (*GetResultResponse, error)
+	// Cancel an example: workers skip its remaining tasks
+	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	// Get all examples - via body
 	GetAllExamples(context.Context, *GetAllExamplesRequest) (*GetAllExamplesResponse, error)
+	// Sweep - evaluate expression on a grid of variable values
+	Sweep(context.Context, *SweepRequest) (*SweepResponse, error)
+	// Get sweep points - via body
+	GetSweepResult(context.Context, *GetSweepResultRequest) (*GetSweepResultResponse, error)
+	// Explain - calculation steps of an example, via body
+	Explain(context.Context, *ExplainRequest) (*ExplainResponse, error)
+	// Save a variable of the user for later expressions
+	SetVariable(context.Context, *SetVariableRequest) (*SetVariableResponse, error)
+	// Get saved variables of the user - via body
+	GetVariables(context.Context, *GetVariablesRequest) (*GetVariablesResponse, error)
+	// Delete a saved variable - via body
+	DeleteVariable(context.Context, *DeleteVariableRequest) (*DeleteVariableResponse, error)
+	// Define a function of the user for later expressions: f(x, y) = x^2 + 3*y
+	DefineFunction(context.Context, *DefineFunctionRequest) (*DefineFunctionResponse, error)
+	// Get functions of the user - via body
+	GetFunctions(context.Context, *GetFunctionsRequest) (*GetFunctionsResponse, error)
+	// Delete a function - via body
+	DeleteFunction(context.Context, *DeleteFunctionRequest) (*DeleteFunctionResponse, error)
+	// Upload a WebAssembly module with custom operations - admins only
+	UploadModule(context.Context, *UploadModuleRequest) (*UploadModuleResponse, error)
+	// Get uploaded modules and their operations - via body
+	GetModules(context.Context, *GetModulesRequest) (*GetModulesResponse, error)
+	// Delete a module - admins only
+	DeleteModule(context.Context, *DeleteModuleRequest) (*DeleteModuleResponse, error)
+	// List tasks workers gave up on - admins only
+	GetDeadLetters(context.Context, *GetDeadLettersRequest) (*GetDeadLettersResponse, error)
+	// Send the task of a dead letter to workers again - admins only
+	ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*ReplayDeadLetterResponse, error)
+	// Refresh currency rates - admins only
+	RefreshRates(context.Context, *RefreshRatesRequest) (*RefreshRatesResponse, error)
 	// Register - via body
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	// Login - via body
@@ -135,9 +374,57 @@ func (UnimplementedCalculatorServer) Calculate(context.Context, *CalculateReques
 func (UnimplementedCalculatorServer) GetResult(context.Context, *GetResultRequest) (*GetResultResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetResult not implemented")
 }
+func (UnimplementedCalculatorServer) Cancel(context.Context, *CancelRequest) (*CancelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
 func (UnimplementedCalculatorServer) GetAllExamples(context.Context, *GetAllExamplesRequest) (*GetAllExamplesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllExamples not implemented")
 }
+func (UnimplementedCalculatorServer) Sweep(context.Context, *SweepRequest) (*SweepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sweep not implemented")
+}
+func (UnimplementedCalculatorServer) GetSweepResult(context.Context, *GetSweepResultRequest) (*GetSweepResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSweepResult not implemented")
+}
+func (UnimplementedCalculatorServer) Explain(context.Context, *ExplainRequest) (*ExplainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Explain not implemented")
+}
+func (UnimplementedCalculatorServer) SetVariable(context.Context, *SetVariableRequest) (*SetVariableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariable not implemented")
+}
+func (UnimplementedCalculatorServer) GetVariables(context.Context, *GetVariablesRequest) (*GetVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVariables not implemented")
+}
+func (UnimplementedCalculatorServer) DeleteVariable(context.Context, *DeleteVariableRequest) (*DeleteVariableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVariable not implemented")
+}
+func (UnimplementedCalculatorServer) DefineFunction(context.Context, *DefineFunctionRequest) (*DefineFunctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DefineFunction not implemented")
+}
+func (UnimplementedCalculatorServer) GetFunctions(context.Context, *GetFunctionsRequest) (*GetFunctionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFunctions not implemented")
+}
+func (UnimplementedCalculatorServer) DeleteFunction(context.Context, *DeleteFunctionRequest) (*DeleteFunctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFunction not implemented")
+}
+func (UnimplementedCalculatorServer) UploadModule(context.Context, *UploadModuleRequest) (*UploadModuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadModule not implemented")
+}
+func (UnimplementedCalculatorServer) GetModules(context.Context, *GetModulesRequest) (*GetModulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModules not implemented")
+}
+func (UnimplementedCalculatorServer) DeleteModule(context.Context, *DeleteModuleRequest) (*DeleteModuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteModule not implemented")
+}
+func (UnimplementedCalculatorServer) GetDeadLetters(context.Context, *GetDeadLettersRequest) (*GetDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeadLetters not implemented")
+}
+func (UnimplementedCalculatorServer) ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*ReplayDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetter not implemented")
+}
+func (UnimplementedCalculatorServer) RefreshRates(context.Context, *RefreshRatesRequest) (*RefreshRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshRates not implemented")
+}
 func (UnimplementedCalculatorServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
@@ -201,6 +488,24 @@ func _Calculator_GetResult_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Calculator_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_Cancel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Calculator_GetAllExamples_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAllExamplesRequest)
 	if err := dec(in); err != nil {
@@ -219,6 +524,276 @@ func _Calculator_GetAllExamples_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Calculator_Sweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).Sweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_Sweep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).Sweep(ctx, req.(*SweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_GetSweepResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSweepResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).GetSweepResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_GetSweepResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).GetSweepResult(ctx, req.(*GetSweepResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_Explain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).Explain(ctx, req.(*ExplainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_SetVariable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).SetVariable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_SetVariable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).SetVariable(ctx, req.(*SetVariableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_GetVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVariablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).GetVariables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_GetVariables_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).GetVariables(ctx, req.(*GetVariablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_DeleteVariable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVariableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).DeleteVariable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_DeleteVariable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).DeleteVariable(ctx, req.(*DeleteVariableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_DefineFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DefineFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).DefineFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_DefineFunction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).DefineFunction(ctx, req.(*DefineFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_GetFunctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFunctionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).GetFunctions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_GetFunctions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).GetFunctions(ctx, req.(*GetFunctionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_DeleteFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).DeleteFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_DeleteFunction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).DeleteFunction(ctx, req.(*DeleteFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_UploadModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadModuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).UploadModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_UploadModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).UploadModule(ctx, req.(*UploadModuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_GetModules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).GetModules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_GetModules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).GetModules(ctx, req.(*GetModulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_DeleteModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteModuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).DeleteModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_DeleteModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).DeleteModule(ctx, req.(*DeleteModuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_GetDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).GetDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_GetDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).GetDeadLetters(ctx, req.(*GetDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_ReplayDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).ReplayDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_ReplayDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).ReplayDeadLetter(ctx, req.(*ReplayDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calculator_RefreshRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServer).RefreshRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calculator_RefreshRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServer).RefreshRates(ctx, req.(*RefreshRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Calculator_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterRequest)
 	if err := dec(in); err != nil {
@@ -270,10 +845,74 @@ var Calculator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetResult",
 			Handler:    _Calculator_GetResult_Handler,
 		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Calculator_Cancel_Handler,
+		},
 		{
 			MethodName: "GetAllExamples",
 			Handler:    _Calculator_GetAllExamples_Handler,
 		},
+		{
+			MethodName: "Sweep",
+			Handler:    _Calculator_Sweep_Handler,
+		},
+		{
+			MethodName: "GetSweepResult",
+			Handler:    _Calculator_GetSweepResult_Handler,
+		},
+		{
+			MethodName: "Explain",
+			Handler:    _Calculator_Explain_Handler,
+		},
+		{
+			MethodName: "SetVariable",
+			Handler:    _Calculator_SetVariable_Handler,
+		},
+		{
+			MethodName: "GetVariables",
+			Handler:    _Calculator_GetVariables_Handler,
+		},
+		{
+			MethodName: "DeleteVariable",
+			Handler:    _Calculator_DeleteVariable_Handler,
+		},
+		{
+			MethodName: "DefineFunction",
+			Handler:    _Calculator_DefineFunction_Handler,
+		},
+		{
+			MethodName: "GetFunctions",
+			Handler:    _Calculator_GetFunctions_Handler,
+		},
+		{
+			MethodName: "DeleteFunction",
+			Handler:    _Calculator_DeleteFunction_Handler,
+		},
+		{
+			MethodName: "UploadModule",
+			Handler:    _Calculator_UploadModule_Handler,
+		},
+		{
+			MethodName: "GetModules",
+			Handler:    _Calculator_GetModules_Handler,
+		},
+		{
+			MethodName: "DeleteModule",
+			Handler:    _Calculator_DeleteModule_Handler,
+		},
+		{
+			MethodName: "GetDeadLetters",
+			Handler:    _Calculator_GetDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetter",
+			Handler:    _Calculator_ReplayDeadLetter_Handler,
+		},
+		{
+			MethodName: "RefreshRates",
+			Handler:    _Calculator_RefreshRates_Handler,
+		},
 		{
 			MethodName: "Register",
 			Handler:    _Calculator_Register_Handler,
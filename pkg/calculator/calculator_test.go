@@ -1,8 +1,18 @@
 package calculator
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
 )
 
 func TestNewExample_GeneratesVariable(t *testing.T) {
@@ -257,6 +267,10 @@ func TestExpression_Check(t *testing.T) {
 		{"invalid: empty", "", false},
 		{"valid: power", "2 ^ 3", true},
 		{"invalid: trailing op", "2 +", false},
+		{"valid: variables", "x * (y + 1)", true},
+		{"invalid: missing operator", "2 (3)", false},
+		{"invalid: bad number", "1.2.3", false},
+		{"invalid: unknown symbol", "2 % 3", false},
 	}
 
 	for _, tt := range tests {
@@ -445,3 +459,1332 @@ func TestStack(t *testing.T) {
 		})
 	}
 }
+
+func TestExpression_Identifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"no variables", "2 + 3", []string{}},
+		{"one variable", "x ^ 2 + 1", []string{"x"}},
+		{"repeated variables", "x * y + x", []string{"x", "y"}},
+		{"long names", "rate_1 * 100", []string{"rate_1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := NewExpression(tt.input)
+			if _, err := expr.Convert(); err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if got := expr.Identifiers(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Identifiers() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpression_Substitute(t *testing.T) {
+	expr := NewExpression("x ^ 2 + y * x")
+	if _, err := expr.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	bound := expr.Substitute(map[string]float64{"x": 1.5, "y": -2})
+	if bound.Postfix != "1.5 2 ^ -2 1.5 * +" {
+		t.Errorf("Substitute() postfix = %q", bound.Postfix)
+	}
+	if expr.Postfix != "x 2 ^ y x * +" {
+		t.Errorf("Substitute() changed original postfix: %q", expr.Postfix)
+	}
+
+	results, _ := bound.Calculate()
+	if len(results) != 3 {
+		t.Errorf("Calculate() returned %d tasks, expected 3", len(results))
+	}
+}
+
+// runTasks executes the planned tasks one by one like workers do
+// and returns the value of the final variable
+func runTasks(t *testing.T, tasks []*models.Task, final string) (Value, error) {
+	return runTasksWithSeed(t, tasks, final, 42)
+}
+
+// runTasksWithSeed - runTasks for an example with the given seed
+func runTasksWithSeed(t *testing.T, tasks []*models.Task, final string, seed int64) (Value, error) {
+	t.Helper()
+	variables := make(map[string]Value)
+	resolve := func(ref string) Value {
+		if value, ok := ParseLiteral(ref); ok {
+			return value
+		}
+		value, ok := variables[ref]
+		if !ok {
+			t.Fatalf("variable %s is used before it is calculated", ref)
+		}
+		return value
+	}
+
+	for i, task := range tasks {
+		refs := []string{task.Num1, task.Num2}
+		if IsFunction(task.Sign) {
+			refs = task.Args
+		}
+		args := make([]Value, 0, len(refs))
+		for _, ref := range refs {
+			args = append(args, resolve(ref))
+		}
+		call := NewCall(task.Sign, args)
+		call.Seed = TaskSeed(seed, i)
+		if task.Sign == "in" {
+			call.Rates = testRates
+		}
+		result, err := call.Evaluate()
+		if err != nil {
+			return Value{}, err
+		}
+		variables[task.Variable] = result
+	}
+	return resolve(final), nil
+}
+
+// evaluate converts, plans and runs the expression
+func evaluate(t *testing.T, input string) (Value, error) {
+	t.Helper()
+	expr := NewExpression(input)
+	if _, err := expr.Convert(); err != nil {
+		return Value{}, err
+	}
+	tasks, final := expr.Calculate()
+	return runTasks(t, tasks, final)
+}
+
+func TestExpression_ConvertFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"list literal", "[1, 2, 3.5]", "1 2 3.5 list/3", false},
+		{"empty list", "[]", "list/0", false},
+		{"aggregate of list", "mean([1, 2]) * 2", "1 2 list/2 mean/1 2 *", false},
+		{"variadic aggregate", "max(1, 2 + 3, 4)", "1 2 3 + 4 max/3", false},
+		{"percentile", "percentile([1, 2], 90)", "1 2 list/2 90 percentile/2", false},
+		{"nested calls", "sum([min(1, 2), 3])", "1 2 min/2 3 list/2 sum/1", false},
+		{"unknown function is kept", "f(1, 2)", "1 2 f/2", false},
+		{"wrong arity", "percentile([1, 2])", "", true},
+		{"internal function", "_moments(1, 2)", "", true},
+		{"no arguments for mean", "mean()", "", true},
+		{"comma outside of call", "(1, 2)", "", true},
+		{"unclosed list", "[1, 2", "", true},
+		{"mismatched brackets", "[1, 2)", "", true},
+		{"trailing comma", "max(1, )", "", true},
+		{"string and unit", `date("2026-10-16") + 90 days`, `"2026-10-16" date/1 90 days/1 +`, false},
+		{"string with space", `date("2026-10-16 10:30")`, `"2026-10-16\x2010:30" date/1`, false},
+		{"conversion", "(a - b) * 2 in hours", `a b - 2 * "hours" in`, false},
+		{"money", "100 USD in EUR", `100 "USD" money/2 "EUR" in`, false},
+		{"unclosed string", `date("2026-10-16)`, "", true},
+		{"in is not a variable", "in + 1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := NewExpression(tt.input)
+			_, err := expr.Convert()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && expr.Postfix != tt.expected {
+				t.Errorf("Convert() postfix = %q, expected %q", expr.Postfix, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float64
+		wantErr  bool
+	}{
+		{"sum", "sum([1, 2, 3.5])", 6.5, false},
+		{"sum of arguments", "sum(1, 2, 3)", 6, false},
+		{"min", "min([4, ~2, 7])", -2, false},
+		{"max", "max(4, 9, 7)", 9, false},
+		{"mean", "mean([1, 2, 3, 4])", 2.5, false},
+		{"median odd", "median([5, 1, 3])", 3, false},
+		{"median even", "median([4, 1, 3, 2])", 2.5, false},
+		{"variance", "variance([2, 4, 4, 4, 5, 5, 7, 9])", 4, false},
+		{"stddev", "stddev([2, 4, 4, 4, 5, 5, 7, 9])", 2, false},
+		{"percentile", "percentile([1, 2, 3, 4, 5], 25)", 2, false},
+		{"percentile interpolation", "percentile([1, 2], 50)", 1.5, false},
+		{"in arithmetic", "mean([1, 3]) * 2 + 1", 5, false},
+		{"percentile out of range", "percentile([1, 2], 101)", 0, true},
+		{"mean of empty list", "mean([])", 0, true},
+		{"list in arithmetic", "[1, 2] + 1", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluate(t, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsBusinessError(err) {
+					t.Errorf("error %v is not a business error", err)
+				}
+				return
+			}
+			if !result.IsNumber() || math.Abs(result.Number-tt.expected) > 1e-9 {
+				t.Errorf("evaluate() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregates_ReduceTree(t *testing.T) {
+	defer func(size int) { ReduceChunkSize = size }(ReduceChunkSize)
+	ReduceChunkSize = 4
+
+	// 1..50
+	items := make([]string, 0, 50)
+	for i := 1; i <= 50; i++ {
+		items = append(items, strconv.Itoa(i))
+	}
+	list := "[" + strings.Join(items, ", ") + "]"
+
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"sum(" + list + ")", 1275},
+		{"max(" + list + ")", 50},
+		{"min(" + strings.Join(items, ", ") + ")", 1},
+		{"mean(" + list + ")", 25.5},
+		{"median(" + list + ")", 25.5},
+		{"variance(" + list + ")", 208.25},
+		{"percentile(" + list + ", 90)", 45.1},
+	}
+
+	for _, tt := range tests {
+		name := tt.input[:strings.IndexByte(tt.input, '(')]
+		t.Run(name, func(t *testing.T) {
+			expr := NewExpression(tt.input)
+			if _, err := expr.Convert(); err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			tasks, final := expr.Calculate()
+
+			for _, task := range tasks {
+				if len(task.Args) > ReduceChunkSize {
+					t.Errorf("task %s has %d operands, chunk is %d", task.Sign, len(task.Args), ReduceChunkSize)
+				}
+			}
+
+			result, err := runTasks(t, tasks, final)
+			if err != nil {
+				t.Fatalf("runTasks() error = %v", err)
+			}
+			if math.Abs(result.Number-tt.expected) > 1e-9 {
+				t.Errorf("result = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatrices(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{"literal", "[[1, 2], [3, 4]]", "[[1, 2], [3, 4]]", nil},
+		{"product", "[[1, 2], [3, 4]] * [[5], [6]]", "[[17], [39]]", nil},
+		{"sum", "[[1, 2], [3, 4]] + [[1, 1], [1, 1]]", "[[2, 3], [4, 5]]", nil},
+		{"scaling", "2 * [[1, 2], [3, 4]] / 2", "[[1, 2], [3, 4]]", nil},
+		{"unary minus", "~[[1, 2]]", "[[-1, -2]]", nil},
+		{"power", "[[1, 1], [0, 1]] ^ 3", "[[1, 3], [0, 1]]", nil},
+		{"matrix by vector", "[[1, 2], [3, 4]] * [1, 1]", "[3, 7]", nil},
+		{"vector by matrix", "[1, 1] * [[1, 2], [3, 4]]", "[4, 6]", nil},
+		{"det", "det([[2, 0], [0, 3]])", "6", nil},
+		{"det with pivoting", "det([[0, 1], [1, 0]])", "-1", nil},
+		{"det of singular", "det([[1, 2], [2, 4]])", "0", nil},
+		{"inv", "inv([[2, 0], [0, 4]])", "[[0.5, 0], [0, 0.25]]", nil},
+		{"transpose", "transpose([[1, 2, 3], [4, 5, 6]])", "[[1, 4], [2, 5], [3, 6]]", nil},
+		{"dot", "dot([1, 2, 3], [4, 5, 6])", "32", nil},
+		{"aggregate of matrix", "sum([[1, 2], [3, 4]])", "10", nil},
+		{"ragged rows", "[[1, 2], [3]]", "", ErrDimensionMismatch},
+		{"product shapes", "[[1, 2]] * [[1, 2]]", "", ErrDimensionMismatch},
+		{"sum shapes", "[[1, 2]] + [[1], [2]]", "", ErrDimensionMismatch},
+		{"det of non-square", "det([[1, 2]])", "", ErrDimensionMismatch},
+		{"inv of singular", "inv([[1, 2], [2, 4]])", "", ErrSingularMatrix},
+		{"dot lengths", "dot([1, 2], [1])", "", ErrDimensionMismatch},
+		{"number divided by matrix", "1 / [[1, 2]]", "", ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluate(t, tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) || !IsBusinessError(err) {
+					t.Fatalf("evaluate() error = %v, expected %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluate() error = %v", err)
+			}
+			if result.String() != tt.expected {
+				t.Errorf("evaluate() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatrices_BlockMultiply(t *testing.T) {
+	defer func(rows int) { MatrixBlockRows = rows }(MatrixBlockRows)
+	MatrixBlockRows = 2
+
+	// 5x2 matrix with rows [i, 1] times [[1], [10]] gives [[i + 10]]
+	rows := make([]string, 0, 5)
+	expected := make([]string, 0, 5)
+	for i := 1; i <= 5; i++ {
+		rows = append(rows, "["+strconv.Itoa(i)+", 1]")
+		expected = append(expected, "["+strconv.Itoa(i+10)+"]")
+	}
+	expr := NewExpression("[" + strings.Join(rows, ", ") + "] * [[1], [10]]")
+	if _, err := expr.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	tasks, final := expr.Calculate()
+
+	products := 0
+	for _, task := range tasks {
+		if task.Sign == "*" {
+			products++
+		}
+		if task.Sign == "list" && len(task.Args) == 5 {
+			t.Errorf("the whole matrix is still built by one task")
+		}
+	}
+	if products != 3 {
+		t.Errorf("got %d block products, expected 3", products)
+	}
+
+	result, err := runTasks(t, tasks, final)
+	if err != nil {
+		t.Fatalf("runTasks() error = %v", err)
+	}
+	if want := "[" + strings.Join(expected, ", ") + "]"; result.String() != want {
+		t.Errorf("result = %v, expected %v", result, want)
+	}
+}
+
+func TestNumberTheory(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"gcd", "gcd(12, 18, 30)", "6", false},
+		{"gcd with negative", "gcd(~12, 18)", "6", false},
+		{"lcm", "lcm(4, 6, 10)", "60", false},
+		{"lcm with zero", "lcm(4, 0)", "0", false},
+		{"modpow", "modpow(4, 13, 497)", "445", false},
+		{"modpow big", "modpow(2, 1000, 1000000007)", "688423210", false},
+		{"modular inverse", "modpow(3, ~1, 11)", "4", false},
+		{"isprime", "isprime(97)", "1", false},
+		{"isprime composite", "isprime(91)", "0", false},
+		{"mersenne prime", "isprime(2 ^ 127 - 1)", "1", false},
+		{"factor", "factor(360)", "[2, 2, 2, 3, 3, 5]", false},
+		{"factor of big semiprime", "factor(1000000016000000063)", "[1000000007, 1000000009]", false},
+		{"factor of one", "factor(1)", "[]", false},
+		{"nCr", "nCr(5, 2)", "10", false},
+		{"nCr big", "nCr(100, 50)", "100891344545564193334812497256", false},
+		{"nCr k > n", "nCr(2, 5)", "0", false},
+		{"big literal", "123456789012345678901234567890 + 1", "123456789012345678901234567891", false},
+		{"exact power", "2 ^ 64", "18446744073709551616", false},
+		{"exact division", "2 ^ 64 / 2 ^ 60", "16", false},
+		{"inexact division", "2 ^ 64 / 3", "6.148914691236517e+18", false},
+		{"fraction argument", "gcd(1.5, 3)", "", true},
+		{"zero modulus", "modpow(2, 3, 0)", "", true},
+		{"no inverse", "modpow(2, ~1, 4)", "", true},
+		{"factor of zero", "factor(0)", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluate(t, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsBusinessError(err) {
+					t.Errorf("error %v is not a business error", err)
+				}
+				return
+			}
+			if result.String() != tt.expected {
+				t.Errorf("evaluate() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	run := func(input string, seed int64) Value {
+		t.Helper()
+		expr := NewExpression(input)
+		if _, err := expr.Convert(); err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		tasks, final := expr.Calculate()
+		result, err := runTasksWithSeed(t, tasks, final, seed)
+		if err != nil {
+			t.Fatalf("runTasks() error = %v", err)
+		}
+		return result
+	}
+
+	t.Run("same seed reproduces the result", func(t *testing.T) {
+		input := "rand() + randint(1, 6) * normal(0, 1)"
+		if first, second := run(input, 7), run(input, 7); first.Number != second.Number {
+			t.Errorf("results differ: %v and %v", first, second)
+		}
+		if first, other := run(input, 7), run(input, 8); first.Number == other.Number {
+			t.Errorf("different seeds give the same result %v", first)
+		}
+	})
+
+	t.Run("every call draws its own number", func(t *testing.T) {
+		if result := run("rand() - rand()", 7); result.Number == 0 {
+			t.Errorf("two calls returned the same number")
+		}
+	})
+
+	t.Run("ranges", func(t *testing.T) {
+		for seed := int64(0); seed < 200; seed++ {
+			if result := run("rand()", seed); result.Number < 0 || result.Number >= 1 {
+				t.Fatalf("rand() = %v", result)
+			}
+			result := run("randint(~2, 2)", seed)
+			if result.Number < -2 || result.Number > 2 || result.Number != math.Trunc(result.Number) {
+				t.Fatalf("randint(~2, 2) = %v", result)
+			}
+		}
+		if result := run("normal(5, 0)", 1); result.Number != 5 {
+			t.Errorf("normal(5, 0) = %v", result)
+		}
+	})
+
+	t.Run("monte carlo", func(t *testing.T) {
+		calls := make([]string, 2000)
+		for i := range calls {
+			calls[i] = "rand()"
+		}
+		result := run("mean(["+strings.Join(calls, ", ")+"])", 3)
+		if math.Abs(result.Number-0.5) > 0.05 {
+			t.Errorf("mean of uniform numbers = %v", result)
+		}
+	})
+
+	for _, input := range []string{"randint(3, 1)", "randint(1.5, 3)", "normal(0, ~1)", "rand(1)"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := evaluate(t, input); err == nil || !IsBusinessError(err) {
+				t.Errorf("evaluate() error = %v, expected a business error", err)
+			}
+		})
+	}
+}
+
+func TestDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"date plus days", `date("2026-10-16") + 90 days`, "2027-01-14", false},
+		{"days before date", `90 days + date("2026-10-16")`, "2027-01-14", false},
+		{"date minus weeks", `date("2026-10-16") - 2 weeks`, "2026-10-02", false},
+		{"difference in days", `(date("2027-01-01") - date("2026-10-16")) in days`, "77", false},
+		{"difference in hours", `date("2027-01-01") - date("2026-12-31 12:00") in hours`, "12", false},
+		{"time of day", `date("2026-10-16 10:30") + 90 minutes`, "2026-10-16T12:00:00Z", false},
+		{"time zone", `date("2026-10-16T10:30:00+03:00")`, "2026-10-16T07:30:00Z", false},
+		{"duration sum", "2 weeks + 3 days", "17 days", false},
+		{"duration call", "days(1.5)", "36h0m0s", false},
+		{"duration scaling", "1 day * 2", "2 days", false},
+		{"durations ratio", "(1 week) / (1 day)", "7", false},
+		{"negative duration", "~(2 days)", "-2 days", false},
+		{"workdays", `workdays(date("2026-10-16"), date("2026-10-26"))`, "6", false},
+		{"workdays backwards", `workdays(date("2026-10-26"), date("2026-10-16"))`, "-6", false},
+		{"workdays of weeks", `workdays(date("2026-10-16"), date("2026-10-16") + 10 weeks)`, "50", false},
+		{"add workdays over weekend", `addworkdays(date("2026-10-16"), 1)`, "2026-10-19", false},
+		{"add workdays from saturday", `addworkdays(date("2026-10-17"), 1)`, "2026-10-19", false},
+		{"add workdays weeks", `addworkdays(date("2026-10-16"), 10)`, "2026-10-30", false},
+		{"subtract workdays", `addworkdays(date("2026-10-19"), ~1)`, "2026-10-16", false},
+		{"bad date", `date("16.10.2026")`, "", true},
+		{"date plus number", `date("2026-10-16") + 1`, "", true},
+		{"date times number", `date("2026-10-16") * 2`, "", true},
+		{"number in hours", "5 in hours", "", true},
+		{"unknown unit", `1 day in "parsecs"`, "", true},
+		{"duration overflow", "1000 weeks * 1000000", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluate(t, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsBusinessError(err) {
+					t.Errorf("error %v is not a business error", err)
+				}
+				return
+			}
+			if result.String() != tt.expected {
+				t.Errorf("evaluate() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("now", func(t *testing.T) {
+		result, err := evaluate(t, "now()")
+		if err != nil || result.Kind != KindTime || time.Since(result.Time) > time.Minute {
+			t.Errorf("now() = %v, %v", result, err)
+		}
+	})
+}
+
+func TestOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"power overflow", "2.5 ^ 1000", ErrOverflow},
+		{"fraction overflow", "0.5 ^ ~1100", ErrOverflow},
+		{"negative overflow", "(~2.5) ^ 1001", ErrOverflow},
+		{"root of negative", "(~8) ^ 0.5", ErrDomain},
+		{"inside function", "sum([2.5 ^ 774, 2.5 ^ 774])", ErrOverflow},
+		{"inside matrix", "[[2.5 ^ 500]] * 2.5 ^ 500", ErrOverflow},
+		{"large but finite", "2.5 ^ 774", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evaluate(t, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("evaluate() error = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil && !IsBusinessError(err) {
+				t.Errorf("error %v is not a business error", err)
+			}
+		})
+	}
+
+	// planned like the service plans examples and calculated like workers do:
+	// an integer power stays exact however large, other powers overflow
+	t.Run("planned", func(t *testing.T) {
+		planned := []struct {
+			name     string
+			input    string
+			expected string
+			wantErr  error
+		}{
+			{"integer power is exact", "10 ^ 400", "1" + strings.Repeat("0", 400), nil},
+			{"fraction power overflows", "2.5 ^ 1000", "", ErrOverflow},
+			{"root of negative", "(~8) ^ 0.5", "", ErrDomain},
+		}
+		for _, tt := range planned {
+			plan, err := PlanScript(tt.input, SyntaxInfix, Scope{})
+			if err != nil {
+				t.Fatalf("%s: PlanScript() error = %v", tt.name, err)
+			}
+			result, err := runTasks(t, plan.Tasks, plan.Variable)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s: error = %v, expected %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && (result.Kind != KindInt || result.String() != tt.expected) {
+				t.Errorf("%s: result = %v (%s), expected the exact integer", tt.name, result, result.Kind)
+			}
+		}
+	})
+
+	t.Run("ieee", func(t *testing.T) {
+		ieee := []struct {
+			name     string
+			sign     string
+			args     []Value
+			expected string
+			wantErr  error
+		}{
+			{"infinity", "^", []Value{NumberValue(2.5), NumberValue(1000)}, "+Inf", nil},
+			{"nan", "^", []Value{NumberValue(-8), NumberValue(0.5)}, "NaN", nil},
+			{"infinity in list", "list", []Value{NumberValue(math.Inf(-1))}, "[-Inf]", nil},
+			{"matrix stays strict", "*", []Value{MatrixValue([][]float64{{1e300}}), NumberValue(1e300)}, "", ErrOverflow},
+			{"division by zero stays", "/", []Value{NumberValue(1), NumberValue(0)}, "", ErrDivisionByZero},
+		}
+		for _, tt := range ieee {
+			call := NewCall(tt.sign, tt.args)
+			call.IEEE = true
+			result, err := call.Evaluate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s: Evaluate() error = %v, expected %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && result.String() != tt.expected {
+				t.Errorf("%s: Evaluate() = %v, expected %v", tt.name, result, tt.expected)
+			}
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	limits := Limits{MaxLength: 40, MaxTokens: 15, MaxDepth: 3, MaxTasks: 5, MaxExponent: 1000}
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"small", "2 + 2 * 2", false},
+		{"too long", strings.Repeat("1+", 20) + "1", true},
+		{"too many tokens", "1+1+1+1+1+1+1+1+1", true},
+		{"nested", "(((1)))", false},
+		{"too deep", "((((1))))", true},
+		{"too deep lists", "[[[[1]]]]", true},
+		{"too many tasks", "1+2+3+4+5+6+7", true},
+		{"literal exponent", "2 ^ 1000", false},
+		{"big literal exponent", "2 ^ 1001", true},
+		{"negative exponent", "2 ^ ~1001", true},
+		{"tower", "9 ^ 9 ^ 9", true},
+		{"computed exponent", "2 ^ (10 * 200)", true},
+		{"variable exponent", "2 ^ x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.CheckInput(tt.input)
+			if err == nil {
+				expr := NewExpression(tt.input)
+				if _, err := expr.Convert(); err != nil {
+					t.Fatal(err)
+				}
+				tasks, _ := expr.Calculate()
+				err = limits.CheckTasks(tasks)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrLimitExceeded) {
+				t.Errorf("error %v is not ErrLimitExceeded", err)
+			}
+		})
+	}
+
+	t.Run("zero is no limit", func(t *testing.T) {
+		if err := (Limits{}).CheckInput(strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100)); err != nil {
+			t.Errorf("CheckInput() error = %v", err)
+		}
+	})
+}
+
+func TestSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"priorities", "2 + 2 * 2", []string{"2 * 2", "2 + 2 * 2"}},
+		{"brackets", "(2 + 2) * 2", []string{"2 + 2", "(2 + 2) * 2"}},
+		{"left associative", "8 - (3 - 1)", []string{"3 - 1", "8 - (3 - 1)"}},
+		{"right associative", "2 ^ 3 ^ 2", []string{"3 ^ 2", "2 ^ 3 ^ 2"}},
+		{"unary minus", "~5 + 1", []string{"0 - 5", "0 - 5 + 1"}},
+		{"calls and lists", "max([1, 2]) + 1", []string{"[1, 2]", "max([1, 2])", "max([1, 2]) + 1"}},
+		{"units", `date("2026-10-16") + 90 days`, []string{`date("2026-10-16")`, "90 days", `date("2026-10-16") + 90 days`}},
+		{"money", "100 USD in EUR", []string{"100 USD", "100 USD in EUR"}},
+		{"aggregate", "mean([1, 2, 3]) + 1", []string{"", "", "", "mean([1, 2, 3])", "mean([1, 2, 3]) + 1"}},
+		{"percentile", "percentile([1, 2, 3], 90)", []string{"", "", "", "percentile([1, 2, 3], 90)"}},
+		{"matrix product", "[[1, 2], [3, 4]] * [[1], [1]]", []string{
+			"[1, 2]", "[3, 4]", "[1]", "[1]", "[[1], [1]]", "", "", "", "", "[[1, 2], [3, 4]] * [[1], [1]]"}},
+	}
+	defer func(size, rows int) { ReduceChunkSize, MatrixBlockRows = size, rows }(ReduceChunkSize, MatrixBlockRows)
+	ReduceChunkSize, MatrixBlockRows = 2, 1
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := NewExpression(tt.input)
+			if _, err := expr.Convert(); err != nil {
+				t.Fatal(err)
+			}
+			tasks, _ := expr.Calculate()
+			if got := Sources(tasks); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Sources() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatStep(t *testing.T) {
+	tests := []struct {
+		sign     string
+		operands []Value
+		expected string
+	}{
+		{"*", []Value{NumberValue(2), NumberValue(2)}, "2 * 2"},
+		{"mean", []Value{NumbersValue([]float64{1, 2})}, "mean([1, 2])"},
+		{"list", []Value{NumberValue(1), NumberValue(2)}, "[1, 2]"},
+		{"date", []Value{StringValue("2026-10-16")}, `date("2026-10-16")`},
+		{"in", []Value{MoneyValue(100, "USD"), StringValue("EUR")}, "100.00 USD in EUR"},
+	}
+
+	for _, tt := range tests {
+		operands := make([]string, len(tt.operands))
+		for i, operand := range tt.operands {
+			operands[i] = operand.Literal()
+		}
+		if got := FormatStep(tt.sign, operands); got != tt.expected {
+			t.Errorf("FormatStep(%s) = %q, expected %q", tt.sign, got, tt.expected)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	places := func(n int) *int { return &n }
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	tests := []struct {
+		name     string
+		format   Format
+		value    Value
+		expected string
+	}{
+		{"zero format", Format{}, NumberValue(1e21), "1e+21"},
+		{"decimals", Format{Decimals: places(2)}, NumberValue(3.14159), "3.14"},
+		{"decimals padded", Format{Decimals: places(3)}, NumberValue(2.5), "2.500"},
+		{"half even", Format{Decimals: places(0)}, NumberValue(2.5), "2"},
+		{"half even odd", Format{Decimals: places(0)}, NumberValue(3.5), "4"},
+		{"half up", Format{Decimals: places(2), Rounding: RoundHalfUp}, NumberValue(2.675), "2.68"},
+		{"half down", Format{Decimals: places(0), Rounding: RoundHalfDown}, NumberValue(2.5), "2"},
+		{"up", Format{Decimals: places(1), Rounding: RoundUp}, NumberValue(-1.21), "-1.3"},
+		{"down", Format{Decimals: places(1), Rounding: RoundDown}, NumberValue(-1.29), "-1.2"},
+		{"ceiling", Format{Decimals: places(1), Rounding: RoundCeiling}, NumberValue(-1.29), "-1.2"},
+		{"floor", Format{Decimals: places(1), Rounding: RoundFloor}, NumberValue(-1.21), "-1.3"},
+		{"below precision", Format{Decimals: places(2)}, NumberValue(0.004), "0.00"},
+		{"below precision up", Format{Decimals: places(2), Rounding: RoundUp}, NumberValue(0.004), "0.01"},
+		{"no negative zero", Format{Decimals: places(1)}, NumberValue(-0.01), "0.0"},
+		{"carry", Format{Decimals: places(1)}, NumberValue(9.96), "10.0"},
+		{"significant", Format{Digits: 3}, NumberValue(1234.5), "1230"},
+		{"significant small", Format{Digits: 2}, NumberValue(0.0012345), "0.0012"},
+		{"significant padded", Format{Digits: 3}, NumberValue(2.5), "2.50"},
+		{"significant carry", Format{Digits: 3}, NumberValue(9.996), "10.0"},
+		{"fixed", Format{Notation: NotationFixed}, NumberValue(1e21), "1000000000000000000000"},
+		{"auto big", Format{Digits: 3}, NumberValue(1.23456e30), "1.23e30"},
+		{"scientific", Format{Notation: NotationScientific}, NumberValue(1234.5), "1.2345e3"},
+		{"scientific digits", Format{Notation: NotationScientific, Digits: 3}, NumberValue(0.00012345), "1.23e-4"},
+		{"scientific decimals", Format{Notation: NotationScientific, Decimals: places(1)}, NumberValue(9.96), "1.0e1"},
+		{"scientific zero", Format{Notation: NotationScientific, Decimals: places(2)}, NumberValue(0), "0.00e0"},
+		{"engineering", Format{Notation: NotationEngineering}, NumberValue(12345), "12.345e3"},
+		{"engineering small", Format{Notation: NotationEngineering, Digits: 3}, NumberValue(0.00012345), "123e-6"},
+		{"fraction", Format{Notation: NotationFraction}, NumberValue(0.375), "3/8"},
+		{"fraction third", Format{Notation: NotationFraction}, NumberValue(-1.0 / 3), "-1/3"},
+		{"fraction pi", Format{Notation: NotationFraction}, NumberValue(math.Pi), "355/113"},
+		{"fraction bounded", Format{Notation: NotationFraction, MaxDenominator: 10}, NumberValue(math.Pi), "22/7"},
+		{"fraction integer", Format{Notation: NotationFraction}, NumberValue(42), "42"},
+		{"infinity", Format{Decimals: places(2)}, NumberValue(math.Inf(1)), "+Inf"},
+		{"list", Format{Decimals: places(1)}, NumbersValue([]float64{1, 2.25}), "[1.0, 2.2]"},
+		{"matrix", Format{Notation: NotationFraction}, MatrixValue([][]float64{{0.5, 0.25}}), "[[1/2, 1/4]]"},
+		{"big int", Format{Notation: NotationScientific, Digits: 4}, IntValue(large), "1.235e29"},
+		{"money", Format{Decimals: places(0)}, MoneyValue(99.5, "USD"), "100 USD"},
+		{"money default", Format{}, MoneyValue(99.5, "USD"), "99.50 USD"},
+		{"string", Format{Decimals: places(2)}, StringValue("x"), "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.format.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.format.Value(tt.value); got != tt.expected {
+				t.Errorf("Value(%s) = %q, expected %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+
+	invalid := []Format{
+		{Digits: 3, Decimals: places(2)},
+		{Decimals: places(-1)},
+		{Digits: 1000},
+		{Notation: "roman"},
+		{Rounding: "sideways"},
+	}
+	for _, format := range invalid {
+		if err := format.Validate(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Validate(%+v) error = %v, expected ErrInvalidArgument", format, err)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	ru, _ := LocaleByName("ru-RU")
+	de, _ := LocaleByName("de")
+
+	canonical := []struct {
+		locale   Locale
+		input    string
+		expected string
+	}{
+		{Locale{}, "max(1,2)", "max(1,2)"},
+		{Locales["en"], "3.14 * 2", "3.14 * 2"},
+		{ru, "3,14 * 2", "3.14 * 2"},
+		{ru, "1 000 000 + 0,5", "1000000 + 0.5"},
+		{ru, "1\u00a0000 + 1\u202f000", "1000 + 1000"},
+		{ru, "max(1; 2,5)", "max(1, 2.5)"},
+		{ru, "[1,5; 2]", "[1.5, 2]"},
+		{ru, "x1 + 2,5", "x1 + 2.5"},
+		{ru, `money(1 000,5; "USD")`, `money(1000.5, "USD")`},
+		{ru, `concat("a;b", "1,5")`, `concat("a;b", "1,5")`},
+		{ru, "90 days", "90 days"},
+		{de, "1.000,5 + 2", "1000.5 + 2"},
+	}
+	for _, tt := range canonical {
+		got, err := tt.locale.Canonical(tt.input, SyntaxInfix)
+		if err != nil {
+			t.Errorf("Canonical(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Canonical(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+		if _, err := NewExpression(got).Convert(); err != nil {
+			t.Errorf("Convert(%q) error = %v", got, err)
+		}
+	}
+
+	// a separator outside brackets separates statements of a script
+	if got, _ := ru.Canonical("a = 1,5; max(a; 2)", SyntaxInfix); got != "a = 1.5; max(a, 2)" {
+		t.Errorf("Canonical(script) = %q", got)
+	}
+	localized := []struct {
+		locale   Locale
+		input    string
+		expected string
+	}{
+		{Locale{}, "max(1.5, 2)", "max(1.5, 2)"},
+		{ru, "max(3.14, 1000)", "max(3,14; 1000)"},
+		{ru, "a = 1.5; max(a, 2)", "a = 1,5; max(a; 2)"},
+		{ru, `concat("a,b", "1.5") + x1.5`, `concat("a,b"; "1.5") + x1.5`},
+		{de, "[[1.5, 2], [3, 4]]", "[[1,5; 2]; [3; 4]]"},
+	}
+	for _, tt := range localized {
+		if got := tt.locale.Localize(tt.input); got != tt.expected {
+			t.Errorf("Localize(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+	// a space separates operands of RPN and S-expressions, it never groups digits
+	syntaxes := []struct {
+		locale   Locale
+		syntax   Syntax
+		input    string
+		expected string
+		result   float64
+	}{
+		{ru, SyntaxRPN, "100 200 +", "100 200 +", 300},
+		{ru, SyntaxRPN, "1,5 2 *", "1.5 2 *", 3},
+		{ru, SyntaxSexpr, "(max 100 200)", "(max 100 200)", 200},
+		{ru, SyntaxSexpr, "(+ 0,5 1 000)", "(+ 0.5 1 000)", 1.5}, // three operands
+		{de, SyntaxSexpr, "(+ 1.000 2)", "(+ 1000 2)", 1002},
+	}
+	for _, tt := range syntaxes {
+		got, err := tt.locale.Canonical(tt.input, tt.syntax)
+		if err != nil {
+			t.Errorf("Canonical(%q, %s) error = %v", tt.input, tt.syntax, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Canonical(%q, %s) = %q, expected %q", tt.input, tt.syntax, got, tt.expected)
+			continue
+		}
+		plan, err := PlanScript(got, tt.syntax, Scope{})
+		if err != nil {
+			t.Errorf("PlanScript(%q, %s) error = %v", got, tt.syntax, err)
+			continue
+		}
+		value, err := runTasks(t, plan.Tasks, plan.Variable)
+		if err != nil || toFloat(value) != tt.result {
+			t.Errorf("%q in %s = %v, %v, expected %g", tt.input, tt.syntax, value, err, tt.result)
+		}
+	}
+	if _, err := de.Canonical("1.5 + 2", SyntaxInfix); !errors.Is(err, ErrCovertExample) {
+		t.Errorf("Canonical(1.5) in de error = %v, expected ErrCovertExample", err)
+	}
+	if _, err := LocaleByName("xx"); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("LocaleByName(xx) error = %v, expected ErrUnknownLocale", err)
+	}
+
+	decimals := 2
+	formatted := []struct {
+		format   Format
+		value    Value
+		expected string
+	}{
+		{Format{Locale: ru}, NumberValue(1234567.5), "1\u00a0234\u00a0567,5"},
+		{Format{Locale: ru}, NumberValue(1000), "1000"},
+		{Format{Locale: Locales["en"], Decimals: &decimals}, NumberValue(12345.678), "12,345.68"},
+		{Format{Locale: de, Notation: NotationScientific}, NumberValue(1234.5), "1,2345e3"},
+		{Format{Locale: ru}, NumbersValue([]float64{1.5, 2}), "[1,5; 2]"},
+		{Format{Locale: ru}, MoneyValue(99.5, "USD"), "99,50 USD"},
+		{Format{Locale: ru}, NumberValue(-0.25), "-0,25"},
+	}
+	for _, tt := range formatted {
+		if got := tt.format.Value(tt.value); got != tt.expected {
+			t.Errorf("Value(%s) in %s = %q, expected %q", tt.value, tt.format.Locale.Name, got, tt.expected)
+		}
+	}
+
+	_, err := NewExpression("2 + * 3").Convert()
+	if got := ru.Translate(err.Error()); !strings.HasPrefix(got, "строка не является математическим выражением") || !strings.Contains(got, "в позиции") {
+		t.Errorf("Translate(%q) = %q", err, got)
+	}
+	if got := ru.Translate(ErrDivisionByZero.Error()); got != "деление на ноль" {
+		t.Errorf("Translate(division by zero) = %q", got)
+	}
+	if got := de.Translate(ErrDivisionByZero.Error()); got != ErrDivisionByZero.Error() {
+		t.Errorf("Translate(division by zero) in de = %q, expected English", got)
+	}
+	AddTranslation("ru", `custom failure of (\S+)`, "ошибка $1")
+	if got := ru.Translate("custom failure of x: division by zero"); got != "ошибка x: деление на ноль" {
+		t.Errorf("Translate(added) = %q", got)
+	}
+	if got := de.Translate("custom failure of x"); got != "custom failure of x" {
+		t.Errorf("Translate(added) in de = %q, expected English", got)
+	}
+}
+
+func TestSyntax(t *testing.T) {
+	same := []struct {
+		syntax Syntax
+		input  string
+		infix  string
+	}{
+		{SyntaxRPN, "3 4 + 2 *", "(3 + 4) * 2"},
+		{SyntaxRPN, "2 3 4 ^ ^", "2 ^ 3 ^ 4"},
+		{SyntaxRPN, "5 ~ 2 -", "~5 - 2"},
+		{SyntaxRPN, "7 isprime x +", "isprime(7) + x"},
+		{SyntaxRPN, "1 2 3 mean(3)", "mean(1, 2, 3)"},
+		{SyntaxRPN, "[1 2 3] sum(1)", "sum([1, 2, 3])"},
+		{SyntaxRPN, "[[1 2] [3 4]]", "[[1, 2], [3, 4]]"},
+		{SyntaxRPN, `"2026-10-16" date 90 days +`, `date("2026-10-16") + 90 days`},
+		{SyntaxRPN, `100 USD "EUR" in`, "100 USD in EUR"},
+		{SyntaxSexpr, "(* (+ 3 4) 2)", "(3 + 4) * 2"},
+		{SyntaxSexpr, "(+ 1 2 3)", "1 + 2 + 3"},
+		{SyntaxSexpr, "(- 10 2 3)", "10 - 2 - 3"},
+		{SyntaxSexpr, "(- x)", "~x"},
+		{SyntaxSexpr, "(^ 2 (^ 3 4))", "2 ^ 3 ^ 4"},
+		{SyntaxSexpr, "(mean 1 2 3)", "mean(1, 2, 3)"},
+		{SyntaxSexpr, "(sum [1 2 3])", "sum([1, 2, 3])"},
+		{SyntaxSexpr, "(rand)", "rand()"},
+		{SyntaxSexpr, `(in (- (date "2027-01-01") (date "2026-10-16")) hours)`, `(date("2027-01-01") - date("2026-10-16")) in hours`},
+		{SyntaxSexpr, "42", "42"},
+	}
+	for _, tt := range same {
+		expr := &Expression{Infix: tt.input, Syntax: tt.syntax}
+		if _, err := expr.Convert(); err != nil {
+			t.Errorf("Convert(%s %q) error = %v", tt.syntax, tt.input, err)
+			continue
+		}
+		infix := NewExpression(tt.infix)
+		if _, err := infix.Convert(); err != nil {
+			t.Fatalf("Convert(%q) error = %v", tt.infix, err)
+		}
+		if expr.Postfix != infix.Postfix {
+			t.Errorf("Convert(%s %q) = %q, expected %q", tt.syntax, tt.input, expr.Postfix, infix.Postfix)
+		}
+	}
+
+	invalid := []struct {
+		syntax Syntax
+		input  string
+	}{
+		{SyntaxRPN, "3 +"},
+		{SyntaxRPN, "3 4"},
+		{SyntaxRPN, "3 4 + +"},
+		{SyntaxRPN, "1 2 mean"},
+		{SyntaxRPN, "[1 2"},
+		{SyntaxRPN, "[1 +] 2"},
+		{SyntaxRPN, "(3 4 +)"},
+		{SyntaxRPN, ""},
+		{SyntaxSexpr, "(+ 1)"},
+		{SyntaxSexpr, "(^ 1 2 3)"},
+		{SyntaxSexpr, "(+ 1 2"},
+		{SyntaxSexpr, "(+ 1 2))"},
+		{SyntaxSexpr, "(1 2)"},
+		{SyntaxSexpr, "(isprime 1 2)"},
+		{SyntaxSexpr, "1 2"},
+		{SyntaxSexpr, "()"},
+	}
+	for _, tt := range invalid {
+		expr := &Expression{Infix: tt.input, Syntax: tt.syntax}
+		if _, err := expr.Convert(); err == nil {
+			t.Errorf("Convert(%s %q) = %q, expected an error", tt.syntax, tt.input, expr.Postfix)
+		}
+	}
+
+	if _, err := ParseSyntax("polish"); !errors.Is(err, ErrUnknownSyntax) {
+		t.Errorf("ParseSyntax(polish) error = %v, expected ErrUnknownSyntax", err)
+	}
+	if syntax, _ := ParseSyntax(""); syntax != SyntaxInfix {
+		t.Errorf("ParseSyntax(\"\") = %q, expected infix", syntax)
+	}
+}
+
+// testRates - units of each currency for one USD
+var testRates = map[string]float64{"USD": 1, "EUR": 0.8, "JPY": 150}
+
+func TestScript(t *testing.T) {
+	statements, err := SplitScript("r = 3; area = pi * r ^ 2\nmax(r, 2); \"a;b\" ;")
+	if err != nil {
+		t.Fatalf("SplitScript() error = %v", err)
+	}
+	expected := []Statement{{"r", "3"}, {"area", "pi * r ^ 2"}, {"", "max(r, 2)"}, {"", `"a;b"`}}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("SplitScript() = %v, expected %v", statements, expected)
+	}
+	if IsScript("2 + 2") || !IsScript("x = 2") || !IsScript("1; 2") {
+		t.Errorf("IsScript() is wrong")
+	}
+
+	plan, err := PlanScript("r = 3; area = pi * r ^ 2; r = area * 2; r + 1", SyntaxInfix, Scope{})
+	if err != nil {
+		t.Fatalf("PlanScript() error = %v", err)
+	}
+	if len(plan.Bindings) != 2 || plan.Bindings[0].Name != "r" || plan.Bindings[1].Name != "area" {
+		t.Fatalf("Bindings = %v, expected r and area", plan.Bindings)
+	}
+	// r is assigned twice: its ref is the task of area * 2, which uses area
+	variables := make(map[string]*models.Task)
+	for _, task := range plan.Tasks {
+		variables[task.Variable] = task
+	}
+	area, r := variables[plan.Bindings[1].Ref], variables[plan.Bindings[0].Ref]
+	if area == nil || r == nil || r.Num1 != area.Variable || r.Sign != "*" {
+		t.Errorf("Bindings = %v, expected refs of the tasks of area and area * 2", plan.Bindings)
+	}
+	if last := plan.Tasks[len(plan.Tasks)-1]; last.Variable != plan.Variable || last.Num1 != r.Variable {
+		t.Errorf("Variable = %q, expected the task of r + 1", plan.Variable)
+	}
+	if len(plan.Identifiers) != 0 {
+		t.Errorf("Identifiers = %v, expected none", plan.Identifiers)
+	}
+
+	// a literal is bound without tasks
+	plan, err = PlanScript("x = 5; x", SyntaxInfix, Scope{})
+	if err != nil || len(plan.Tasks) != 0 || plan.Variable != "5" || plan.Bindings[0].Ref != "5" {
+		t.Errorf("PlanScript(x = 5; x) = %+v, %v", plan, err)
+	}
+
+	// a long literal is the answer as it is, examples.response keeps all its digits
+	long := strings.Repeat("9", 100)
+	plan, err = PlanScript(long, SyntaxInfix, Scope{})
+	if err != nil || len(plan.Tasks) != 0 || plan.Variable != long {
+		t.Fatalf("PlanScript(100 digits) = %+v, %v", plan, err)
+	}
+	if value, ok := ParseLiteral(plan.Variable); !ok || value.String() != long {
+		t.Errorf("ParseLiteral(100 digits) = %v, %v, expected the same digits", value, ok)
+	}
+
+	plan, err = PlanScript("y = x + 1; y * z", SyntaxInfix, Scope{})
+	if err != nil || !reflect.DeepEqual(plan.Identifiers, []string{"x", "z"}) {
+		t.Errorf("PlanScript() identifiers = %v, %v, expected x and z", plan.Identifiers, err)
+	}
+
+	// saved values of names, an assignment hides them
+	plan, err = PlanScript("price * (1 + tax_rate); tax_rate = 0; tax_rate + 1", SyntaxInfix, Scope{Values: map[string]string{"price": "100", "tax_rate": "0.2", "other": "1"}})
+	if err != nil || len(plan.Identifiers) != 0 || !reflect.DeepEqual(plan.Inputs, map[string]string{"price": "100", "tax_rate": "0.2"}) {
+		t.Errorf("PlanScript() with values = %+v, %v", plan, err)
+	}
+	if first := plan.Tasks[0]; first.Num1 != "1" || first.Num2 != "0.2" {
+		t.Errorf("PlanScript() with values: first task = %+v, expected 1 + 0.2", first)
+	}
+
+	for _, input := range []string{"mean = 2", "days = 1", "USD = 1", "pi = 3", "in = 1", "x = 2; 2 + * 3", "; ;"} {
+		if _, err := PlanScript(input, SyntaxInfix, Scope{}); !errors.Is(err, ErrCovertExample) {
+			t.Errorf("PlanScript(%q) error = %v, expected ErrCovertExample", input, err)
+		}
+	}
+	if _, err := PlanScript("x = 2; 2 + * 3", SyntaxInfix, Scope{}); err == nil || !strings.HasPrefix(err.Error(), "statement 2:") {
+		t.Errorf("PlanScript() error = %v, expected the number of the statement", err)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	expr := NewExpression(`ans * 2 + @last + result("3f2a-b1")`)
+	if _, err := expr.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if got, expected := expr.Postfix, "ans 2 * @last + @3f2a-b1 +"; got != expected {
+		t.Errorf("Convert() = %q, expected %q", got, expected)
+	}
+	if got := expr.Identifiers(); !reflect.DeepEqual(got, []string{"ans", "@last", "@3f2a-b1"}) {
+		t.Errorf("Identifiers() = %v", got)
+	}
+	for name, id := range map[string]string{"ans": "", "@last": "", "@3f2a-b1": "3f2a-b1"} {
+		if !IsReference(name) || ReferenceID(name) != id {
+			t.Errorf("ReferenceID(%s) = %q, expected %q", name, ReferenceID(name), id)
+		}
+	}
+	if IsReference("x") || IsReference("@") {
+		t.Errorf("IsReference() is true for a plain name")
+	}
+
+	// a pending example is bound to its variable, a calculated one to its value
+	plan, err := PlanScript("ans * 2; x = @last", SyntaxInfix, Scope{Values: map[string]string{"ans": "21", "@last": "7c1e"}})
+	if err != nil || len(plan.Tasks) != 1 || plan.Tasks[0].Num1 != "21" || plan.Bindings[0].Ref != "7c1e" {
+		t.Errorf("PlanScript() = %+v, %v", plan, err)
+	}
+
+	for _, input := range []string{`result(5)`, `result("a", "b")`, `result(x)`, `result("")`, "ans = 2", "result = 2"} {
+		if _, err := PlanScript(input, SyntaxInfix, Scope{}); !errors.Is(err, ErrCovertExample) {
+			t.Errorf("PlanScript(%q) error = %v, expected ErrCovertExample", input, err)
+		}
+	}
+	if err := CheckName("@last"); err == nil {
+		t.Errorf("CheckName(@last) = nil, expected an error")
+	}
+}
+
+func TestFunctionDefinitions(t *testing.T) {
+	defs := make(map[string]FunctionDef)
+	for _, text := range []string{"f(x, y) = x^2 + 3*y", "hyp(a, b) = (f(a, 0) + b^2) ^ 0.5", "answer() = 42"} {
+		def, err := ParseDefinition(text)
+		if err != nil {
+			t.Fatalf("ParseDefinition(%q) error = %v", text, err)
+		}
+		if err := def.Validate(defs); err != nil {
+			t.Fatalf("Validate(%q) error = %v", text, err)
+		}
+		defs[def.Name] = def
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(2, 5)", "19"},
+		{"f(1 + 1, f(1, 0)) * 2", "14"},
+		{"hyp(3, 4)", "5"},
+		{"answer() + 1", "43"},
+		{"r = 3; f(r, r)", "18"},
+	}
+	for _, tt := range tests {
+		plan, err := PlanScript(tt.input, SyntaxInfix, Scope{Functions: defs})
+		if err != nil || len(plan.Functions) != 0 {
+			t.Errorf("PlanScript(%q) = %+v, %v", tt.input, plan, err)
+			continue
+		}
+		got, err := runTasks(t, plan.Tasks, plan.Variable)
+		if err != nil || got.String() != tt.expected {
+			t.Errorf("%s = %v, %v, expected %s", tt.input, got, err, tt.expected)
+		}
+	}
+
+	plan, err := PlanScript("hyp(3, 4)", SyntaxInfix, Scope{Functions: defs})
+	if err != nil || !reflect.DeepEqual(plan.Expanded, []string{"f", "hyp"}) {
+		t.Errorf("Expanded = %v, %v, expected f and hyp", plan.Expanded, err)
+	}
+	for input, syntax := range map[string]Syntax{"2 5 f(2)": SyntaxRPN, "(f 2 5)": SyntaxSexpr} {
+		plan, err := PlanScript(input, syntax, Scope{Functions: defs})
+		if err != nil {
+			t.Errorf("PlanScript(%q) error = %v", input, err)
+			continue
+		}
+		if got, err := runTasks(t, plan.Tasks, plan.Variable); err != nil || got.String() != "19" {
+			t.Errorf("%s = %v, %v, expected 19", input, got, err)
+		}
+	}
+	if _, err := PlanScript("f(1)", SyntaxInfix, Scope{Functions: defs}); !errors.Is(err, ErrCovertExample) {
+		t.Errorf("f(1) error = %v, expected ErrCovertExample", err)
+	}
+
+	// recursion, directly or through another function
+	g, _ := ParseDefinition("g(x) = f(x, g(x))")
+	if err := g.Validate(defs); err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Errorf("Validate(g) error = %v, expected recursion", err)
+	}
+	f, _ := ParseDefinition("f(x, y) = hyp(x, y)")
+	if err := f.Validate(defs); err == nil || !strings.Contains(err.Error(), "recursive") {
+		t.Errorf("Validate(f) error = %v, expected recursion", err)
+	}
+
+	// a chain deeper than the limit
+	chain := map[string]FunctionDef{"l0": {Name: "l0", Params: []string{"x"}, Body: "x + 1"}}
+	for i := 1; i <= MaxFunctionDepth; i++ {
+		name := fmt.Sprintf("l%d", i)
+		chain[name] = FunctionDef{Name: name, Params: []string{"x"}, Body: fmt.Sprintf("l%d(x)", i-1)}
+	}
+	if _, err := PlanScript(fmt.Sprintf("l%d(1)", MaxFunctionDepth-1), SyntaxInfix, Scope{Functions: chain}); err != nil {
+		t.Errorf("a chain of %d calls error = %v", MaxFunctionDepth, err)
+	}
+	if _, err := PlanScript(fmt.Sprintf("l%d(1)", MaxFunctionDepth), SyntaxInfix, Scope{Functions: chain}); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("a chain of %d calls error = %v, expected ErrLimitExceeded", MaxFunctionDepth+1, err)
+	}
+
+	for text, expected := range map[string]error{
+		"f x = x":              ErrCovertExample,
+		"mean(x) = x":          ErrCovertExample,
+		"h(x, x) = x":          ErrCovertExample,
+		"h(x) = x + y":         ErrUnknownVariable,
+		"h(x) = unknown(x)":    ErrUnknownFunction,
+		"h(x) = ans + x":       ErrUnknownVariable,
+		"h(x) = x +":           ErrCovertExample,
+		"h(x) = f(x, 1, 2)":    ErrCovertExample,
+		"h(x, y) = pi * x * y": nil,
+	} {
+		def, err := ParseDefinition(text)
+		if err == nil {
+			err = def.Validate(defs)
+		}
+		if !errors.Is(err, expected) || (expected == nil && err != nil) {
+			t.Errorf("%q error = %v, expected %v", text, err, expected)
+		}
+	}
+}
+
+func TestCall_Custom(t *testing.T) {
+	lerp := func(args []float64) (float64, error) {
+		return args[0] + (args[1]-args[0])*args[2], nil
+	}
+	call := NewCall("lerp", []Value{NumberValue(10), NumberValue(20), NumberValue(0.25)})
+	call.Custom = lerp
+	if got, err := call.Evaluate(); err != nil || got.String() != "12.5" {
+		t.Errorf("lerp(10, 20, 0.25) = %v, %v, expected 12.5", got, err)
+	}
+
+	call = NewCall("lerp", []Value{ListValue([]Value{NumberValue(1)}), NumberValue(2), NumberValue(3)})
+	call.Custom = lerp
+	if _, err := call.Evaluate(); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("lerp of a list error = %v, expected ErrInvalidArgument", err)
+	}
+
+	call = NewCall("crash", nil)
+	call.Custom = func([]float64) (float64, error) { return 0, fmt.Errorf("%w: trap", ErrOperationFailed) }
+	if _, err := call.Evaluate(); !IsBusinessError(err) {
+		t.Errorf("a failed operation is not a business error: %v", err)
+	}
+	call.Custom = func([]float64) (float64, error) { return math.Inf(1), nil }
+	if _, err := call.Evaluate(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("an infinite result error = %v, expected ErrOverflow", err)
+	}
+	if IsOperator("lerp") || !IsOperator("in") {
+		t.Errorf("IsOperator() is wrong")
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"literal", "100 USD", "100.00 USD", false},
+		{"call", `money(2.5, "EUR")`, "2.50 EUR", false},
+		{"sum", "100 USD + 20 USD", "120.00 USD", false},
+		{"scaling", "3 * 10 EUR / 4", "7.50 EUR", false},
+		{"ratio", "(30 EUR) / (10 EUR)", "3", false},
+		{"negative", "~(5 USD)", "-5.00 USD", false},
+		{"conversion", "100 USD in EUR", "80.00 EUR", false},
+		{"cross conversion", "40 EUR in JPY", "7500.00 JPY", false},
+		{"sum after conversion", "100 USD + (80 EUR in USD)", "200.00 USD", false},
+		{"conversion of a sum", "100 USD + 25 USD in EUR", "100.00 EUR", false},
+		{"different currencies", "100 USD + 20 EUR", "", true},
+		{"money plus number", "100 USD + 1", "", true},
+		{"money times money", "(2 USD) * (3 USD)", "", true},
+		{"unknown currency", "100 USD in XYZ", "", true},
+		{"conversion to unit", "100 USD in days", "", true},
+		{"bad code", `money(1, "usd")`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluate(t, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !IsBusinessError(err) {
+					t.Errorf("error %v is not a business error", err)
+				}
+				return
+			}
+			if result.String() != tt.expected {
+				t.Errorf("evaluate() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("currencies", func(t *testing.T) {
+		expr := NewExpression(`100 USD + money(1, "JPY") in EUR`)
+		if _, err := expr.Convert(); err != nil {
+			t.Fatal(err)
+		}
+		if got := expr.Currencies(); !reflect.DeepEqual(got, []string{"EUR", "JPY", "USD"}) {
+			t.Errorf("Currencies() = %v", got)
+		}
+	})
+}
+
+func mustParseLiteral(t *testing.T, ref string) Value {
+	t.Helper()
+	value, ok := ParseLiteral(ref)
+	if !ok {
+		t.Fatalf("ParseLiteral(%q) failed", ref)
+	}
+	return value
+}
+
+func TestValue_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Value
+		json  string
+	}{
+		{"number", NumberValue(2.5), `2.5`},
+		{"list", NumbersValue([]float64{1, 2}), `{"kind":"list","list":[1,2]}`},
+		{"empty list", ListValue([]Value{}), `{"kind":"list"}`},
+		{"matrix", MatrixValue([][]float64{{1, 2}, {3, 4}}), `{"kind":"matrix","matrix":[[1,2],[3,4]]}`},
+		{"string", mustParseLiteral(t, `"x"`), `{"kind":"string","text":"x"}`},
+		{"duration", DurationValue(90 * time.Minute), `{"kind":"duration","duration":5400000000000}`},
+		{"date", TimeValue(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)), `{"kind":"time","time":"2026-10-16T00:00:00Z"}`},
+		{"big int", mustParseLiteral(t, "123456789012345678901234567890"), `{"kind":"int","int":123456789012345678901234567890}`},
+		{"infinity", NumberValue(math.Inf(1)), `"+Inf"`},
+		{"nan", NumberValue(math.NaN()), `"NaN"`},
+		{"money", MoneyValue(12.5, "EUR"), `{"kind":"money","amount":12.5,"currency":"EUR"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("Marshal() = %s, expected %s", data, tt.json)
+			}
+
+			var decoded Value
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if decoded.String() != tt.value.String() || decoded.Kind != tt.value.Kind {
+				t.Errorf("Unmarshal() = %v, expected %v", decoded, tt.value)
+			}
+		})
+	}
+}
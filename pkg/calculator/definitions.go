@@ -0,0 +1,201 @@
+package calculator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxFunctionDepth - user functions calling each other nested deeper are rejected
+	MaxFunctionDepth = 16
+	// maxExpandedItems - items of an expression after calls are inlined,
+	// a function using its argument twice doubles it on every level
+	maxExpandedItems = 100000
+)
+
+// FunctionDef - a function defined by a user: f(x, y) = x^2 + 3*y
+type FunctionDef struct {
+	Name   string
+	Params []string
+	Body   string // an infix expression of the parameters
+}
+
+func (d FunctionDef) String() string {
+	return d.Name + "(" + strings.Join(d.Params, ", ") + ") = " + d.Body
+}
+
+// ParseDefinition - "f(x, y) = x^2 + 3*y". The name and the parameters
+// follow the rules of CheckName, the body is checked by Validate.
+func ParseDefinition(text string) (FunctionDef, error) {
+	head, body, ok := strings.Cut(text, "=")
+	body = strings.TrimSpace(body)
+	if !ok || body == "" {
+		return FunctionDef{}, fmt.Errorf("%w: a definition is f(x, y) = expression", ErrCovertExample)
+	}
+
+	head = strings.TrimSpace(head)
+	open := strings.IndexByte(head, '(')
+	if open <= 0 || !strings.HasSuffix(head, ")") {
+		return FunctionDef{}, fmt.Errorf("%w: a definition is f(x, y) = expression", ErrCovertExample)
+	}
+	def := FunctionDef{Name: strings.TrimRightFunc(head[:open], unicode.IsSpace), Params: []string{}, Body: body}
+	if err := CheckName(def.Name); err != nil {
+		return FunctionDef{}, fmt.Errorf("%w: %w", ErrCovertExample, err)
+	}
+
+	if params := strings.TrimSpace(head[open+1 : len(head)-1]); params != "" {
+		for _, param := range strings.Split(params, ",") {
+			param = strings.TrimSpace(param)
+			if err := CheckName(param); err != nil {
+				return FunctionDef{}, fmt.Errorf("%w: parameter %w", ErrCovertExample, err)
+			}
+			if slices.Contains(def.Params, param) {
+				return FunctionDef{}, fmt.Errorf("%w: parameter %q is repeated", ErrCovertExample, param)
+			}
+			def.Params = append(def.Params, param)
+		}
+	}
+	return def, nil
+}
+
+// Validate - the body is an expression of the parameters that calls builtin
+// functions and the functions of defs, without recursion. defs are the other
+// functions of the user, a function with the same name is replaced by d.
+func (d FunctionDef) Validate(defs map[string]FunctionDef) error {
+	items, err := d.postfix()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if isIdentifier(item) && !slices.Contains(d.Params, item) {
+			return fmt.Errorf("%w: %s is not a parameter of %s", ErrUnknownVariable, item, d.Name)
+		}
+	}
+
+	all := make(map[string]FunctionDef, len(defs)+1)
+	for name, def := range defs {
+		all[name] = def
+	}
+	all[d.Name] = d
+	expr := &Expression{Postfix: strings.Join(items, " ")}
+	expanded, _, err := expr.Expand(all)
+	if err != nil {
+		return err
+	}
+	if names := expanded.UnknownFunctions(); len(names) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownFunction, names[0])
+	}
+	return nil
+}
+
+// postfix - items of the body, references are not allowed in it
+func (d FunctionDef) postfix() ([]string, error) {
+	expr := &Expression{Infix: d.Body}
+	if _, err := expr.Convert(); err != nil {
+		return nil, err
+	}
+	return strings.Fields(expr.Postfix), nil
+}
+
+// Expand returns a copy of the expression where calls of the functions
+// of defs are replaced by their bodies, the arguments are put in place of
+// the parameters. The names of the expanded functions are returned sorted.
+// Convert must be called first.
+func (s *Expression) Expand(defs map[string]FunctionDef) (*Expression, []string, error) {
+	if len(defs) == 0 {
+		return s, nil, nil
+	}
+	used := make(map[string]bool)
+	items, err := expand(strings.Fields(s.Postfix), defs, nil, used)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &Expression{Infix: s.Infix, Postfix: strings.Join(items, " "), Syntax: s.Syntax}, names, nil
+}
+
+// expand - postfix items are split into the operands of each operator and
+// call, so a call of a user function knows its arguments. calls are the
+// functions being expanded, from the outermost.
+func expand(items []string, defs map[string]FunctionDef, calls []string, used map[string]bool) ([]string, error) {
+	operands := make([][]string, 0, len(items))
+	size := 0
+	for _, item := range items {
+		n := operandsOf(item)
+		if len(operands) < n {
+			return nil, ErrCovertExample
+		}
+		args := operands[len(operands)-n:]
+		operands = operands[:len(operands)-n]
+
+		name, _, isCall := parseFunctionToken(item)
+		def, ok := defs[name]
+		if !isCall || !ok || IsFunction(name) {
+			operand := make([]string, 0, 1)
+			for _, arg := range args {
+				operand = append(operand, arg...)
+			}
+			operands = append(operands, append(operand, item))
+			continue
+		}
+
+		switch {
+		case len(def.Params) != n:
+			return nil, fmt.Errorf("%w: %s takes %d arguments, got %d", ErrCovertExample, name, len(def.Params), n)
+		case slices.Contains(calls, name):
+			return nil, fmt.Errorf("%w: %s is recursive: %s → %s", ErrCovertExample, name, strings.Join(calls, " → "), name)
+		case len(calls) >= MaxFunctionDepth:
+			return nil, fmt.Errorf("%w: functions are nested deeper than %d", ErrLimitExceeded, MaxFunctionDepth)
+		}
+
+		body, err := def.postfix()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		bound := make([]string, 0, len(body))
+		for _, b := range body {
+			if i := slices.Index(def.Params, b); i >= 0 {
+				bound = append(bound, args[i]...)
+			} else {
+				bound = append(bound, b)
+			}
+		}
+		operand, err := expand(bound, defs, append(slices.Clip(calls), name), used)
+		if err != nil {
+			return nil, err
+		}
+		size += len(operand)
+		if size > maxExpandedItems {
+			return nil, fmt.Errorf("%w: functions expand to more than %d items", ErrLimitExceeded, maxExpandedItems)
+		}
+		used[name] = true
+		operands = append(operands, operand)
+	}
+
+	if len(operands) != 1 {
+		return nil, ErrCovertExample
+	}
+	return operands[0], nil
+}
+
+// operandsOf - the number of operands a postfix item takes, 0 for operands
+func operandsOf(item string) int {
+	switch item {
+	case "~":
+		return 1
+	case "+", "-", "*", "/", "^", "in":
+		return 2
+	}
+	if _, arity, ok := parseFunctionToken(item); ok {
+		return arity
+	}
+	return 0
+}
@@ -0,0 +1,196 @@
+package calculator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+)
+
+// atomPriority - numbers, names and calls never need brackets
+const atomPriority = 5
+
+// source - text of a sub-expression and the priority of its outer operator.
+// Steps inside a reduction tree have no text, only the items they aggregate.
+type source struct {
+	text     string
+	priority int
+	items    []string
+}
+
+// Sources - text of the sub-expression computed by each task, in the order
+// of the tasks: 2 + 2 * 2 gives "2 * 2", "2 + 2 * 2". The text is rebuilt
+// from the tasks, so spaces and redundant brackets of the input are lost
+// and ~x is shown as 0 - x. Steps the planner split an aggregate or a
+// matrix product into have an empty text, the aggregate itself is shown
+// as written: mean([1, 2, ..., 100]).
+func Sources(tasks []*models.Task) []string {
+	known := make(map[string]source, len(tasks))
+	byVariable := make(map[string]*models.Task, len(tasks))
+	result := make([]string, len(tasks))
+
+	operand := func(ref string) source {
+		if s, ok := known[ref]; ok {
+			return s
+		}
+		if strings.HasPrefix(ref, "-") {
+			return source{text: ref, priority: OperatorPriority["-"]} // substituted negative value
+		}
+		if text, err := strconv.Unquote(ref); err == nil {
+			return source{text: strconv.Quote(text), priority: atomPriority}
+		}
+		return source{text: ref, priority: atomPriority}
+	}
+
+	for i, task := range tasks {
+		var s source
+		switch {
+		case isReductionStep(task.Sign):
+			s = source{items: itemsOf(task.Args, operand)}
+		case task.Sign == "_vstack":
+			s = stackSource(task.Args, byVariable, operand)
+			for j, block := range tasks[:i] {
+				if blockOf(block, task.Args, byVariable) {
+					result[j] = "" // blocks of rows are shown by the whole product
+				}
+			}
+		case IsFunction(task.Sign):
+			s = callSource(strings.TrimPrefix(task.Sign, "_"), task.Args, operand) // _mean is mean
+		default:
+			s = operatorSource(task.Sign, operand(task.Num1), operand(task.Num2), task.Num2)
+		}
+		known[task.Variable] = s
+		byVariable[task.Variable] = task
+		result[i] = s.text
+	}
+	return result
+}
+
+// isReductionStep - partial and combining steps of an aggregate that only
+// the planner produces: _moments, _merge_sorted
+func isReductionStep(sign string) bool {
+	if !IsInternal(sign) {
+		return false
+	}
+	for _, fn := range functions {
+		if fn.reduce != nil && (fn.reduce.partial == sign || fn.reduce.combine == sign) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemsOf - texts of the items, the items of reduction steps in their place
+func itemsOf(refs []string, operand func(string) source) []string {
+	items := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if s := operand(ref); s.items != nil {
+			items = append(items, s.items...)
+		} else {
+			items = append(items, s.text)
+		}
+	}
+	return items
+}
+
+// blockOf - a block of rows or its product with the right matrix,
+// products are the refs of _vstack
+func blockOf(task *models.Task, products []string, byVariable map[string]*models.Task) bool {
+	for _, ref := range products {
+		product, ok := byVariable[ref]
+		if ok && (task == product || task.Variable == product.Num1) {
+			return true
+		}
+	}
+	return false
+}
+
+// stackSource - the matrix product split by blockMultiply, as it was written
+func stackSource(refs []string, byVariable map[string]*models.Task, operand func(string) source) source {
+	rows := make([]string, 0, len(refs))
+	right := ""
+	for _, ref := range refs {
+		product, ok := byVariable[ref]
+		if !ok {
+			continue
+		}
+		if block, ok := byVariable[product.Num1]; ok {
+			rows = append(rows, itemsOf(block.Args, operand)...)
+		}
+		right = product.Num2
+	}
+	matrix := source{text: "[" + strings.Join(rows, ", ") + "]", priority: atomPriority}
+	return operatorSource("*", matrix, operand(right), right)
+}
+
+// operatorSource - a op b with brackets where priorities require them
+func operatorSource(sign string, a, b source, rawB string) source {
+	priority := OperatorPriority[sign]
+	if sign == "in" {
+		// unit or currency: d in hours, m in EUR
+		if text, err := strconv.Unquote(rawB); err == nil {
+			b = source{text: text, priority: atomPriority}
+		}
+	}
+	if a.priority < priority || (a.priority == priority && RightAssociative[sign]) {
+		a.text = "(" + a.text + ")"
+	}
+	if b.priority < priority || (b.priority == priority && !RightAssociative[sign]) {
+		b.text = "(" + b.text + ")"
+	}
+	return source{text: a.text + " " + sign + " " + b.text, priority: priority}
+}
+
+// callSource - f(a, b), [a, b] for lists, 90 days for units, 100 USD for money
+func callSource(name string, refs []string, operand func(string) source) source {
+	args := make([]string, len(refs))
+	for i, ref := range refs {
+		if s := operand(ref); s.items != nil {
+			args[i] = "[" + strings.Join(s.items, ", ") + "]" // end of a reduction tree
+		} else {
+			args[i] = s.text
+		}
+	}
+
+	// postfix forms only take a plain number: 90 days, not (a + b) days
+	number := false
+	if len(refs) > 0 {
+		value, ok := ParseLiteral(refs[0])
+		number = ok && value.IsNumber() && !strings.HasPrefix(refs[0], "-")
+	}
+
+	switch {
+	case name == "list":
+		return source{text: "[" + strings.Join(args, ", ") + "]", priority: atomPriority}
+	case IsUnit(name) && len(refs) == 1 && number:
+		return source{text: args[0] + " " + name, priority: atomPriority}
+	case name == "money" && len(refs) == 2 && number:
+		if code, err := strconv.Unquote(refs[1]); err == nil && isCurrency(code) {
+			return source{text: args[0] + " " + code, priority: atomPriority}
+		}
+	}
+	return source{text: name + "(" + strings.Join(args, ", ") + ")", priority: atomPriority}
+}
+
+// FormatStep - one step with the values of its operands: 2 * 2, mean([1, 2])
+func FormatStep(sign string, operands []string) string {
+	switch {
+	case sign == "list":
+		return "[" + strings.Join(operands, ", ") + "]"
+	case IsFunction(sign):
+		return sign + "(" + strings.Join(operands, ", ") + ")"
+	case sign == "in" && len(operands) == 2:
+		if text, err := strconv.Unquote(operands[1]); err == nil {
+			return operands[0] + " in " + text
+		}
+	}
+	return strings.Join(operands, " "+sign+" ")
+}
+
+// Literal - the value as it is written in an expression: strings are quoted
+func (v Value) Literal() string {
+	if v.Kind == KindString {
+		return strconv.Quote(v.Text)
+	}
+	return v.String()
+}
@@ -2,6 +2,7 @@ package calculator
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
@@ -9,8 +10,48 @@ var (
 	ErrDivisionByZero       = errors.New("division by zero")
 	ErrNonExistingOperation = errors.New("operation does not exist or not implemented")
 	ErrCovertExample        = errors.New("line is not a mathematical expression or contains an error")
+	ErrUnknownVariable      = errors.New("unknown variable")
+	ErrUnknownReference     = errors.New("unknown reference") // ans, @last or result("id") without a result
+	ErrUnknownFunction      = errors.New("unknown function")
+	ErrInvalidArgument      = errors.New("invalid argument")
+	ErrDimensionMismatch    = errors.New("dimension mismatch")
+	ErrSingularMatrix       = errors.New("matrix is singular")
+	ErrUnknownCurrency      = errors.New("unknown currency")
+	ErrCurrencyMismatch     = errors.New("currency mismatch")
+	ErrOverflow             = errors.New("overflow: result is too large")
+	ErrDomain               = errors.New("domain error: result is not a number")
+	ErrOperationFailed      = errors.New("custom operation failed") // trapped, or ran out of time or memory
 )
 
+// businessErrors - errors caused by the expression itself,
+// retrying the task won't help
+var businessErrors = []error{
+	ErrDivisionByZero,
+	ErrNonExistingOperation,
+	ErrCovertExample,
+	ErrUnknownVariable,
+	ErrUnknownFunction,
+	ErrInvalidArgument,
+	ErrDimensionMismatch,
+	ErrSingularMatrix,
+	ErrUnknownCurrency,
+	ErrCurrencyMismatch,
+	ErrOverflow,
+	ErrDomain,
+	ErrOperationFailed,
+}
+
+// IsBusinessError checks if the error should be saved to the example
+// instead of retrying the task
+func IsBusinessError(err error) bool {
+	for _, target := range businessErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 type Node struct {
 	Num1 float64
 	Num2 float64
@@ -24,6 +65,16 @@ type Node struct {
 // 	Variable string `json:"variable"`
 // }
 
+// IsOperator checks if the sign of a task is an operator: it takes Num1
+// and Num2, functions take Args
+func IsOperator(sign string) bool {
+	switch sign {
+	case "+", "-", "*", "/", "^", "in":
+		return true
+	}
+	return false
+}
+
 func NewNode(num1, num2 float64, sign string) *Node {
 	return &Node{Num1: num1, Num2: num2, Sign: sign}
 }
@@ -47,3 +98,80 @@ func (n *Node) Calculate() (float64, error) {
 		return 0, ErrNonExistingOperation
 	}
 }
+
+// Call - one step with already resolved operands:
+// a binary operator or a function with any number of arguments.
+// Operators on numbers are computed by Node, on big integers by exactOperator,
+// on lists and matrices by applyOperator.
+type Call struct {
+	Sign  string
+	Args  []Value
+	Seed  uint64             // for random functions, see TaskSeed
+	Rates map[string]float64 // for "in" on money, rate snapshot of the example
+	IEEE  bool               // numbers may be ±Inf and NaN instead of ErrOverflow and ErrDomain
+
+	// Custom - an operation the calculator does not know: a function
+	// of an uploaded WebAssembly module, numbers in and a number out
+	Custom func(args []float64) (float64, error)
+}
+
+func NewCall(sign string, args []Value) *Call {
+	return &Call{Sign: sign, Args: args}
+}
+
+// Evaluate computes the step. Infinite results are ErrOverflow,
+// NaN is ErrDomain unless IEEE is set.
+func (c *Call) Evaluate() (Value, error) {
+	result, err := c.evaluate()
+	if err != nil {
+		return Value{}, err
+	}
+	if err := finite(result, c.IEEE); err != nil {
+		return Value{}, fmt.Errorf("%w in %s", err, c.Sign)
+	}
+	return result, nil
+}
+
+func (c *Call) evaluate() (Value, error) {
+	if IsFunction(c.Sign) {
+		return applyFunction(c.Sign, c.Args, c.Seed)
+	}
+	if c.Custom != nil {
+		return c.custom()
+	}
+
+	if len(c.Args) != 2 {
+		return Value{}, fmt.Errorf("%w: operator %s expects 2 operands, got %d", ErrInvalidArgument, c.Sign, len(c.Args))
+	}
+	if c.Sign == "in" && c.Args[0].Kind == KindMoney {
+		return convertMoney(c.Args[0], c.Args[1], c.Rates)
+	}
+	if result, ok, err := exactOperator(c.Sign, c.Args[0], c.Args[1]); ok {
+		return result, err
+	}
+	if !c.Args[0].IsNumber() || !c.Args[1].IsNumber() {
+		return applyOperator(c.Sign, c.Args[0], c.Args[1])
+	}
+	result, err := NewNode(c.Args[0].Number, c.Args[1].Number, c.Sign).Calculate()
+	if err != nil {
+		return Value{}, err
+	}
+	return NumberValue(result), nil
+}
+
+// custom - a custom operation on numbers
+func (c *Call) custom() (Value, error) {
+	numbers := make([]float64, len(c.Args))
+	for i, arg := range c.Args {
+		number, ok := arg.Exact()
+		if !ok {
+			return Value{}, fmt.Errorf("%w: %s takes numbers, argument %d is %s", ErrInvalidArgument, c.Sign, i+1, arg.Kind)
+		}
+		numbers[i] = number
+	}
+	result, err := c.Custom(numbers)
+	if err != nil {
+		return Value{}, err
+	}
+	return NumberValue(result), nil
+}
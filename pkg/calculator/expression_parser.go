@@ -2,8 +2,9 @@ package calculator
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
-	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/tainj/distributed_calculator2/internal/models"
@@ -11,19 +12,26 @@ import (
 
 var (
 	OperatorPriority = map[string]int{
-		"+": 1,
-		"-": 1,
-		"*": 2,
-		"/": 2,
-		"^": 3,
-		"~": 4,
-		"(": 6,
+		"+":  1,
+		"-":  1,
+		"*":  2,
+		"/":  2,
+		"^":  3,
+		"~":  4,
+		"(":  6,
+		"in": 0, // conversion of durations: (a - b) in hours
 	}
 
 	// Right-associative operators
 	RightAssociative = map[string]bool{
 		"^": true, // 2^3^4 = 2^(3^4)
 	}
+
+	// Constants - names replaced by their values when an expression is converted
+	Constants = map[string]float64{
+		"pi": math.Pi,
+		"e":  math.E,
+	}
 )
 
 func NewExample(num1, num2, sign string) (models.Task, string) {
@@ -31,6 +39,12 @@ func NewExample(num1, num2, sign string) (models.Task, string) {
 	return models.Task{Num1: num1, Num2: num2, Sign: sign, Variable: variable}, variable
 }
 
+// NewCallExample - task for a function with any number of arguments
+func NewCallExample(sign string, args []string) (models.Task, string) {
+	variable := uuid.New().String()
+	return models.Task{Sign: sign, Args: args, Variable: variable}, variable
+}
+
 // Stack implementation and its methods
 type Stack struct {
 	list []string
@@ -63,6 +77,7 @@ func (s *Stack) Peek() string {
 type Expression struct {
 	Infix   string // Infix expression
 	Postfix string // Postfix expression
+	Syntax  Syntax // how Infix is written, infix if empty
 }
 
 func NewExpression(str string) *Expression {
@@ -75,216 +90,470 @@ func (s *Expression) Check() bool {
 }
 
 // IsValidMathExpression checks if a string is a valid mathematical expression.
-// Supports: digits, +, -, *, /, ^, ~ (unary minus), ., ()
+// Supports: numbers, variables, +, -, *, /, ^, ~ (unary minus), (),
+// function calls mean(1, 2), lists [1, 2, 3], strings "2026-10-16",
+// units 90 days, money 100 USD and conversions d in hours, m in EUR
 func (s *Expression) IsValidMathExpression() bool {
-	expr := strings.ReplaceAll(s.Infix, " ", "")
-	if expr == "" {
-		return false
+	_, err := s.postfix()
+	return err == nil
+}
+
+func (s *Expression) Convert() (bool, error) {
+	list, err := s.postfix()
+	if err != nil {
+		return false, err
+	}
+	if list, err = resultCalls(list); err != nil {
+		return false, err
 	}
+	for i, item := range list {
+		if value, ok := Constants[item]; ok {
+			list[i] = strconv.FormatFloat(value, 'g', -1, 64)
+		}
+	}
+	s.Postfix = strings.Join(list, " ")
+	return true, nil
+}
+
+// postfix - items of the postfix form of Infix in its syntax
+func (s *Expression) postfix() ([]string, error) {
+	switch s.Syntax {
+	case SyntaxRPN:
+		return rpnToPostfix(s.Infix)
+	case SyntaxSexpr:
+		return sexprToPostfix(s.Infix)
+	case SyntaxInfix, "":
+		return toPostfix(s.Infix)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSyntax, s.Syntax)
+}
 
-	// Allowed characters
-	allowed := "+-*/().~^"
-	for i, ch := range expr {
-		if !unicode.IsDigit(ch) && !strings.ContainsRune(allowed, ch) {
-			return false
+// isGroup - opening bracket with arguments on the operator stack:
+// a list "[" or a function call "mean("
+func isGroup(item string) bool {
+	return item == "[" || (item != "(" && strings.HasSuffix(item, "("))
+}
+
+// toPostfix validates the expression and converts it to reverse Polish notation
+// (shunting-yard). Validation is done on the fly: after an operand we expect
+// a binary operator, "," or a closing bracket, after an operator - an operand,
+// "~" or an opening bracket. Calls and lists become "name/arity" items.
+func toPostfix(infix string) ([]string, error) {
+	tokens, err := tokenize(infix)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrCovertExample
+	}
+
+	list := make([]string, 0, len(tokens))
+	stack := NewStack()
+	arity := make([]int, 0) // arguments of the open calls and lists
+	expectOperand := true
+	opened := false // previous token opened a call or a list
+
+	// closeGroup - unload operators down to the nearest opening bracket
+	// and return the number of arguments if it is a call or a list
+	closeGroup := func(tok token, empty bool) (string, int, error) {
+		for !stack.IsEmptyStack() && !isGroup(stack.Peek()) && stack.Peek() != "(" {
+			list = append(list, stack.Pop())
+		}
+		if stack.IsEmptyStack() {
+			return "", 0, fmt.Errorf("%w: extra closing bracket at position %d", ErrCovertExample, tok.pos)
+		}
+		top := stack.Pop()
+		if top == "(" {
+			return top, 0, nil
 		}
+		count := arity[len(arity)-1]
+		arity = arity[:len(arity)-1]
+		if empty {
+			count = 0 // rand(), []
+		}
+		return top, count, nil
+	}
 
-		// Forbid decimal point at the end
-		if ch == '.' {
-			if i == len(expr)-1 {
-				return false
+	// pushOperator - pop operators with higher or equal priority
+	// BUT: if operator is right-associative (e.g., ^), don't pop at equal priority
+	pushOperator := func(operator string) {
+		value := OperatorPriority[operator]
+		for !stack.IsEmptyStack() {
+			top := stack.Peek()
+			if top == "(" || isGroup(top) {
+				break
 			}
-			next := rune(expr[i+1])
-			if !unicode.IsDigit(next) {
-				return false
+			topPriority := OperatorPriority[top]
+			if topPriority > value || (topPriority == value && !RightAssociative[operator]) {
+				list = append(list, stack.Pop())
+			} else {
+				break
 			}
 		}
+		stack.Push(operator)
 	}
 
-	// Check brackets
-	balance := 0
-	for _, ch := range expr {
-		if ch == '(' {
-			balance++
-		} else if ch == ')' {
-			balance--
-			if balance < 0 {
-				return false // Extra closing bracket
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		wasOpened := opened
+		opened = false
+
+		switch {
+		case expectOperand && tok.kind == tokenIdent && (IsUnit(tok.text) || isCurrency(tok.text)) && !stack.IsEmptyStack() && stack.Peek() == "in":
+			// unit or currency of a conversion is a string operand: in "hours", in "EUR"
+			list = append(list, quoteLiteral(tok.text))
+			expectOperand = false
+		case !expectOperand && tok.kind == tokenIdent && tok.text == "in":
+			pushOperator(tok.text)
+			expectOperand = true
+		case !expectOperand && tok.kind == tokenIdent && IsUnit(tok.text):
+			// postfix unit binds to the operand before it: 90 days
+			list = append(list, functionToken(tok.text, 1))
+		case !expectOperand && tok.kind == tokenIdent && isCurrency(tok.text):
+			// money literal: 100 USD → 100 "USD" money/2
+			list = append(list, quoteLiteral(tok.text), functionToken("money", 2))
+		case expectOperand && tok.kind == tokenIdent && i+1 < len(tokens) && tokens[i+1].kind == tokenLParen:
+			// function call, "(" is a part of it
+			stack.Push(tok.text + "(")
+			arity = append(arity, 1)
+			opened = true
+			i++
+		case expectOperand && (tok.kind == tokenNumber || (tok.kind == tokenIdent && tok.text != "in")):
+			list = append(list, tok.text)
+			expectOperand = false
+		case expectOperand && tok.kind == tokenString:
+			list = append(list, quoteLiteral(tok.text))
+			expectOperand = false
+		case expectOperand && tok.text == "~":
+			// prefix operator - nothing to pop, it has no left operand
+			stack.Push(tok.text)
+		case expectOperand && tok.kind == tokenLParen:
+			stack.Push(tok.text)
+		case expectOperand && tok.kind == tokenLBracket:
+			stack.Push(tok.text)
+			arity = append(arity, 1)
+			opened = true
+		case !expectOperand && tok.kind == tokenOperator && tok.text != "~":
+			pushOperator(tok.text)
+			expectOperand = true
+		case !expectOperand && tok.kind == tokenComma:
+			for !stack.IsEmptyStack() && !isGroup(stack.Peek()) && stack.Peek() != "(" {
+				list = append(list, stack.Pop())
+			}
+			if stack.IsEmptyStack() || !isGroup(stack.Peek()) {
+				return nil, fmt.Errorf("%w: unexpected \",\" at position %d", ErrCovertExample, tok.pos)
 			}
+			arity[len(arity)-1]++
+			expectOperand = true
+		case (!expectOperand || wasOpened) && tok.kind == tokenRParen:
+			top, count, err := closeGroup(tok, wasOpened)
+			if err != nil {
+				return nil, err
+			}
+			if top == "[" {
+				return nil, fmt.Errorf("%w: unexpected \")\" at position %d", ErrCovertExample, tok.pos)
+			}
+			if top != "(" {
+				name := strings.TrimSuffix(top, "(")
+				if err := checkCall(name, count); err != nil {
+					return nil, err
+				}
+				list = append(list, functionToken(name, count))
+			}
+			expectOperand = false
+		case (!expectOperand || wasOpened) && tok.kind == tokenRBracket:
+			top, count, err := closeGroup(tok, wasOpened)
+			if err != nil {
+				return nil, err
+			}
+			if top != "[" {
+				return nil, fmt.Errorf("%w: unexpected \"]\" at position %d", ErrCovertExample, tok.pos)
+			}
+			list = append(list, functionToken("list", count))
+			expectOperand = false
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrCovertExample, tok.text, tok.pos)
 		}
 	}
-	if balance != 0 {
-		return false // Unbalanced brackets
+
+	if expectOperand {
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrCovertExample)
 	}
+	for !stack.IsEmptyStack() {
+		top := stack.Pop()
+		if top == "(" || isGroup(top) {
+			return nil, fmt.Errorf("%w: unbalanced brackets", ErrCovertExample)
+		}
+		list = append(list, top) // unload remaining stack
+	}
+	return list, nil
+}
 
-	// Check for consecutive operators
-	binaryOps := "+-*/^"
-	for i := 0; i < len(expr)-1; i++ {
-		curr := rune(expr[i])
-		next := rune(expr[i+1])
+// checkCall validates a call of a builtin function. Other names are
+// left to the caller, see UnknownFunctions.
+func checkCall(name string, arity int) error {
+	if !IsFunction(name) {
+		return nil
+	}
+	return checkArity(name, arity)
+}
 
-		// Two binary operators in a row - error
-		if strings.ContainsRune(binaryOps, curr) && strings.ContainsRune(binaryOps, next) {
-			return false
+// UnknownFunctions returns the names of called functions that are not
+// builtin. Convert must be called first.
+func (s *Expression) UnknownFunctions() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range strings.Fields(s.Postfix) {
+		name, _, ok := parseFunctionToken(item)
+		if ok && !IsFunction(name) && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
 		}
+	}
+	return names
+}
 
-		// ~ - unary minus
-		if curr == '~' {
-			if i == len(expr)-1 {
-				return false // ~ at the end
-			}
-			if next != '(' && !unicode.IsDigit(next) && next != '~' {
-				return false // After ~ must be number, ( or ~
-			}
+// Identifiers returns the names of the variables used in the expression
+// (in order of first appearance). Convert must be called first.
+func (s *Expression) Identifiers() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range strings.Fields(s.Postfix) {
+		if isIdentifier(item) && !seen[item] {
+			seen[item] = true
+			names = append(names, item)
 		}
 	}
+	return names
+}
 
-	// Check first character
-	first := rune(expr[0])
-	if !isDigitOrUnaryMinusOrOpenParen(first) {
-		return false
+// Substitute returns a copy of the expression where variables are replaced
+// by their values. Unknown variables are left as is.
+func (s *Expression) Substitute(values map[string]float64) *Expression {
+	items := strings.Fields(s.Postfix)
+	for i, item := range items {
+		if value, ok := values[item]; ok && isIdentifier(item) {
+			items[i] = NumberLiteral(value)
+		}
 	}
+	return &Expression{Infix: s.Infix, Postfix: strings.Join(items, " "), Syntax: s.Syntax}
+}
+
+// NumberLiteral - a number as an operand of tasks
+func NumberLiteral(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+func (s *Expression) Calculate() ([]*models.Task, string) {
+	results := make([]*models.Task, 0)
+	expression := strings.Split(s.Postfix, " ") // form a list of numbers and operators
+	lists := make(map[string]*models.Task)      // list literals by their variables, for aggregates
+
+	for {
+		index := nextOperation(expression)
+		if index < 0 {
+			break // a single operand left - it is the answer
+		}
+		sign := expression[index]
+
+		var newExpr []string
+		if name, arity, isFunction := parseFunctionToken(sign); isFunction {
+			// function call or list literal: takes arity operands
+			if index < arity {
+				return nil, "" // error: not enough operands
+			}
+			operands := append([]string{}, expression[index-arity:index]...)
 
-	// Check last character
-	last := rune(expr[len(expr)-1])
-	if strings.ContainsRune("+-*/^~", last) {
-		return false
+			tasks, variable := planCall(name, operands, lists)
+			for _, task := range tasks {
+				if task.Sign == "list" {
+					lists[task.Variable] = task
+				}
+			}
+			results = dropUnused(append(results, tasks...), lists, operands)
+			newExpr = replaceOperands(expression, index, arity, variable)
+		} else if sign == "~" {
+			// unary minus: ~X → 0 - X
+			if index < 1 {
+				return nil, "" // error: no operand
+			}
+			num1 := "0"
+			num2 := expression[index-1]
+			sign := "-" // always subtraction
+			result, variable := NewExample(num1, num2, sign)
+			results = append(results, &result)
+			newExpr = replaceUnary(expression, index, variable)
+		} else {
+			// Binary operator: +, -, *, /, ^
+			if index < 2 {
+				return nil, "" // error: not enough operands
+			}
+			num1 := expression[index-2]
+			num2 := expression[index-1]
+			if rows := matrixRows(num1, lists); sign == "*" && len(rows) > MatrixBlockRows {
+				tasks, variable := blockMultiply(rows, num2)
+				results = dropUnused(append(results, tasks...), lists, []string{num1})
+				newExpr = replaceBinary(expression, index, variable)
+			} else {
+				result, variable := NewExample(num1, num2, sign)
+				results = append(results, &result)
+				newExpr = replaceBinary(expression, index, variable)
+			}
+		}
+
+		expression = newExpr
 	}
+	return results, expression[0]
+}
 
-	return true
+// nextOperation - index of the first operator or function call, -1 if none
+func nextOperation(expression []string) int {
+	for index, item := range expression {
+		if _, isOperator := OperatorPriority[item]; isOperator {
+			return index
+		}
+		if _, _, isFunction := parseFunctionToken(item); isFunction {
+			return index
+		}
+	}
+	return -1
 }
 
-// isDigitOrUnaryMinusOrOpenParen checks if we can start an expression with this character.
-func isDigitOrUnaryMinusOrOpenParen(ch rune) bool {
-	return unicode.IsDigit(ch) || ch == '~' || ch == '('
+// planCall - tasks for one function call. Aggregates over many items
+// are reduced by a tree of tasks: each worker handles at most
+// ReduceChunkSize items and the partial results are combined level by level.
+func planCall(name string, operands []string, lists map[string]*models.Task) ([]*models.Task, string) {
+	fn := functions[name]
+	if fn.reduce != nil {
+		items, extra := aggregateItems(name, operands, lists)
+		if len(items) > ReduceChunkSize {
+			return reduceTree(fn.reduce, items, extra)
+		}
+	}
+	task, variable := NewCallExample(name, operands)
+	return []*models.Task{&task}, variable
 }
 
-func (s *Expression) Convert() (bool, error) {
-	if !s.Check() {
-		return false, fmt.Errorf("line is not a mathematical expression or contains an error")
+// aggregateItems - items to aggregate and extra arguments of the aggregate:
+// mean([1, 2, 3]) and mean(1, 2, 3) both aggregate 1, 2, 3,
+// percentile([1, 2, 3], 90) has extra argument 90
+func aggregateItems(name string, operands []string, lists map[string]*models.Task) ([]string, []string) {
+	items, extra := operands, []string{}
+	if name == "percentile" {
+		items, extra = operands[:1], operands[1:]
 	}
-	// Initialize list, stack, and list for numbers
-	list := make([]string, 0)
-	stack := NewStack()
-	example := strings.ReplaceAll(s.Infix, " ", "") // remove spaces
-	number := make([]rune, 0)
-	for _, i := range example {
-		sign := string(i)
-		if unicode.IsDigit(i) { // check if character is a digit
-			number = append(number, i) // add to numbers list
-			continue
-		} else if sign == "." {
-			number = append(number, rune(sign[0]))
-		} else {
-			if len(number) != 0 {
-				list = append(list, string(number)) // if not a digit, add the whole string to the list
-				number = make([]rune, 0)
-			}
+	if len(items) == 1 {
+		if list, ok := lists[items[0]]; ok {
+			return list.Args, extra
 		}
-		if value, isOperator := OperatorPriority[sign]; isOperator {
-			// Process operators: +, -, *, /, ^, ~, (
-			// Pop operators from stack with higher or equal priority
-			// BUT: if operator is right-associative (e.g., ^), don't pop at equal priority
-			for !stack.IsEmptyStack() {
-				top := stack.Peek()
-				if top == "(" {
-					break
-				}
+	}
+	if name == "percentile" {
+		return nil, extra // not a literal list, can't be split
+	}
+	return items, extra
+}
 
-				topPriority := OperatorPriority[top]
-
-				if topPriority > value {
-					list = append(list, stack.Pop())
-				} else if topPriority == value {
-					// If priority is equal
-					// Check associativity: if left-associative - pop, if right - don't
-					if !RightAssociative[sign] {
-						list = append(list, stack.Pop())
-					} else {
-						break
-					}
-				} else {
-					break
-				}
-			}
-			stack.Push(sign) // add current operator to stack
+func reduceTree(r *reduction, items, extra []string) ([]*models.Task, string) {
+	tasks := make([]*models.Task, 0)
+	level := items
+	sign := r.partial
+	for {
+		next := make([]string, 0, len(level)/ReduceChunkSize+1)
+		for start := 0; start < len(level); start += ReduceChunkSize {
+			end := min(start+ReduceChunkSize, len(level))
+			task, variable := NewCallExample(sign, append([]string{}, level[start:end]...))
+			tasks = append(tasks, &task)
+			next = append(next, variable)
 		}
-		if i == ')' { // extract operators from stack
-			for stack.Peek() != "(" {
-				list = append(list, stack.Pop())
-			}
-			stack.Pop() // remove "("
+		level = next
+		sign = r.combine
+		if len(level) == 1 {
+			break
 		}
 	}
-	if len(number) > 0 {
-		list = append(list, string(number)) // add last number if present
+
+	if r.final == "" {
+		return tasks, level[0]
 	}
-	for !stack.IsEmptyStack() {
-		list = append(list, stack.Pop()) // unload remaining stack
+	task, variable := NewCallExample(r.final, append(level, extra...))
+	return append(tasks, &task), variable
+}
+
+// matrixRows - variables of the rows if the operand is a matrix literal
+// (a list of list literals), nil otherwise
+func matrixRows(operand string, lists map[string]*models.Task) []string {
+	matrix, ok := lists[operand]
+	if !ok || len(matrix.Args) == 0 {
+		return nil
 	}
-	s.Postfix = strings.Join(list, " ")
-	return true, nil
+	for _, row := range matrix.Args {
+		if _, isList := lists[row]; !isList {
+			return nil
+		}
+	}
+	return matrix.Args
 }
 
-func (s *Expression) Calculate() ([]*models.Task, string) {
-	results := make([]*models.Task, 0)
-	expression := strings.Split(s.Postfix, " ") // form a list of numbers and operators
-	if len(expression) == 1 {
-		num := expression[0]
-		// consider this as: 0 + num
-		result, variable := NewExample("0", num, "+")
-		return append(results, &result), variable
-	}
-	for len(expression) != 1 {
-		for index, sign := range expression {
-			if _, isOperator := OperatorPriority[sign]; isOperator {
-				var num1, num2 string
-				var newExpr []string
-				if sign == "~" {
-					// unary minus: ~X → 0 - X
-					if index < 1 {
-						return nil, "" // error: no operand
-					}
-					num1 = "0"
-					num2 = expression[index-1]
-					sign := "-" // always subtraction
-					result, variable := NewExample(num1, num2, sign)
-					results = append(results, &result)
-					newExpr = replaceUnary(expression, index, variable)
-				} else {
-					// Binary operator: +, -, *, /, ^
-					if index < 2 {
-						return nil, "" // error: not enough operands
-					}
-					num1 = expression[index-2]
-					num2 = expression[index-1]
-					result, variable := NewExample(num1, num2, sign)
-					results = append(results, &result)
-					newExpr = replaceBinary(expression, index, variable)
-				}
+// blockMultiply - A * B for a big matrix literal A: every worker multiplies
+// a block of MatrixBlockRows rows of A by B, the blocks are stacked back
+func blockMultiply(rows []string, right string) ([]*models.Task, string) {
+	tasks := make([]*models.Task, 0)
+	products := make([]string, 0, len(rows)/MatrixBlockRows+1)
+	for start := 0; start < len(rows); start += MatrixBlockRows {
+		end := min(start+MatrixBlockRows, len(rows))
+		block, blockVariable := NewCallExample("list", append([]string{}, rows[start:end]...))
+		product, productVariable := NewExample(blockVariable, right, "*")
+		tasks = append(tasks, &block, &product)
+		products = append(products, productVariable)
+	}
+	task, variable := NewCallExample("_vstack", products)
+	return append(tasks, &task), variable
+}
 
-				expression = newExpr
+// dropUnused removes list literals that were consumed by a reduction tree
+// or a block multiplication: they read the items directly,
+// so nobody needs the list itself
+func dropUnused(tasks []*models.Task, lists map[string]*models.Task, operands []string) []*models.Task {
+	for _, operand := range operands {
+		if _, ok := lists[operand]; !ok || usesVariable(tasks, operand) {
+			continue
+		}
+		delete(lists, operand)
+		for i, task := range tasks {
+			if task.Variable == operand {
+				tasks = append(tasks[:i], tasks[i+1:]...)
 				break
 			}
 		}
 	}
-	return results, expression[0]
+	return tasks
 }
 
-func replaceUnary(expr []string, opIndex int, varName string) []string {
-	start := opIndex - 1
-	end := opIndex + 1
-	if start < 0 {
-		start = 0
-	}
-	if end > len(expr) {
-		end = len(expr)
+func usesVariable(tasks []*models.Task, variable string) bool {
+	for _, task := range tasks {
+		if task.Num1 == variable || task.Num2 == variable {
+			return true
+		}
+		for _, arg := range task.Args {
+			if arg == variable {
+				return true
+			}
+		}
 	}
-	return append(append(append([]string{}, expr[:start]...), varName), expr[end:]...)
+	return false
+}
+
+func replaceUnary(expr []string, opIndex int, varName string) []string {
+	return replaceOperands(expr, opIndex, 1, varName)
 }
 
 func replaceBinary(expr []string, opIndex int, varName string) []string {
-	start := opIndex - 2
+	return replaceOperands(expr, opIndex, 2, varName)
+}
+
+// replaceOperands replaces the operation and its count operands with the result variable
+func replaceOperands(expr []string, opIndex, count int, varName string) []string {
+	start := opIndex - count
 	end := opIndex + 1
 	if start < 0 {
 		start = 0
@@ -0,0 +1,363 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Notation - how numbers are written
+type Notation string
+
+const (
+	NotationAuto        Notation = ""            // as Value.String: 1234.5, 1e+300
+	NotationFixed       Notation = "fixed"       // 1234.5
+	NotationScientific  Notation = "scientific"  // 1.2345e3
+	NotationEngineering Notation = "engineering" // 1.2345e3, 12.345e3, the exponent is a multiple of 3
+	NotationFraction    Notation = "fraction"    // 3/8, the closest fraction with a bounded denominator
+)
+
+// Rounding - what to do with the dropped digits
+type Rounding string
+
+const (
+	RoundHalfEven Rounding = "half_even" // ties to the even digit, the default
+	RoundHalfUp   Rounding = "half_up"   // ties away from zero
+	RoundHalfDown Rounding = "half_down" // ties toward zero
+	RoundUp       Rounding = "up"        // away from zero
+	RoundDown     Rounding = "down"      // toward zero
+	RoundCeiling  Rounding = "ceiling"   // toward +Inf
+	RoundFloor    Rounding = "floor"     // toward -Inf
+)
+
+const (
+	maxFormatDigits = 100
+	// DefaultMaxDenominator - fractions by default: 355/113 for pi
+	DefaultMaxDenominator = 10000
+)
+
+// Format - how results are shown to clients. The zero Format is Value.String.
+// Rounding works on the shortest decimal form of a number, the one that is
+// shown without a format, so 2.675 rounds half up to 2.68.
+type Format struct {
+	Digits         int  // significant digits, 0 - as many as needed
+	Decimals       *int // digits after the point (of the mantissa in scientific notations), not together with Digits
+	Rounding       Rounding
+	Notation       Notation
+	MaxDenominator int64  // fraction notation only, DefaultMaxDenominator if 0
+	Locale         Locale // separators of the output, the zero Locale writes numbers as expressions do
+}
+
+func (f Format) Validate() error {
+	switch f.Notation {
+	case NotationAuto, NotationFixed, NotationScientific, NotationEngineering, NotationFraction:
+	default:
+		return fmt.Errorf("%w: unknown notation %q", ErrInvalidArgument, f.Notation)
+	}
+	switch f.Rounding {
+	case "", RoundHalfEven, RoundHalfUp, RoundHalfDown, RoundUp, RoundDown, RoundCeiling, RoundFloor:
+	default:
+		return fmt.Errorf("%w: unknown rounding %q", ErrInvalidArgument, f.Rounding)
+	}
+	switch {
+	case f.Digits < 0 || f.Digits > maxFormatDigits:
+		return fmt.Errorf("%w: significant digits must be from 1 to %d", ErrInvalidArgument, maxFormatDigits)
+	case f.Decimals != nil && (*f.Decimals < 0 || *f.Decimals > maxFormatDigits):
+		return fmt.Errorf("%w: decimal places must be from 0 to %d", ErrInvalidArgument, maxFormatDigits)
+	case f.Decimals != nil && f.Digits > 0:
+		return fmt.Errorf("%w: significant digits and decimal places can't be set together", ErrInvalidArgument)
+	case f.MaxDenominator < 0:
+		return fmt.Errorf("%w: max denominator must be positive", ErrInvalidArgument)
+	}
+	return nil
+}
+
+func (f Format) isZero() bool {
+	return f.plain() && f.Locale.Decimal == 0
+}
+
+// plain - no precision and no notation, maybe a locale
+func (f Format) plain() bool {
+	return f.Digits == 0 && f.Decimals == nil && f.Notation == NotationAuto
+}
+
+// Value - numbers and elements of lists and matrices by the format,
+// amounts of money too, other values as Value.String
+func (f Format) Value(v Value) string {
+	switch {
+	case v.IsNumber():
+		return f.Number(v.Number)
+	case v.Kind == KindInt && !f.isZero() && f.Notation != NotationFraction:
+		return f.Locale.number(f.decimal(decimalOfInt(v.Int)))
+	case v.Kind == KindList:
+		items := make([]string, len(v.List))
+		for i, item := range v.List {
+			items[i] = f.Value(item)
+		}
+		return "[" + strings.Join(items, f.Locale.separator()) + "]"
+	case v.Kind == KindMatrix:
+		rows := make([]string, len(v.Matrix))
+		for i, row := range v.Matrix {
+			rows[i] = f.Value(NumbersValue(row))
+		}
+		return "[" + strings.Join(rows, f.Locale.separator()) + "]"
+	case v.Kind == KindMoney && !f.isZero():
+		if f.plain() {
+			cents := 2 // only the locale is set
+			f.Decimals = &cents
+		}
+		return f.Number(v.Number) + " " + v.Currency
+	}
+	return v.String()
+}
+
+// Number - one number by the format
+func (f Format) Number(x float64) string {
+	if f.isZero() || math.IsInf(x, 0) || math.IsNaN(x) {
+		return f.Locale.number(NumberValue(x).String())
+	}
+	if f.Notation == NotationFraction {
+		return f.Locale.number(fraction(x, f.MaxDenominator))
+	}
+	return f.Locale.number(f.decimal(decimalOf(x)))
+}
+
+func (f Format) decimal(d decimal) string {
+	notation := f.Notation
+	if notation == NotationAuto {
+		// like %g: fixed unless the number is very big or very small
+		notation = NotationFixed
+		if len(d.digits) > 0 && (d.exp > 21 || d.exp < -6) {
+			notation = NotationScientific
+		}
+	}
+
+	if notation == NotationFixed {
+		switch {
+		case f.Decimals != nil:
+			d.round(d.exp+*f.Decimals, f.Rounding)
+			return d.fixed(*f.Decimals)
+		case f.Digits > 0:
+			d.round(f.Digits, f.Rounding)
+			return d.fixed(max(0, f.Digits-d.exp))
+		}
+		return d.fixed(max(0, len(d.digits)-d.exp))
+	}
+
+	step := 1
+	if notation == NotationEngineering {
+		step = 3
+	}
+	switch {
+	case f.Decimals != nil:
+		d.round(d.intDigits(step)+*f.Decimals, f.Rounding)
+		return d.scientific(*f.Decimals, step)
+	case f.Digits > 0:
+		d.round(f.Digits, f.Rounding)
+		return d.scientific(max(0, f.Digits-d.intDigits(step)), step)
+	}
+	return d.scientific(max(0, len(d.digits)-d.intDigits(step)), step)
+}
+
+// decimal - |x| = 0.digits × 10^exp, digits have no trailing zeros,
+// no digits is zero
+type decimal struct {
+	neg    bool
+	digits []byte // '0'..'9'
+	exp    int
+}
+
+func decimalOf(x float64) decimal {
+	d := decimal{neg: x < 0}
+	if x == 0 {
+		return d
+	}
+	// shortest form that reads back as x: 1.2345e+03
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(math.Abs(x), 'e', -1, 64), "e")
+	e, _ := strconv.Atoi(exponent)
+	d.digits = []byte(strings.Replace(mantissa, ".", "", 1))
+	d.exp = e + 1
+	d.trim()
+	return d
+}
+
+func decimalOfInt(n *big.Int) decimal {
+	text := new(big.Int).Abs(n).String()
+	d := decimal{neg: n.Sign() < 0, digits: []byte(text), exp: len(text)}
+	d.trim()
+	return d
+}
+
+func (d *decimal) trim() {
+	for len(d.digits) > 0 && d.digits[len(d.digits)-1] == '0' {
+		d.digits = d.digits[:len(d.digits)-1]
+	}
+	if len(d.digits) == 0 {
+		d.neg, d.exp = false, 0
+	}
+}
+
+// digit - i-th digit, zeros beyond the stored ones
+func (d decimal) digit(i int) byte {
+	if i >= 0 && i < len(d.digits) {
+		return d.digits[i]
+	}
+	return '0'
+}
+
+// intDigits - digits before the point of the mantissa when the exponent
+// is a multiple of step
+func (d decimal) intDigits(step int) int {
+	if len(d.digits) == 0 {
+		return 1
+	}
+	return d.exp - d.exponent(step)
+}
+
+func (d decimal) exponent(step int) int {
+	if len(d.digits) == 0 {
+		return 0
+	}
+	e := d.exp - 1
+	if e < 0 {
+		e -= step - 1 // round toward -Inf
+	}
+	return e / step * step
+}
+
+// round keeps the first n digits, n may be 0 or negative:
+// 0.004 rounded to 2 decimals keeps 0 digits
+func (d *decimal) round(n int, mode Rounding) {
+	if n >= len(d.digits) {
+		return
+	}
+
+	next, rest := byte('0'), true // the first dropped digit and if any other is non-zero
+	if n >= 0 {
+		next = d.digits[n]
+		rest = strings.Trim(string(d.digits[n+1:]), "0") != ""
+	}
+	dropped := next != '0' || rest
+	odd := n > 0 && (d.digits[n-1]-'0')%2 == 1
+
+	var up bool
+	switch mode {
+	case RoundUp:
+		up = dropped
+	case RoundDown:
+		up = false
+	case RoundCeiling:
+		up = dropped && !d.neg
+	case RoundFloor:
+		up = dropped && d.neg
+	case RoundHalfUp:
+		up = next >= '5'
+	case RoundHalfDown:
+		up = next > '5' || (next == '5' && rest)
+	default:
+		up = next > '5' || (next == '5' && (rest || odd))
+	}
+
+	if n <= 0 {
+		if up {
+			// one unit of the last kept place
+			d.digits, d.exp = []byte{'1'}, d.exp-n+1
+		} else {
+			d.digits = nil
+		}
+		d.trim()
+		return
+	}
+
+	kept := d.digits[:n]
+	if up {
+		i := n - 1
+		for i >= 0 && kept[i] == '9' {
+			kept[i] = '0'
+			i--
+		}
+		if i < 0 {
+			kept = append([]byte{'1'}, kept...)
+			d.exp++
+		} else {
+			kept[i]++
+		}
+	}
+	d.digits = kept
+	d.trim()
+}
+
+// fixed - with exactly frac digits after the point
+func (d decimal) fixed(frac int) string {
+	var b strings.Builder
+	if d.neg {
+		b.WriteByte('-')
+	}
+	if d.exp <= 0 {
+		b.WriteByte('0')
+	}
+	for i := 0; i < d.exp; i++ {
+		b.WriteByte(d.digit(i))
+	}
+	if frac > 0 {
+		b.WriteByte('.')
+		for i := 0; i < frac; i++ {
+			b.WriteByte(d.digit(d.exp + i))
+		}
+	}
+	return b.String()
+}
+
+// scientific - mantissa with frac digits after the point and an exponent
+// that is a multiple of step
+func (d decimal) scientific(frac, step int) string {
+	e := d.exponent(step)
+	mantissa := decimal{neg: d.neg, digits: d.digits, exp: d.exp - e}
+	return mantissa.fixed(frac) + "e" + strconv.Itoa(e)
+}
+
+// fraction - the closest fraction with the denominator up to maxDen
+// (continued fractions): 0.375 → 3/8, pi → 355/113
+func fraction(x float64, maxDen int64) string {
+	if maxDen <= 0 {
+		maxDen = DefaultMaxDenominator
+	}
+	if math.Abs(x) >= 1<<53 {
+		return NumberValue(x).String() // an integer already
+	}
+
+	sign := ""
+	if x < 0 {
+		sign, x = "-", -x
+	}
+
+	// convergents h/k, starting with 0/1 and 1/0
+	h0, h1 := int64(0), int64(1)
+	k0, k1 := int64(1), int64(0)
+	y := x
+	for {
+		a := math.Floor(y)
+		if a > 1<<53 {
+			break
+		}
+		h2, k2 := int64(a)*h1+h0, int64(a)*k1+k0
+		if k2 > maxDen {
+			break
+		}
+		h0, h1, k0, k1 = h1, h2, k1, k2
+		if y == a || float64(h1)/float64(k1) == x {
+			break
+		}
+		y = 1 / (y - a)
+	}
+
+	switch {
+	case h1 == 0:
+		return "0"
+	case k1 == 1:
+		return sign + strconv.FormatInt(h1, 10)
+	}
+	return sign + strconv.FormatInt(h1, 10) + "/" + strconv.FormatInt(k1, 10)
+}
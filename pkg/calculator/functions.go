@@ -0,0 +1,344 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReduceChunkSize - how many items one worker reduces at most.
+// Bigger aggregates are split into a tree of tasks.
+var ReduceChunkSize = 64
+
+// function - builtin function available in expressions
+type function struct {
+	minArgs int
+	maxArgs int // -1 - any number of arguments
+	apply   func(args []Value) (Value, error)
+	// random functions draw from a generator seeded per task instead
+	seeded func(rng *rand.Rand, args []Value) (Value, error)
+
+	// internal functions are only produced by the planner
+	internal bool
+	// how to split the function into a tree of tasks (aggregates only)
+	reduce *reduction
+}
+
+// reduction - aggregate as partial -> combine -> ... -> final
+type reduction struct {
+	partial string // applied to chunks of items
+	combine string // applied to chunks of partial results
+	final   string // applied to the last partial result, may be empty
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		// list literal: [1, 2, 3], list of rows is a matrix: [[1, 2], [3, 4]]
+		"list": {minArgs: 0, maxArgs: -1, apply: listOf},
+
+		"sum": {minArgs: 1, maxArgs: -1, apply: sumOf, reduce: &reduction{partial: "sum", combine: "sum"}},
+		"min": {minArgs: 1, maxArgs: -1, apply: minOf, reduce: &reduction{partial: "min", combine: "min"}},
+		"max": {minArgs: 1, maxArgs: -1, apply: maxOf, reduce: &reduction{partial: "max", combine: "max"}},
+		"mean": {minArgs: 1, maxArgs: -1, apply: meanOf,
+			reduce: &reduction{partial: "_moments", combine: "_merge_moments", final: "_mean"}},
+		"variance": {minArgs: 1, maxArgs: -1, apply: varianceOf,
+			reduce: &reduction{partial: "_moments", combine: "_merge_moments", final: "_variance"}},
+		"stddev": {minArgs: 1, maxArgs: -1, apply: stddevOf,
+			reduce: &reduction{partial: "_moments", combine: "_merge_moments", final: "_stddev"}},
+		"median": {minArgs: 1, maxArgs: -1, apply: medianOf,
+			reduce: &reduction{partial: "_sort", combine: "_merge_sorted", final: "median"}},
+		"percentile": {minArgs: 2, maxArgs: 2, apply: percentileOf,
+			reduce: &reduction{partial: "_sort", combine: "_merge_sorted", final: "percentile"}},
+
+		"det":       {minArgs: 1, maxArgs: 1, apply: determinant},
+		"inv":       {minArgs: 1, maxArgs: 1, apply: inverse},
+		"transpose": {minArgs: 1, maxArgs: 1, apply: transpose},
+		"dot":       {minArgs: 2, maxArgs: 2, apply: dot},
+
+		// number theory, exact on integers of any size
+		"gcd":     {minArgs: 2, maxArgs: -1, apply: gcdOf},
+		"lcm":     {minArgs: 2, maxArgs: -1, apply: lcmOf},
+		"modpow":  {minArgs: 3, maxArgs: 3, apply: modPow},
+		"isprime": {minArgs: 1, maxArgs: 1, apply: isPrime},
+		"factor":  {minArgs: 1, maxArgs: 1, apply: factorOf},
+		"nCr":     {minArgs: 2, maxArgs: 2, apply: binomial},
+
+		// dates and durations, units are added in init below
+		"date":        {minArgs: 1, maxArgs: 1, apply: parseDate},
+		"now":         {minArgs: 0, maxArgs: 0, apply: now},
+		"workdays":    {minArgs: 2, maxArgs: 2, apply: workdays},
+		"addworkdays": {minArgs: 2, maxArgs: 2, apply: addWorkdays},
+
+		// money(100, "USD"), also written as 100 USD
+		"money": {minArgs: 2, maxArgs: 2, apply: money},
+
+		// random numbers, reproducible with the seed of the example
+		"rand":    {minArgs: 0, maxArgs: 0, seeded: uniform},
+		"randint": {minArgs: 2, maxArgs: 2, seeded: randomInt},
+		"normal":  {minArgs: 2, maxArgs: 2, seeded: normal},
+
+		// steps of the reduction tree
+		"_moments":       {minArgs: 1, maxArgs: -1, apply: momentsOf, internal: true},
+		"_merge_moments": {minArgs: 1, maxArgs: -1, apply: mergeMoments, internal: true},
+		"_mean":          {minArgs: 1, maxArgs: 1, apply: momentsMean, internal: true},
+		"_variance":      {minArgs: 1, maxArgs: 1, apply: momentsVariance, internal: true},
+		"_stddev":        {minArgs: 1, maxArgs: 1, apply: momentsStddev, internal: true},
+		"_sort":          {minArgs: 1, maxArgs: -1, apply: sortedOf, internal: true},
+		"_merge_sorted":  {minArgs: 1, maxArgs: -1, apply: sortedOf, internal: true},
+
+		// joins row blocks of a matrix product
+		"_vstack": {minArgs: 1, maxArgs: -1, apply: vstack, internal: true},
+	}
+
+	// days(90), also written as 90 days
+	for name, unit := range durationUnits {
+		functions[name] = function{minArgs: 1, maxArgs: 1, apply: durationOf(unit)}
+	}
+}
+
+// IsFunction checks if the sign of a task is a builtin function
+func IsFunction(sign string) bool {
+	_, ok := functions[sign]
+	return ok
+}
+
+// IsInternal checks if the sign of a task is a step only the planner produces
+func IsInternal(sign string) bool {
+	return functions[sign].internal
+}
+
+// functionToken - name of the function and the number of arguments
+// in postfix notation: "mean/3"
+func functionToken(name string, arity int) string {
+	return name + "/" + strconv.Itoa(arity)
+}
+
+// parseFunctionToken - reverse of functionToken
+func parseFunctionToken(item string) (string, int, bool) {
+	slash := strings.LastIndexByte(item, '/')
+	if slash <= 0 {
+		return "", 0, false
+	}
+	arity, err := strconv.Atoi(item[slash+1:])
+	if err != nil || arity < 0 {
+		return "", 0, false
+	}
+	return item[:slash], arity, true
+}
+
+// checkArity validates a call of a builtin function
+func checkArity(name string, arity int) error {
+	fn, ok := functions[name]
+	if !ok || fn.internal {
+		return fmt.Errorf("%w: %s", ErrUnknownFunction, name)
+	}
+	if arity < fn.minArgs || (fn.maxArgs >= 0 && arity > fn.maxArgs) {
+		return fmt.Errorf("%w: wrong number of arguments for %s: %d", ErrInvalidArgument, name, arity)
+	}
+	return nil
+}
+
+func applyFunction(name string, args []Value, seed uint64) (Value, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return Value{}, fmt.Errorf("%w: %s", ErrUnknownFunction, name)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return Value{}, fmt.Errorf("%w: wrong number of arguments for %s: %d", ErrInvalidArgument, name, len(args))
+	}
+	if fn.seeded != nil {
+		return fn.seeded(newRand(seed), args)
+	}
+	return fn.apply(args)
+}
+
+// nonEmpty - numbers of the arguments, error for an empty list
+func nonEmpty(args []Value) ([]float64, error) {
+	items, err := numbers(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: empty list", ErrInvalidArgument)
+	}
+	return items, nil
+}
+
+func sumOf(args []Value) (Value, error) {
+	items, err := numbers(args)
+	if err != nil {
+		return Value{}, err
+	}
+	sum := 0.0
+	for _, item := range items {
+		sum += item
+	}
+	return NumberValue(sum), nil
+}
+
+func minOf(args []Value) (Value, error) {
+	items, err := nonEmpty(args)
+	if err != nil {
+		return Value{}, err
+	}
+	result := items[0]
+	for _, item := range items[1:] {
+		result = math.Min(result, item)
+	}
+	return NumberValue(result), nil
+}
+
+func maxOf(args []Value) (Value, error) {
+	items, err := nonEmpty(args)
+	if err != nil {
+		return Value{}, err
+	}
+	result := items[0]
+	for _, item := range items[1:] {
+		result = math.Max(result, item)
+	}
+	return NumberValue(result), nil
+}
+
+func meanOf(args []Value) (Value, error) {
+	moments, err := momentsOf(args)
+	if err != nil {
+		return Value{}, err
+	}
+	return momentsMean([]Value{moments})
+}
+
+// varianceOf - population variance
+func varianceOf(args []Value) (Value, error) {
+	moments, err := momentsOf(args)
+	if err != nil {
+		return Value{}, err
+	}
+	return momentsVariance([]Value{moments})
+}
+
+// stddevOf - population standard deviation
+func stddevOf(args []Value) (Value, error) {
+	moments, err := momentsOf(args)
+	if err != nil {
+		return Value{}, err
+	}
+	return momentsStddev([]Value{moments})
+}
+
+func medianOf(args []Value) (Value, error) {
+	items, err := nonEmpty(args)
+	if err != nil {
+		return Value{}, err
+	}
+	sort.Float64s(items)
+	return NumberValue(percentileSorted(items, 50)), nil
+}
+
+// percentileOf - percentile(list, p), p from 0 to 100,
+// linear interpolation between the closest ranks
+func percentileOf(args []Value) (Value, error) {
+	if args[0].Kind != KindList || !args[1].IsNumber() {
+		return Value{}, fmt.Errorf("%w: percentile expects a list and a number", ErrInvalidArgument)
+	}
+	p := args[1].Number
+	if p < 0 || p > 100 {
+		return Value{}, fmt.Errorf("%w: percentile must be from 0 to 100, got %g", ErrInvalidArgument, p)
+	}
+	items, err := nonEmpty(args[:1])
+	if err != nil {
+		return Value{}, err
+	}
+	sort.Float64s(items)
+	return NumberValue(percentileSorted(items, p)), nil
+}
+
+func percentileSorted(items []float64, p float64) float64 {
+	rank := p / 100 * float64(len(items)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return items[lower] + (items[upper]-items[lower])*(rank-float64(lower))
+}
+
+// momentsOf - partial statistics of a chunk: [count, mean, M2]
+// (M2 - sum of squared deviations from the mean)
+func momentsOf(args []Value) (Value, error) {
+	items, err := nonEmpty(args)
+	if err != nil {
+		return Value{}, err
+	}
+	mean := 0.0
+	m2 := 0.0
+	for i, item := range items {
+		// Welford's online algorithm
+		delta := item - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (item - mean)
+	}
+	return NumbersValue([]float64{float64(len(items)), mean, m2}), nil
+}
+
+// mergeMoments - combines partial statistics of several chunks
+// (parallel algorithm of Chan et al.)
+func mergeMoments(args []Value) (Value, error) {
+	count, mean, m2 := 0.0, 0.0, 0.0
+	for _, arg := range args {
+		n, m, s, err := unpackMoments(arg)
+		if err != nil {
+			return Value{}, err
+		}
+		total := count + n
+		delta := m - mean
+		mean += delta * n / total
+		m2 += s + delta*delta*count*n/total
+		count = total
+	}
+	return NumbersValue([]float64{count, mean, m2}), nil
+}
+
+func unpackMoments(v Value) (float64, float64, float64, error) {
+	items, err := numbers([]Value{v})
+	if err != nil || v.Kind != KindList || len(items) != 3 || items[0] <= 0 {
+		return 0, 0, 0, fmt.Errorf("%w: bad partial statistics", ErrInvalidArgument)
+	}
+	return items[0], items[1], items[2], nil
+}
+
+func momentsMean(args []Value) (Value, error) {
+	_, mean, _, err := unpackMoments(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	return NumberValue(mean), nil
+}
+
+func momentsVariance(args []Value) (Value, error) {
+	count, _, m2, err := unpackMoments(args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	return NumberValue(m2 / count), nil
+}
+
+func momentsStddev(args []Value) (Value, error) {
+	variance, err := momentsVariance(args)
+	if err != nil {
+		return Value{}, err
+	}
+	return NumberValue(math.Sqrt(variance.Number)), nil
+}
+
+// sortedOf - all numbers of the arguments in ascending order
+func sortedOf(args []Value) (Value, error) {
+	items, err := numbers(args)
+	if err != nil {
+		return Value{}, err
+	}
+	sort.Float64s(items)
+	return NumbersValue(items), nil
+}
@@ -0,0 +1,291 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// MaxIntBits - limit for exact integer results, bigger powers
+// fall back to float64 as before
+var MaxIntBits = 1 << 16
+
+// maxExactFloat - integers up to 2^53 are exact in float64
+const maxExactFloat = 1 << 53
+
+var bigMaxExact = big.NewInt(maxExactFloat)
+
+func IntValue(n *big.Int) Value {
+	return Value{Kind: KindInt, Int: n}
+}
+
+// intResult - integers that fit float64 exactly stay plain numbers,
+// only bigger ones become KindInt
+func intResult(n *big.Int) Value {
+	if new(big.Int).Abs(n).Cmp(bigMaxExact) <= 0 {
+		return NumberValue(float64(n.Int64()))
+	}
+	return IntValue(n)
+}
+
+// Exact - the value as float64 if it is a number that fits float64 exactly
+func (v Value) Exact() (float64, bool) {
+	switch {
+	case v.IsNumber():
+		return v.Number, true
+	case v.Kind == KindInt && v.Int.IsInt64() && math.Abs(float64(v.Int.Int64())) <= maxExactFloat:
+		return float64(v.Int.Int64()), true
+	}
+	return 0, false
+}
+
+// toBig - integer form of a number or an Int, false for fractions
+func toBig(v Value) (*big.Int, bool) {
+	switch {
+	case v.Kind == KindInt:
+		return new(big.Int).Set(v.Int), true
+	case v.IsNumber():
+		if math.IsInf(v.Number, 0) || math.IsNaN(v.Number) || v.Number != math.Trunc(v.Number) {
+			return nil, false
+		}
+		n, _ := new(big.Float).SetFloat64(v.Number).Int(nil)
+		return n, true
+	}
+	return nil, false
+}
+
+// toFloat - approximate float64 form of a number or an Int
+func toFloat(v Value) float64 {
+	if v.Kind == KindInt {
+		f, _ := new(big.Float).SetInt(v.Int).Float64()
+		return f
+	}
+	return v.Number
+}
+
+// exactOperator keeps integer arithmetic exact when float64 can't:
+// an Int operand or an integer result beyond 2^53. Returns false if
+// the operands should be handled as usual.
+func exactOperator(sign string, a, b Value) (Value, bool, error) {
+	scalar := func(v Value) bool { return v.IsNumber() || v.Kind == KindInt }
+	if !scalar(a) || !scalar(b) {
+		return Value{}, false, nil
+	}
+	isInt := a.Kind == KindInt || b.Kind == KindInt
+
+	x, okX := toBig(a)
+	y, okY := toBig(b)
+	if !okX || !okY {
+		if !isInt {
+			return Value{}, false, nil
+		}
+		// an Int with a fraction - approximate
+		result, err := NewNode(toFloat(a), toFloat(b), sign).Calculate()
+		return NumberValue(result), true, err
+	}
+
+	// plain numbers only need big arithmetic if float64 loses digits
+	if !isInt {
+		result, err := NewNode(a.Number, b.Number, sign).Calculate()
+		if err != nil || math.Abs(result) <= maxExactFloat || sign == "/" {
+			return Value{}, false, nil
+		}
+	}
+
+	switch sign {
+	case "+":
+		return intResult(x.Add(x, y)), true, nil
+	case "-":
+		return intResult(x.Sub(x, y)), true, nil
+	case "*":
+		return intResult(x.Mul(x, y)), true, nil
+	case "/":
+		if y.Sign() == 0 {
+			return Value{}, true, ErrDivisionByZero
+		}
+		quotient, remainder := new(big.Int).QuoRem(x, y, new(big.Int))
+		if remainder.Sign() == 0 {
+			return intResult(quotient), true, nil
+		}
+		result, _ := new(big.Rat).SetFrac(x, y).Float64()
+		return NumberValue(result), true, nil
+	case "^":
+		if y.Sign() < 0 || !y.IsInt64() || y.Int64() > int64(MaxIntBits) || int64(x.BitLen())*y.Int64() > int64(MaxIntBits) {
+			result, err := NewNode(toFloat(a), toFloat(b), sign).Calculate()
+			return NumberValue(result), true, err
+		}
+		return intResult(x.Exp(x, y, nil)), true, nil
+	}
+	return Value{}, true, ErrNonExistingOperation
+}
+
+// integerArgs - all arguments as integers, fractions are not allowed
+func integerArgs(name string, args []Value) ([]*big.Int, error) {
+	result := make([]*big.Int, len(args))
+	for i, arg := range args {
+		n, ok := toBig(arg)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s expects integers, got %s", ErrInvalidArgument, name, arg)
+		}
+		result[i] = n
+	}
+	return result, nil
+}
+
+func gcdOf(args []Value) (Value, error) {
+	items, err := integerArgs("gcd", args)
+	if err != nil {
+		return Value{}, err
+	}
+	result := new(big.Int)
+	for _, item := range items {
+		result.GCD(nil, nil, result, item.Abs(item))
+	}
+	return intResult(result), nil
+}
+
+func lcmOf(args []Value) (Value, error) {
+	items, err := integerArgs("lcm", args)
+	if err != nil {
+		return Value{}, err
+	}
+	result := big.NewInt(1)
+	for _, item := range items {
+		if item.Sign() == 0 {
+			return NumberValue(0), nil
+		}
+		item.Abs(item)
+		gcd := new(big.Int).GCD(nil, nil, result, item)
+		result.Mul(result, item.Quo(item, gcd))
+	}
+	return intResult(result), nil
+}
+
+// modPow - base ^ exp mod m, a negative exponent uses the modular inverse
+func modPow(args []Value) (Value, error) {
+	items, err := integerArgs("modpow", args)
+	if err != nil {
+		return Value{}, err
+	}
+	base, exp, mod := items[0], items[1], items[2]
+	if mod.Sign() <= 0 {
+		return Value{}, fmt.Errorf("%w: modpow modulus must be positive", ErrInvalidArgument)
+	}
+	result := new(big.Int).Exp(base, exp, mod)
+	if result == nil {
+		return Value{}, fmt.Errorf("%w: %s has no inverse modulo %s", ErrInvalidArgument, base, mod)
+	}
+	return intResult(result), nil
+}
+
+// isPrime - 1 for primes, 0 otherwise. Exact below 2^64,
+// probabilistic (Miller-Rabin + Baillie-PSW) above
+func isPrime(args []Value) (Value, error) {
+	items, err := integerArgs("isprime", args)
+	if err != nil {
+		return Value{}, err
+	}
+	if items[0].ProbablyPrime(20) {
+		return NumberValue(1), nil
+	}
+	return NumberValue(0), nil
+}
+
+// binomial - nCr(n, k), number of k-element subsets of n elements
+func binomial(args []Value) (Value, error) {
+	items, err := integerArgs("nCr", args)
+	if err != nil {
+		return Value{}, err
+	}
+	n, k := items[0], items[1]
+	if n.Sign() < 0 || k.Sign() < 0 {
+		return Value{}, fmt.Errorf("%w: nCr expects non-negative integers", ErrInvalidArgument)
+	}
+	if k.Cmp(n) > 0 {
+		return NumberValue(0), nil
+	}
+	if !n.IsInt64() || n.Int64() > maxBinomialN {
+		return Value{}, fmt.Errorf("%w: nCr is limited to n <= %d", ErrInvalidArgument, maxBinomialN)
+	}
+	return intResult(new(big.Int).Binomial(n.Int64(), k.Int64())), nil
+}
+
+const (
+	maxBinomialN = 100000
+	// trial division bound and Pollard's rho iterations per attempt
+	smallFactorBound = 10000
+	rhoIterations    = 1 << 18
+)
+
+// factorOf - prime factors in ascending order with repetitions:
+// factor(12) = [2, 2, 3]
+func factorOf(args []Value) (Value, error) {
+	items, err := integerArgs("factor", args)
+	if err != nil {
+		return Value{}, err
+	}
+	n := items[0]
+	if n.Sign() <= 0 {
+		return Value{}, fmt.Errorf("%w: factor expects a positive integer", ErrInvalidArgument)
+	}
+
+	factors := make([]*big.Int, 0)
+	// small factors by trial division
+	for p := int64(2); p <= smallFactorBound && n.Cmp(big.NewInt(p*p)) >= 0; p++ {
+		divisor := big.NewInt(p)
+		for new(big.Int).Mod(n, divisor).Sign() == 0 {
+			factors = append(factors, divisor)
+			n = new(big.Int).Quo(n, divisor)
+		}
+	}
+
+	// the rest with Pollard's rho
+	pending := []*big.Int{n}
+	for len(pending) > 0 {
+		m := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		switch {
+		case m.Cmp(big.NewInt(1)) == 0:
+		case m.ProbablyPrime(20):
+			factors = append(factors, m)
+		default:
+			d := pollardRho(m)
+			if d == nil {
+				return Value{}, fmt.Errorf("%w: %s is too hard to factor", ErrInvalidArgument, m)
+			}
+			pending = append(pending, d, new(big.Int).Quo(m, d))
+		}
+	}
+
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Cmp(factors[j]) < 0 })
+	result := make([]Value, len(factors))
+	for i, factor := range factors {
+		result[i] = intResult(factor)
+	}
+	return ListValue(result), nil
+}
+
+// pollardRho - a non-trivial divisor of a composite n (Floyd's cycle detection),
+// nil if it wasn't found in rhoIterations steps
+func pollardRho(n *big.Int) *big.Int {
+	one := big.NewInt(1)
+	for c := int64(1); c <= 5; c++ {
+		x, y := big.NewInt(2), big.NewInt(2)
+		step := func(v *big.Int) *big.Int {
+			v.Mul(v, v).Add(v, big.NewInt(c))
+			return v.Mod(v, n)
+		}
+		d := big.NewInt(1)
+		diff := new(big.Int)
+		for i := 0; i < rhoIterations && d.Cmp(one) == 0; i++ {
+			step(x)
+			step(step(y))
+			d.GCD(nil, nil, diff.Abs(diff.Sub(x, y)), n)
+		}
+		if d.Cmp(one) != 0 && d.Cmp(n) != 0 {
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package calculator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenNumber   tokenKind = iota // 2, 2.5
+	tokenIdent                     // x, y, rate
+	tokenOperator                  // + - * / ^ ~
+	tokenLParen                    // (
+	tokenRParen                    // )
+	tokenComma                     // ,
+	tokenLBracket                  // [
+	tokenRBracket                  // ]
+	tokenString                    // "2026-10-16", text is without quotes
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // position in the original string (in runes)
+}
+
+// tokenize splits an infix expression into tokens.
+// Spaces are ignored, everything else must be recognized.
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0, len(runes))
+
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case unicode.IsDigit(ch):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			if i < len(runes) && runes[i] == '.' {
+				i++
+				// forbid decimal point without digits after it
+				if i >= len(runes) || !unicode.IsDigit(runes[i]) {
+					return nil, fmt.Errorf("%w: bad number at position %d", ErrCovertExample, start)
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case isIdentStart(ch) || (ch == '@' && i+1 < len(runes) && isIdentStart(runes[i+1])):
+			// names and references to examples: @last
+			start := i
+			i++
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		case ch == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("%w: unclosed string at position %d", ErrCovertExample, start)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[start+1 : i]), pos: start})
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case ch == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case ch == '[':
+			tokens = append(tokens, token{kind: tokenLBracket, text: "[", pos: i})
+			i++
+		case ch == ']':
+			tokens = append(tokens, token{kind: tokenRBracket, text: "]", pos: i})
+			i++
+		default:
+			if _, isOperator := OperatorPriority[string(ch)]; isOperator {
+				tokens = append(tokens, token{kind: tokenOperator, text: string(ch), pos: i})
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("%w: unexpected symbol %q at position %d", ErrCovertExample, ch, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentStart(ch rune) bool {
+	return ch == '_' || (ch < unicode.MaxASCII && unicode.IsLetter(ch))
+}
+
+func isIdentPart(ch rune) bool {
+	return isIdentStart(ch) || unicode.IsDigit(ch)
+}
+
+// isIdentifier checks that the whole string is a valid variable name
+// or a reference to an example: @last, @<id>
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	if strings.HasPrefix(s, "@") {
+		return len(s) > 1 && strings.IndexFunc(s[1:], func(ch rune) bool { return !isIdentPart(ch) && ch != '-' }) < 0
+	}
+	for i, ch := range s {
+		if i == 0 && !isIdentStart(ch) {
+			return false
+		}
+		if !isIdentPart(ch) {
+			return false
+		}
+	}
+	return true
+}
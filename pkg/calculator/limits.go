@@ -0,0 +1,111 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+)
+
+// ErrLimitExceeded - the expression is too big to accept, nothing is saved
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits - how big an expression may be, zero means no limit
+type Limits struct {
+	MaxLength   int     `env:"MAX_LENGTH"`   // characters of the input
+	MaxTokens   int     `env:"MAX_TOKENS"`   // numbers, names, operators and brackets
+	MaxDepth    int     `env:"MAX_DEPTH"`    // nesting of brackets, calls and lists
+	MaxTasks    int     `env:"MAX_TASKS"`    // tasks sent to workers for one example
+	MaxExponent float64 `env:"MAX_EXPONENT"` // absolute value of an exponent known before the calculation
+}
+
+// DefaultLimits - limits when nothing is configured
+var DefaultLimits = Limits{
+	MaxLength:   10000,
+	MaxTokens:   2000,
+	MaxDepth:    64,
+	MaxTasks:    1000,
+	MaxExponent: 100000,
+}
+
+// CheckInput - length, tokens and nesting, before the expression is converted
+func (l Limits) CheckInput(infix string) error {
+	if l.MaxLength > 0 && utf8.RuneCountInString(infix) > l.MaxLength {
+		return fmt.Errorf("%w: expression is longer than %d characters", ErrLimitExceeded, l.MaxLength)
+	}
+
+	tokens, err := tokenize(infix)
+	if err != nil {
+		return nil // syntax errors are reported by Convert
+	}
+	if l.MaxTokens > 0 && len(tokens) > l.MaxTokens {
+		return fmt.Errorf("%w: expression has more than %d tokens", ErrLimitExceeded, l.MaxTokens)
+	}
+
+	depth := 0
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenLParen, tokenLBracket:
+			depth++
+			if l.MaxDepth > 0 && depth > l.MaxDepth {
+				return fmt.Errorf("%w: brackets are nested deeper than %d", ErrLimitExceeded, l.MaxDepth)
+			}
+		case tokenRParen, tokenRBracket:
+			depth--
+		}
+	}
+	return nil
+}
+
+// CheckTasks - number of tasks and exponents of the planned expression.
+// An exponent is checked if it is a literal or computed from literals:
+// 9 ^ 9 ^ 9 has the exponent 9 ^ 9 = 387420489.
+func (l Limits) CheckTasks(tasks []*models.Task) error {
+	if l.MaxTasks > 0 && len(tasks) > l.MaxTasks {
+		return fmt.Errorf("%w: expression needs %d steps, at most %d are allowed", ErrLimitExceeded, len(tasks), l.MaxTasks)
+	}
+	if l.MaxExponent <= 0 {
+		return nil
+	}
+
+	byVariable := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byVariable[task.Variable] = task
+	}
+	for _, task := range tasks {
+		if task.Sign != "^" {
+			continue
+		}
+		if exponent, ok := constantOf(task.Num2, byVariable); ok && !(math.Abs(exponent) <= l.MaxExponent) {
+			return fmt.Errorf("%w: exponent is larger than %g", ErrLimitExceeded, l.MaxExponent)
+		}
+	}
+	return nil
+}
+
+// constantOf - value of an operand made of literals and arithmetic,
+// false if it depends on anything else
+func constantOf(ref string, byVariable map[string]*models.Task) (float64, bool) {
+	if value, ok := ParseLiteral(ref); ok {
+		if !value.IsNumber() && value.Kind != KindInt {
+			return 0, false
+		}
+		return toFloat(value), true
+	}
+	task, ok := byVariable[ref]
+	if !ok || IsFunction(task.Sign) {
+		return 0, false
+	}
+	a, okA := constantOf(task.Num1, byVariable)
+	b, okB := constantOf(task.Num2, byVariable)
+	if !okA || !okB {
+		return 0, false
+	}
+	result, err := NewNode(a, b, task.Sign).Calculate()
+	if err != nil {
+		return 0, false
+	}
+	return result, true
+}
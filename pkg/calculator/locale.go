@@ -0,0 +1,304 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrUnknownLocale - the locale is not one of Locales
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// Locale - how numbers are written: 3.14 and 1,000 or 3,14 and 1 000,
+// and the language of error messages
+type Locale struct {
+	Name      string
+	Decimal   rune   // decimal separator
+	Group     rune   // digit grouping in output, 0 - no grouping
+	Groups    []rune // digit grouping accepted in input
+	Separator rune   // separator of function arguments and list items
+	Language  string // "en" or "ru"
+}
+
+// Locales - supported locales. The zero Locale is the syntax of expressions
+// itself: a point, commas between arguments and no grouping.
+var Locales = map[string]Locale{
+	"en": {Name: "en", Decimal: '.', Group: ',', Separator: ',', Language: "en"},
+	"ru": {Name: "ru", Decimal: ',', Group: ' ', Groups: []rune{' ', ' ', ' '}, Separator: ';', Language: "ru"},
+	"de": {Name: "de", Decimal: ',', Group: '.', Groups: []rune{'.'}, Separator: ';', Language: "en"},
+	"fr": {Name: "fr", Decimal: ',', Group: ' ', Groups: []rune{' ', ' ', ' '}, Separator: ';', Language: "en"},
+}
+
+// LocaleByName - a locale by its name, the region is ignored: ru-RU is ru.
+// An empty name is the zero Locale.
+func LocaleByName(name string) (Locale, error) {
+	if name == "" {
+		return Locale{}, nil
+	}
+	language, _, _ := strings.Cut(strings.ReplaceAll(name, "_", "-"), "-")
+	locale, ok := Locales[strings.ToLower(language)]
+	if !ok {
+		return Locale{}, fmt.Errorf("%w %q", ErrUnknownLocale, name)
+	}
+	return locale, nil
+}
+
+func (l Locale) canonical() bool {
+	return l.Decimal == 0 || (l.Decimal == '.' && l.Separator == ',' && len(l.Groups) == 0)
+}
+
+func (l Locale) isGroup(ch rune) bool {
+	for _, group := range l.Groups {
+		if ch == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Canonical - the expression rewritten with a point as the decimal separator,
+// without digit grouping and with commas between arguments: in ru
+// "max(3,14; 1 000)" is "max(3.14, 1000)". Strings and names are not changed,
+// a separator outside brackets is left to separate statements of a script.
+// A grouping separator must be followed by exactly three digits. In RPN and
+// S-expressions a space separates operands, so it never groups digits there:
+// "100 200 +" stays two numbers.
+func (l Locale) Canonical(input string, syntax Syntax) (string, error) {
+	if l.canonical() {
+		return input, nil
+	}
+
+	runes := []rune(input)
+	depth := 0
+	var b strings.Builder
+	b.Grow(len(input))
+	digitsAt := func(i, n int) bool {
+		for j := i; j < i+n; j++ {
+			if j >= len(runes) || !unicode.IsDigit(runes[j]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case ch == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			end = min(end+1, len(runes))
+			b.WriteString(string(runes[i:end]))
+			i = end
+		case isIdentStart(ch):
+			start := i
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			b.WriteString(string(runes[start:i]))
+		case unicode.IsDigit(ch):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				b.WriteRune(runes[i])
+				i++
+			}
+			// 1 000 000: groups of exactly three digits
+			for i+1 < len(runes) && l.isGroup(runes[i]) && (syntax == SyntaxInfix || !unicode.IsSpace(runes[i])) {
+				if digitsAt(i+1, 3) && !digitsAt(i+4, 1) {
+					b.WriteString(string(runes[i+1 : i+4]))
+					i += 4
+					continue
+				}
+				if !unicode.IsSpace(runes[i]) {
+					return "", fmt.Errorf("%w: bad digit grouping at position %d", ErrCovertExample, start)
+				}
+				break // a space between two operands
+			}
+			if i < len(runes) && runes[i] == l.Decimal && digitsAt(i+1, 1) {
+				b.WriteByte('.')
+				i++
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					b.WriteRune(runes[i])
+					i++
+				}
+			}
+		case ch == l.Separator && depth > 0:
+			b.WriteByte(',')
+			i++
+		case ch == '(' || ch == '[':
+			depth++
+			b.WriteRune(ch)
+			i++
+		case ch == ')' || ch == ']':
+			depth--
+			b.WriteRune(ch)
+			i++
+		default:
+			b.WriteRune(ch)
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// Localize - reverse of Canonical without digit grouping: in ru
+// "max(3.14, 1000)" is "max(3,14; 1000)". Used to show parts of an
+// expression in the notation the user wrote it in.
+func (l Locale) Localize(expression string) string {
+	if l.canonical() {
+		return expression
+	}
+
+	runes := []rune(expression)
+	depth := 0
+	var b strings.Builder
+	b.Grow(len(expression))
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case ch == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			end = min(end+1, len(runes))
+			b.WriteString(string(runes[i:end]))
+			i = end
+		case isIdentStart(ch):
+			start := i
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+			b.WriteString(string(runes[start:i]))
+		case unicode.IsDigit(ch):
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				b.WriteRune(runes[i])
+				i++
+			}
+			if i+1 < len(runes) && runes[i] == '.' && unicode.IsDigit(runes[i+1]) {
+				b.WriteRune(l.Decimal)
+				i++
+			}
+		case ch == ',' && depth > 0:
+			b.WriteRune(l.Separator)
+			i++
+		case ch == '(' || ch == '[':
+			depth++
+			b.WriteRune(ch)
+			i++
+		case ch == ')' || ch == ']':
+			depth--
+			b.WriteRune(ch)
+			i++
+		default:
+			b.WriteRune(ch)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// number - a number written by Format in the locale: 1234567.5 is
+// "1 234 567,5" in ru. Exponents, fractions and Inf are kept as they are.
+func (l Locale) number(text string) string {
+	if l.Decimal == 0 {
+		return text
+	}
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	end := strings.IndexFunc(text, func(ch rune) bool { return ch < '0' || ch > '9' })
+	if end < 0 {
+		end = len(text)
+	}
+	integer, rest := text[:end], text[end:]
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && l.Group != 0 && len(integer) > 4 && (len(integer)-i)%3 == 0 {
+			b.WriteRune(l.Group)
+		}
+		b.WriteRune(digit)
+	}
+	if strings.HasPrefix(rest, ".") {
+		b.WriteRune(l.Decimal)
+		rest = rest[1:]
+	}
+	b.WriteString(rest)
+	return b.String()
+}
+
+// separator - between items of lists in output
+func (l Locale) separator() string {
+	if l.Separator == 0 {
+		return ", "
+	}
+	return string(l.Separator) + " "
+}
+
+// translation - a part of an English error message and its translation,
+// $1.. are the submatches of the pattern
+type translation struct {
+	pattern *regexp.Regexp
+	text    string
+}
+
+// russian - errors of the parser, of limits and of calculations.
+// Details that are not listed stay in English.
+var russian = []translation{
+	{regexp.MustCompile(`line is not a mathematical expression or contains an error`), "строка не является математическим выражением или содержит ошибку"},
+	{regexp.MustCompile(`bad number at position (\d+)`), "неверное число в позиции $1"},
+	{regexp.MustCompile(`bad digit grouping at position (\d+)`), "неверная группировка цифр в позиции $1"},
+	{regexp.MustCompile(`unclosed string at position (\d+)`), "незакрытая строка в позиции $1"},
+	{regexp.MustCompile(`unexpected symbol (.+) at position (\d+)`), "неожиданный символ $1 в позиции $2"},
+	{regexp.MustCompile(`extra closing bracket at position (\d+)`), "лишняя закрывающая скобка в позиции $1"},
+	{regexp.MustCompile(`unexpected (".*") at position (\d+)`), "неожиданный $1 в позиции $2"},
+	{regexp.MustCompile(`unexpected end of expression`), "неожиданный конец выражения"},
+	{regexp.MustCompile(`unbalanced brackets`), "несбалансированные скобки"},
+	{regexp.MustCompile(`limit exceeded`), "превышен лимит"},
+	{regexp.MustCompile(`expression is longer than (\d+) characters`), "выражение длиннее $1 символов"},
+	{regexp.MustCompile(`expression has more than (\d+) tokens`), "в выражении больше $1 лексем"},
+	{regexp.MustCompile(`brackets are nested deeper than (\d+)`), "скобки вложены глубже $1 уровней"},
+	{regexp.MustCompile(`expression needs (\d+) steps, at most (\d+) are allowed`), "выражению нужно $1 шагов, разрешено не больше $2"},
+	{regexp.MustCompile(`exponent is larger than (\S+)`), "показатель степени больше $1"},
+	{regexp.MustCompile(`division by zero`), "деление на ноль"},
+	{regexp.MustCompile(`operation does not exist or not implemented`), "операция не существует или не реализована"},
+	{regexp.MustCompile(`unknown variable`), "неизвестная переменная"},
+	{regexp.MustCompile(`unknown reference`), "неизвестная ссылка"},
+	{regexp.MustCompile(`unknown function`), "неизвестная функция"},
+	{regexp.MustCompile(`unknown currency`), "неизвестная валюта"},
+	{regexp.MustCompile(`unknown locale`), "неизвестная локаль"},
+	{regexp.MustCompile(`invalid argument`), "недопустимый аргумент"},
+	{regexp.MustCompile(`dimension mismatch`), "размерности не совпадают"},
+	{regexp.MustCompile(`matrix is singular`), "матрица вырождена"},
+	{regexp.MustCompile(`currency mismatch`), "валюты не совпадают"},
+	{regexp.MustCompile(`overflow: result is too large`), "переполнение: результат слишком велик"},
+	{regexp.MustCompile(`domain error: result is not a number`), "ошибка области определения: результат не число"},
+	{regexp.MustCompile(`custom operation failed`), "ошибка пользовательской операции"},
+}
+
+// translations - tables by language, English is not translated
+var translations = map[string][]translation{
+	"ru": russian,
+}
+
+// AddTranslation - translation of errors the calculator does not know,
+// such as errors of the service. Call it in init, before Translate is used.
+func AddTranslation(language, pattern, text string) {
+	translations[language] = append(translations[language], translation{regexp.MustCompile(pattern), text})
+}
+
+// Translate - an error message in the language of the locale.
+// Messages are English, so other languages are translated by parts.
+func (l Locale) Translate(message string) string {
+	for _, t := range translations[l.Language] {
+		message = t.pattern.ReplaceAllString(message, t.text)
+	}
+	return message
+}
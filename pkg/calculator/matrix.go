@@ -0,0 +1,415 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+)
+
+// MatrixBlockRows - how many rows of the left matrix one worker multiplies.
+// Bigger literal matrices are split into row blocks.
+var MatrixBlockRows = 32
+
+func MatrixValue(rows [][]float64) Value {
+	return Value{Kind: KindMatrix, Matrix: rows}
+}
+
+// shape of a matrix: rows x columns
+func shape(m [][]float64) (int, int) {
+	if len(m) == 0 {
+		return 0, 0
+	}
+	return len(m), len(m[0])
+}
+
+// listOf - [a, b, c]: a list of numbers, or a matrix if every item
+// is a list of numbers of the same length (a row)
+func listOf(args []Value) (Value, error) {
+	if len(args) == 0 || args[0].Kind != KindList {
+		return ListValue(args), nil
+	}
+
+	rows := make([][]float64, 0, len(args))
+	for _, arg := range args {
+		if arg.Kind != KindList {
+			return Value{}, fmt.Errorf("%w: matrix rows must be lists", ErrDimensionMismatch)
+		}
+		row, err := numbers(arg.List)
+		if err != nil {
+			return Value{}, err
+		}
+		if len(row) != len(arg.List) {
+			return Value{}, fmt.Errorf("%w: matrix rows must contain numbers", ErrInvalidArgument)
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return Value{}, fmt.Errorf("%w: matrix rows have different length: %d and %d", ErrDimensionMismatch, len(rows[0]), len(row))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows[0]) == 0 {
+		return Value{}, fmt.Errorf("%w: matrix rows are empty", ErrDimensionMismatch)
+	}
+	return MatrixValue(rows), nil
+}
+
+// vector - numbers of a list, nil if the list contains something else
+func vector(v Value) []float64 {
+	items, err := numbers(v.List)
+	if err != nil || len(items) != len(v.List) {
+		return nil
+	}
+	return items
+}
+
+// applyOperator - + - * / ^ for matrices. A list of numbers can be
+// multiplied by a matrix as a vector. Numbers are broadcast over every
+// element of a matrix, so ~M is 0 - M.
+func applyOperator(sign string, a, b Value) (Value, error) {
+	switch {
+	case a.Kind == KindMoney || b.Kind == KindMoney:
+		return moneyOperator(sign, a, b)
+	case isTemporal(a) || isTemporal(b) || sign == "in":
+		return timeOperator(sign, a, b)
+	case a.Kind == KindMatrix && b.Kind == KindMatrix:
+		return matrixByMatrix(sign, a.Matrix, b.Matrix)
+	case a.Kind == KindMatrix && b.Kind == KindList:
+		if sign != "*" {
+			break
+		}
+		column := vector(b)
+		if column == nil {
+			break
+		}
+		product, err := multiply(a.Matrix, columnOf(column))
+		if err != nil {
+			return Value{}, err
+		}
+		return NumbersValue(flatten(product)), nil
+	case a.Kind == KindList && b.Kind == KindMatrix:
+		if sign != "*" {
+			break
+		}
+		row := vector(a)
+		if row == nil {
+			break
+		}
+		product, err := multiply([][]float64{row}, b.Matrix)
+		if err != nil {
+			return Value{}, err
+		}
+		return NumbersValue(product[0]), nil
+	case a.Kind == KindMatrix && b.IsNumber():
+		if sign == "^" {
+			return matrixPower(a.Matrix, b.Number)
+		}
+		return elementwise(sign, a.Matrix, [][]float64{{b.Number}}, true)
+	case a.IsNumber() && b.Kind == KindMatrix:
+		if sign == "/" || sign == "^" {
+			break
+		}
+		return elementwise(sign, [][]float64{{a.Number}}, b.Matrix, true)
+	}
+	return Value{}, fmt.Errorf("%w: operator %s is not defined for %s and %s", ErrInvalidArgument, sign, kindOf(a), kindOf(b))
+}
+
+func kindOf(v Value) Kind {
+	if v.IsNumber() {
+		return KindNumber
+	}
+	return v.Kind
+}
+
+func matrixByMatrix(sign string, a, b [][]float64) (Value, error) {
+	switch sign {
+	case "*":
+		product, err := multiply(a, b)
+		if err != nil {
+			return Value{}, err
+		}
+		return MatrixValue(product), nil
+	case "+", "-":
+		return elementwise(sign, a, b, false)
+	}
+	return Value{}, fmt.Errorf("%w: operator %s is not defined for matrices", ErrInvalidArgument, sign)
+}
+
+// elementwise applies a scalar operator to every pair of elements.
+// With broadcast a 1x1 operand is applied to every element of the other one.
+func elementwise(sign string, a, b [][]float64, broadcast bool) (Value, error) {
+	rowsA, colsA := shape(a)
+	rowsB, colsB := shape(b)
+	scalarA := broadcast && rowsA == 1 && colsA == 1
+	scalarB := broadcast && rowsB == 1 && colsB == 1
+	if !scalarA && !scalarB && (rowsA != rowsB || colsA != colsB) {
+		return Value{}, fmt.Errorf("%w: %dx%d and %dx%d", ErrDimensionMismatch, rowsA, colsA, rowsB, colsB)
+	}
+
+	rows, cols := rowsA, colsA
+	if scalarA {
+		rows, cols = rowsB, colsB
+	}
+	result := make([][]float64, rows)
+	for i := range result {
+		result[i] = make([]float64, cols)
+		for j := range result[i] {
+			x, y := a[0][0], b[0][0]
+			if !scalarA {
+				x = a[i][j]
+			}
+			if !scalarB {
+				y = b[i][j]
+			}
+			value, err := NewNode(x, y, sign).Calculate()
+			if err != nil {
+				return Value{}, err
+			}
+			result[i][j] = value
+		}
+	}
+	return MatrixValue(result), nil
+}
+
+func multiply(a, b [][]float64) ([][]float64, error) {
+	rowsA, colsA := shape(a)
+	rowsB, colsB := shape(b)
+	if colsA != rowsB {
+		return nil, fmt.Errorf("%w: can't multiply %dx%d by %dx%d", ErrDimensionMismatch, rowsA, colsA, rowsB, colsB)
+	}
+	result := make([][]float64, rowsA)
+	for i := range result {
+		result[i] = make([]float64, colsB)
+		for k := 0; k < colsA; k++ {
+			for j := 0; j < colsB; j++ {
+				result[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return result, nil
+}
+
+// matrixPower - M ^ n for a square matrix and an integer n,
+// negative n uses the inverse matrix
+func matrixPower(m [][]float64, n float64) (Value, error) {
+	rows, cols := shape(m)
+	if rows != cols {
+		return Value{}, fmt.Errorf("%w: power of a %dx%d matrix", ErrDimensionMismatch, rows, cols)
+	}
+	if n != math.Trunc(n) {
+		return Value{}, fmt.Errorf("%w: matrix power must be an integer", ErrInvalidArgument)
+	}
+	if n < 0 {
+		inverse, err := invert(m)
+		if err != nil {
+			return Value{}, err
+		}
+		m, n = inverse, -n
+	}
+
+	// exponentiation by squaring
+	result := identity(rows)
+	for ; n > 0; n = math.Floor(n / 2) {
+		if math.Mod(n, 2) == 1 {
+			result, _ = multiply(result, m)
+		}
+		m, _ = multiply(m, m)
+	}
+	return MatrixValue(result), nil
+}
+
+func identity(n int) [][]float64 {
+	result := make([][]float64, n)
+	for i := range result {
+		result[i] = make([]float64, n)
+		result[i][i] = 1
+	}
+	return result
+}
+
+func columnOf(items []float64) [][]float64 {
+	column := make([][]float64, len(items))
+	for i, item := range items {
+		column[i] = []float64{item}
+	}
+	return column
+}
+
+func flatten(m [][]float64) []float64 {
+	result := make([]float64, 0)
+	for _, row := range m {
+		result = append(result, row...)
+	}
+	return result
+}
+
+func copyMatrix(m [][]float64) [][]float64 {
+	result := make([][]float64, len(m))
+	for i, row := range m {
+		result[i] = append([]float64{}, row...)
+	}
+	return result
+}
+
+// matrixArg - the only argument of det, inv, transpose
+func matrixArg(name string, args []Value) ([][]float64, error) {
+	switch args[0].Kind {
+	case KindMatrix:
+		return args[0].Matrix, nil
+	case KindList:
+		if row := vector(args[0]); len(row) > 0 {
+			return [][]float64{row}, nil // a vector is a 1xN matrix
+		}
+	}
+	return nil, fmt.Errorf("%w: %s expects a matrix", ErrInvalidArgument, name)
+}
+
+func squareArg(name string, args []Value) ([][]float64, error) {
+	m, err := matrixArg(name, args)
+	if err != nil {
+		return nil, err
+	}
+	if rows, cols := shape(m); rows != cols {
+		return nil, fmt.Errorf("%w: %s of a %dx%d matrix", ErrDimensionMismatch, name, rows, cols)
+	}
+	return m, nil
+}
+
+// determinant - Gaussian elimination with partial pivoting
+func determinant(args []Value) (Value, error) {
+	m, err := squareArg("det", args)
+	if err != nil {
+		return Value{}, err
+	}
+	a := copyMatrix(m)
+	n := len(a)
+	det := 1.0
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if a[pivot][col] == 0 {
+			return NumberValue(0), nil
+		}
+		if pivot != col {
+			a[pivot], a[col] = a[col], a[pivot]
+			det = -det
+		}
+		det *= a[col][col]
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+		}
+	}
+	return NumberValue(det), nil
+}
+
+func inverse(args []Value) (Value, error) {
+	m, err := squareArg("inv", args)
+	if err != nil {
+		return Value{}, err
+	}
+	result, err := invert(m)
+	if err != nil {
+		return Value{}, err
+	}
+	return MatrixValue(result), nil
+}
+
+// invert - Gauss-Jordan elimination with partial pivoting
+func invert(m [][]float64) ([][]float64, error) {
+	a := copyMatrix(m)
+	n := len(a)
+	result := identity(n)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, ErrSingularMatrix
+		}
+		a[pivot], a[col] = a[col], a[pivot]
+		result[pivot], result[col] = result[col], result[pivot]
+
+		scale := a[col][col]
+		for k := 0; k < n; k++ {
+			a[col][k] /= scale
+			result[col][k] /= scale
+		}
+		for row := 0; row < n; row++ {
+			if row == col {
+				continue
+			}
+			factor := a[row][col]
+			for k := 0; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+				result[row][k] -= factor * result[col][k]
+			}
+		}
+	}
+	return result, nil
+}
+
+func transpose(args []Value) (Value, error) {
+	m, err := matrixArg("transpose", args)
+	if err != nil {
+		return Value{}, err
+	}
+	rows, cols := shape(m)
+	result := make([][]float64, cols)
+	for j := range result {
+		result[j] = make([]float64, rows)
+		for i := range m {
+			result[j][i] = m[i][j]
+		}
+	}
+	return MatrixValue(result), nil
+}
+
+// dot - scalar product of two vectors
+func dot(args []Value) (Value, error) {
+	a, b := vector(args[0]), vector(args[1])
+	if args[0].Kind != KindList || args[1].Kind != KindList || a == nil || b == nil {
+		return Value{}, fmt.Errorf("%w: dot expects two lists of numbers", ErrInvalidArgument)
+	}
+	if len(a) != len(b) {
+		return Value{}, fmt.Errorf("%w: vectors of length %d and %d", ErrDimensionMismatch, len(a), len(b))
+	}
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return NumberValue(sum), nil
+}
+
+// vstack - joins row blocks of a matrix product back together,
+// blocks of a matrix by vector product are lists
+func vstack(args []Value) (Value, error) {
+	if args[0].Kind == KindList {
+		items := make([]Value, 0)
+		for _, arg := range args {
+			if arg.Kind != KindList {
+				return Value{}, fmt.Errorf("%w: blocks have different kinds", ErrInvalidArgument)
+			}
+			items = append(items, arg.List...)
+		}
+		return ListValue(items), nil
+	}
+
+	rows := make([][]float64, 0)
+	for _, arg := range args {
+		if arg.Kind != KindMatrix {
+			return Value{}, fmt.Errorf("%w: only matrices can be stacked", ErrInvalidArgument)
+		}
+		if len(rows) > 0 && len(arg.Matrix[0]) != len(rows[0]) {
+			return Value{}, fmt.Errorf("%w: blocks have different width", ErrDimensionMismatch)
+		}
+		rows = append(rows, arg.Matrix...)
+	}
+	return MatrixValue(rows), nil
+}
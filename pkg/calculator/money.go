@@ -0,0 +1,107 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func MoneyValue(amount float64, currency string) Value {
+	return Value{Kind: KindMoney, Number: amount, Currency: currency}
+}
+
+// isCurrency - ISO 4217 style code: three capital letters, USD, EUR
+func isCurrency(name string) bool {
+	if len(name) != 3 {
+		return false
+	}
+	for _, ch := range name {
+		if ch < 'A' || ch > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatMoney - 100.00 USD
+func formatMoney(amount float64, currency string) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64) + " " + currency
+}
+
+// money - money(100, "USD"), also written as 100 USD
+func money(args []Value) (Value, error) {
+	if !args[0].IsNumber() || args[1].Kind != KindString || !isCurrency(args[1].Text) {
+		return Value{}, fmt.Errorf("%w: money expects an amount and a currency code", ErrInvalidArgument)
+	}
+	return MoneyValue(args[0].Number, args[1].Text), nil
+}
+
+// convertMoney - "amount in EUR". Rates are units of each currency
+// for one unit of the base currency of the snapshot.
+func convertMoney(amount, target Value, rates map[string]float64) (Value, error) {
+	if target.Kind != KindString || !isCurrency(target.Text) {
+		return Value{}, fmt.Errorf("%w: money can only be converted to a currency, got %s", ErrInvalidArgument, target)
+	}
+	from, ok := rates[amount.Currency]
+	if !ok || from <= 0 {
+		return Value{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, amount.Currency)
+	}
+	to, ok := rates[target.Text]
+	if !ok || to <= 0 {
+		return Value{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, target.Text)
+	}
+	return MoneyValue(amount.Number/from*to, target.Text), nil
+}
+
+// moneyOperator - money ± money and money / money of the same currency,
+// money * number, number * money, money / number
+func moneyOperator(sign string, a, b Value) (Value, error) {
+	if a.Kind == KindMoney && b.Kind == KindMoney {
+		if a.Currency != b.Currency {
+			return Value{}, fmt.Errorf("%w: %s and %s, convert with \"in\" first", ErrCurrencyMismatch, a.Currency, b.Currency)
+		}
+		switch sign {
+		case "+", "-":
+			result, err := NewNode(a.Number, b.Number, sign).Calculate()
+			return MoneyValue(result, a.Currency), err
+		case "/":
+			result, err := NewNode(a.Number, b.Number, sign).Calculate()
+			return NumberValue(result), err
+		}
+	}
+
+	switch {
+	case a.Kind == KindMoney && b.IsNumber() && (sign == "*" || sign == "/"):
+		result, err := NewNode(a.Number, b.Number, sign).Calculate()
+		return MoneyValue(result, a.Currency), err
+	case a.IsNumber() && b.Kind == KindMoney && sign == "*":
+		return MoneyValue(a.Number*b.Number, b.Currency), nil
+	case a.IsNumber() && a.Number == 0 && b.Kind == KindMoney && sign == "-":
+		return MoneyValue(-b.Number, b.Currency), nil // ~x
+	}
+	return Value{}, fmt.Errorf("%w: operator %s is not defined for %s and %s", ErrInvalidArgument, sign, kindOf(a), kindOf(b))
+}
+
+// Currencies returns the currency codes used in money literals and
+// conversions, sorted. Convert must be called first.
+func (s *Expression) Currencies() []string {
+	seen := make(map[string]bool)
+	items := strings.Fields(s.Postfix)
+	for i, item := range items {
+		// 100 "USD" money/2, x "EUR" in
+		if i == 0 || (item != functionToken("money", 2) && item != "in") {
+			continue
+		}
+		if code, err := strconv.Unquote(items[i-1]); err == nil && isCurrency(code) {
+			seen[code] = true
+		}
+	}
+
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
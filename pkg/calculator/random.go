@@ -0,0 +1,58 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// TaskSeed - seed of one task derived from the seed of the example and
+// the index of the task, so a retried or repeated task draws the same numbers
+// on any worker
+func TaskSeed(seed int64, index int) uint64 {
+	return splitMix64(splitMix64(uint64(seed)) ^ uint64(index))
+}
+
+// splitMix64 - a good 64-bit mixer, neighbour inputs give unrelated outputs
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, splitMix64(seed)))
+}
+
+// uniform - rand(), a number from [0, 1)
+func uniform(rng *rand.Rand, args []Value) (Value, error) {
+	return NumberValue(rng.Float64()), nil
+}
+
+// randomInt - randint(a, b), an integer from a to b inclusive
+func randomInt(rng *rand.Rand, args []Value) (Value, error) {
+	if !args[0].IsNumber() || !args[1].IsNumber() {
+		return Value{}, fmt.Errorf("%w: randint expects two numbers", ErrInvalidArgument)
+	}
+	a, b := args[0].Number, args[1].Number
+	if a != math.Trunc(a) || b != math.Trunc(b) || math.Abs(a) > maxExactFloat || math.Abs(b) > maxExactFloat {
+		return Value{}, fmt.Errorf("%w: randint expects integers, got %s and %s", ErrInvalidArgument, args[0], args[1])
+	}
+	if a > b {
+		return Value{}, fmt.Errorf("%w: randint range is empty: %s > %s", ErrInvalidArgument, args[0], args[1])
+	}
+	return NumberValue(a + float64(rng.Int64N(int64(b-a)+1))), nil
+}
+
+// normal - normal(mu, sigma), a normally distributed number
+func normal(rng *rand.Rand, args []Value) (Value, error) {
+	if !args[0].IsNumber() || !args[1].IsNumber() {
+		return Value{}, fmt.Errorf("%w: normal expects two numbers", ErrInvalidArgument)
+	}
+	mu, sigma := args[0].Number, args[1].Number
+	if sigma < 0 {
+		return Value{}, fmt.Errorf("%w: normal sigma must not be negative, got %s", ErrInvalidArgument, args[1])
+	}
+	return NumberValue(mu + sigma*rng.NormFloat64()), nil
+}
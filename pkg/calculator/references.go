@@ -0,0 +1,54 @@
+package calculator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LastResult - the result of the previous example of the user
+	LastResult = "ans"
+	// lastReference - the same as ans
+	lastReference = "@last"
+	// resultFunction - result("<id>"), the result of an example by its id
+	resultFunction = "result"
+)
+
+// IsReference - ans, @last or @<id>, which result("<id>") is converted to
+func IsReference(name string) bool {
+	return name == LastResult || (strings.HasPrefix(name, "@") && isIdentifier(name))
+}
+
+// ReferenceID - the id of the example a reference points to,
+// empty for the previous example of the user
+func ReferenceID(name string) string {
+	if name == LastResult || name == lastReference {
+		return ""
+	}
+	return strings.TrimPrefix(name, "@")
+}
+
+// resultCalls - result("<id>") becomes the reference @<id>
+func resultCalls(list []string) ([]string, error) {
+	converted := make([]string, 0, len(list))
+	for _, item := range list {
+		name, arity, ok := parseFunctionToken(item)
+		if !ok || name != resultFunction {
+			converted = append(converted, item)
+			continue
+		}
+
+		// the argument is the item right before the call
+		last := len(converted) - 1
+		var id string
+		if arity == 1 && last >= 0 && strings.HasPrefix(converted[last], `"`) {
+			id, _ = strconv.Unquote(converted[last])
+		}
+		if id == "" || !isIdentifier("@"+id) {
+			return nil, fmt.Errorf("%w: %s() takes the id of an example as a string", ErrCovertExample, resultFunction)
+		}
+		converted[last] = "@" + id
+	}
+	return converted, nil
+}
@@ -0,0 +1,222 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tainj/distributed_calculator2/internal/models"
+)
+
+// Statement - one statement of a script: name = expression, or an expression
+type Statement struct {
+	Name       string // empty if nothing is assigned
+	Expression string
+}
+
+// SplitScript - statements of "r = 3; area = pi * r ^ 2; area * 2".
+// Statements are separated by ";" or new lines outside brackets and strings,
+// empty statements are skipped.
+func SplitScript(input string) ([]Statement, error) {
+	statements := make([]Statement, 0, 1)
+	add := func(text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+		statement, err := parseStatement(text)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, statement)
+		return nil
+	}
+
+	depth, start, quoted := 0, 0, false
+	for i, ch := range input {
+		switch {
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+		case ch == '(' || ch == '[':
+			depth++
+		case ch == ')' || ch == ']':
+			depth--
+		case depth <= 0 && (ch == ';' || ch == '\n'):
+			if err := add(input[start:i]); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+	}
+	if err := add(input[start:]); err != nil {
+		return nil, err
+	}
+
+	if len(statements) == 0 {
+		return nil, ErrCovertExample
+	}
+	return statements, nil
+}
+
+// IsScript - more than one statement or an assignment
+func IsScript(input string) bool {
+	statements, err := SplitScript(input)
+	return err == nil && (len(statements) > 1 || statements[0].Name != "")
+}
+
+// parseStatement - "name = expression" or just an expression
+func parseStatement(text string) (Statement, error) {
+	end := strings.IndexFunc(text, func(ch rune) bool { return !isIdentPart(ch) })
+	if end <= 0 {
+		return Statement{Expression: text}, nil
+	}
+	rest := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+	if !strings.HasPrefix(rest, "=") {
+		return Statement{Expression: text}, nil
+	}
+
+	name := text[:end]
+	if err := CheckName(name); err != nil {
+		return Statement{}, fmt.Errorf("%w: %w", ErrCovertExample, err)
+	}
+	return Statement{Name: name, Expression: strings.TrimSpace(rest[1:])}, nil
+}
+
+// CheckName - a name can be given to a value: not a function, a unit,
+// a currency, "in", a reference or a constant
+func CheckName(name string) error {
+	if !isIdentifier(name) || IsFunction(name) || IsUnit(name) || isCurrency(name) || name == "in" ||
+		IsReference(name) || name == resultFunction {
+		return fmt.Errorf("%q can't be assigned", name)
+	}
+	if _, ok := Constants[name]; ok {
+		return fmt.Errorf("%q is a constant", name)
+	}
+	return nil
+}
+
+// Plan - tasks of a whole script. Names are bound to the refs of their values:
+// a variable of a task, whose result workers keep in Redis as result:<ref>,
+// or a literal, so later statements use earlier results without new tasks.
+type Plan struct {
+	Tasks       []*models.Task
+	Variable    string            // the answer: the value of the last statement
+	Bindings    []models.Binding  // assigned names in the order of the first assignment
+	Identifiers []string          // names that are not assigned before they are used and have no value
+	Inputs      map[string]string // refs of the names that are not assigned by the script
+	Functions   []string          // unknown functions
+	Expanded    []string          // user functions inlined into the tasks
+	Currencies  []string          // currencies of money and conversions
+}
+
+// Scope - what a script may use besides its own assignments
+type Scope struct {
+	Values    map[string]string      // refs of names: literals or variables of results
+	Functions map[string]FunctionDef // functions defined by the user
+}
+
+// PlanScript - tasks of all statements in order. A plain expression is
+// a script of one statement. Names that the script uses before assigning
+// them are bound to values, if there are any: literals, like saved variables,
+// or refs of results, like references to other examples. Calls of user
+// functions are inlined.
+func PlanScript(input string, syntax Syntax, scope Scope) (*Plan, error) {
+	statements, err := SplitScript(input)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Identifiers: []string{}, Functions: []string{}, Inputs: map[string]string{}}
+	refs := make(map[string]string)
+	seen := make(map[string]bool)
+	currencies := make(map[string]bool)
+	expanded := make(map[string]bool)
+
+	for i, statement := range statements {
+		expr := &Expression{Infix: statement.Expression, Syntax: syntax}
+		if _, err := expr.Convert(); err != nil {
+			if len(statements) > 1 {
+				return nil, fmt.Errorf("statement %d: %w", i+1, err)
+			}
+			return nil, err
+		}
+		expr, names, err := expr.Expand(scope.Functions)
+		if err != nil {
+			if len(statements) > 1 {
+				return nil, fmt.Errorf("statement %d: %w", i+1, err)
+			}
+			return nil, err
+		}
+		for _, name := range names {
+			expanded[name] = true
+		}
+		expr = expr.Bind(refs)
+
+		for _, name := range expr.Identifiers() {
+			if value, ok := scope.Values[name]; ok {
+				plan.Inputs[name] = value
+				continue
+			}
+			if !seen["variable "+name] {
+				seen["variable "+name] = true
+				plan.Identifiers = append(plan.Identifiers, name)
+			}
+		}
+		for _, name := range expr.UnknownFunctions() {
+			if !seen["function "+name] {
+				seen["function "+name] = true
+				plan.Functions = append(plan.Functions, name)
+			}
+		}
+		for _, code := range expr.Currencies() {
+			currencies[code] = true
+		}
+		expr = expr.Bind(plan.Inputs)
+
+		tasks, variable := expr.Calculate()
+		plan.Tasks = append(plan.Tasks, tasks...)
+		plan.Variable = variable
+
+		if statement.Name == "" {
+			continue
+		}
+		if _, ok := refs[statement.Name]; !ok {
+			plan.Bindings = append(plan.Bindings, models.Binding{Name: statement.Name})
+		}
+		refs[statement.Name] = variable
+	}
+
+	for i, binding := range plan.Bindings {
+		plan.Bindings[i].Ref = refs[binding.Name] // the last assignment
+	}
+	for code := range currencies {
+		plan.Currencies = append(plan.Currencies, code)
+	}
+	sort.Strings(plan.Currencies)
+	for name := range expanded {
+		plan.Expanded = append(plan.Expanded, name)
+	}
+	sort.Strings(plan.Expanded)
+	return plan, nil
+}
+
+// BoundValue - the value of a name assigned by a script, nil if it is not
+// calculated yet
+type BoundValue struct {
+	Name  string
+	Value *Value
+}
+
+// Bind returns a copy of the expression where variables are replaced by
+// refs: literals or variables of tasks planned before
+func (s *Expression) Bind(refs map[string]string) *Expression {
+	items := strings.Fields(s.Postfix)
+	for i, item := range items {
+		if ref, ok := refs[item]; ok && isIdentifier(item) {
+			items[i] = ref
+		}
+	}
+	return &Expression{Infix: s.Infix, Postfix: strings.Join(items, " "), Syntax: s.Syntax}
+}
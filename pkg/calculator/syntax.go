@@ -0,0 +1,307 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrUnknownSyntax - the syntax is not one of the Syntax constants
+var ErrUnknownSyntax = errors.New("unknown syntax")
+
+// Syntax - how an expression is written
+type Syntax string
+
+const (
+	SyntaxInfix Syntax = "infix" // (3 + 4) * 2
+	SyntaxRPN   Syntax = "rpn"   // 3 4 + 2 *
+	SyntaxSexpr Syntax = "sexpr" // (* (+ 3 4) 2)
+)
+
+// ParseSyntax - a syntax by its name, infix if the name is empty
+func ParseSyntax(name string) (Syntax, error) {
+	switch syntax := Syntax(name); syntax {
+	case "":
+		return SyntaxInfix, nil
+	case SyntaxInfix, SyntaxRPN, SyntaxSexpr:
+		return syntax, nil
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownSyntax, name)
+}
+
+// rpnToPostfix validates an expression in reverse Polish notation and
+// converts it to the postfix items of toPostfix. Functions with a fixed
+// number of arguments are written bare (7 isprime), others with the number
+// of arguments (1 2 3 mean(3)); lists are [1 2 3], units and currencies
+// follow the number (90 days, 100 USD), the target of "in" is a string
+// (d "hours" in).
+func rpnToPostfix(input string) ([]string, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrCovertExample
+	}
+
+	list := make([]string, 0, len(tokens))
+	depth := 0              // values on the stack
+	marks := make([]int, 0) // depth at each open "["
+
+	// apply - an item that takes n values and leaves one
+	apply := func(item string, n int, tok token) error {
+		bottom := 0
+		if len(marks) > 0 {
+			bottom = marks[len(marks)-1]
+		}
+		if depth-bottom < n {
+			return fmt.Errorf("%w: %q needs %d operands at position %d", ErrCovertExample, tok.text, n, tok.pos)
+		}
+		list = append(list, item)
+		depth = depth - n + 1
+		return nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.kind == tokenNumber:
+			list = append(list, tok.text)
+			depth++
+		case tok.kind == tokenString:
+			list = append(list, quoteLiteral(tok.text))
+			depth++
+		case tok.kind == tokenOperator && tok.text == "~":
+			err = apply(tok.text, 1, tok)
+		case tok.kind == tokenOperator:
+			err = apply(tok.text, 2, tok)
+		case tok.kind == tokenIdent && tok.text == "in":
+			err = apply(tok.text, 2, tok)
+		case tok.kind == tokenIdent && IsUnit(tok.text):
+			err = apply(functionToken(tok.text, 1), 1, tok)
+		case tok.kind == tokenIdent && isCurrency(tok.text):
+			// 100 USD → 100 "USD" money/2
+			if err = apply(quoteLiteral(tok.text), 1, tok); err == nil {
+				list = append(list, functionToken("money", 2))
+			}
+		case tok.kind == tokenIdent && i+3 < len(tokens) && tokens[i+1].kind == tokenLParen &&
+			tokens[i+2].kind == tokenNumber && tokens[i+3].kind == tokenRParen:
+			// mean(3) - a function and the number of its arguments
+			arity, errArity := strconv.Atoi(tokens[i+2].text)
+			if errArity != nil {
+				return nil, fmt.Errorf("%w: bad number of arguments at position %d", ErrCovertExample, tokens[i+2].pos)
+			}
+			if err := checkCall(tok.text, arity); err != nil {
+				return nil, err
+			}
+			err = apply(functionToken(tok.text, arity), arity, tok)
+			i += 3
+		case tok.kind == tokenIdent && IsFunction(tok.text):
+			fn := functions[tok.text]
+			if fn.internal {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownFunction, tok.text)
+			}
+			if fn.minArgs != fn.maxArgs {
+				return nil, fmt.Errorf("%w: %s needs the number of arguments at position %d: %s(%d)", ErrCovertExample, tok.text, tok.pos, tok.text, max(fn.minArgs, 1))
+			}
+			err = apply(functionToken(tok.text, fn.minArgs), fn.minArgs, tok)
+		case tok.kind == tokenIdent:
+			list = append(list, tok.text) // variable
+			depth++
+		case tok.kind == tokenLBracket:
+			marks = append(marks, depth)
+		case tok.kind == tokenRBracket:
+			if len(marks) == 0 {
+				return nil, fmt.Errorf("%w: unexpected \"]\" at position %d", ErrCovertExample, tok.pos)
+			}
+			bottom := marks[len(marks)-1]
+			marks = marks[:len(marks)-1]
+			list = append(list, functionToken("list", depth-bottom))
+			depth = bottom + 1
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrCovertExample, tok.text, tok.pos)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case len(marks) > 0:
+		return nil, fmt.Errorf("%w: unbalanced brackets", ErrCovertExample)
+	case depth != 1:
+		return nil, fmt.Errorf("%w: %d values are left instead of one", ErrCovertExample, depth)
+	}
+	return list, nil
+}
+
+// sexprToPostfix validates an S-expression and converts it to the postfix
+// items of toPostfix: (* (+ 3 4) 2). Operators + - * / take two or more
+// arguments and are applied from the left, (- x) is ~x, ^ and "in" take
+// exactly two; (f a b) calls functions and units, [a b] is a list.
+func sexprToPostfix(input string) ([]string, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrCovertExample
+	}
+
+	p := &sexprParser{tokens: tokens}
+	if err := p.expr(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrCovertExample, tok.text, tok.pos)
+	}
+	return p.list, nil
+}
+
+type sexprParser struct {
+	tokens []token
+	pos    int
+	list   []string
+}
+
+// expr - one atom, (head args...) or [items...]
+func (p *sexprParser) expr() error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("%w: unexpected end of expression", ErrCovertExample)
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case tokenNumber, tokenIdent:
+		if tok.text == "in" {
+			break
+		}
+		p.list = append(p.list, tok.text)
+		return nil
+	case tokenString:
+		p.list = append(p.list, quoteLiteral(tok.text))
+		return nil
+	case tokenLBracket:
+		count, err := p.args(tokenRBracket)
+		if err != nil {
+			return err
+		}
+		p.list = append(p.list, functionToken("list", count))
+		return nil
+	case tokenLParen:
+		return p.form(tok)
+	}
+	return fmt.Errorf("%w: unexpected %q at position %d", ErrCovertExample, tok.text, tok.pos)
+}
+
+// args - expressions up to the closing bracket, which is consumed
+func (p *sexprParser) args(closing tokenKind) (int, error) {
+	count := 0
+	for {
+		if p.pos >= len(p.tokens) {
+			return 0, fmt.Errorf("%w: unbalanced brackets", ErrCovertExample)
+		}
+		if p.tokens[p.pos].kind == closing {
+			p.pos++
+			return count, nil
+		}
+		if err := p.expr(); err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
+
+// form - (head args...) after "("
+func (p *sexprParser) form(open token) error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("%w: unbalanced brackets", ErrCovertExample)
+	}
+	head := p.tokens[p.pos]
+	if head.kind != tokenOperator && head.kind != tokenIdent {
+		return fmt.Errorf("%w: expected an operator or a function after \"(\" at position %d", ErrCovertExample, open.pos)
+	}
+	p.pos++
+
+	// "in" takes a unit or a currency written bare: (in d hours)
+	if head.text == "in" {
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenIdent {
+			if target := p.tokens[p.pos].text; IsUnit(target) || isCurrency(target) {
+				p.list = append(p.list, quoteLiteral(target))
+				p.pos++
+				return p.closeWith(head, 2, 2, open)
+			}
+		}
+		if err := p.expr(); err != nil {
+			return err
+		}
+		return p.closeWith(head, 2, 2, open)
+	}
+
+	// operands are emitted as they are parsed: (+ a b c) → a b + c +
+	count := 0
+	for p.pos < len(p.tokens) && p.tokens[p.pos].kind != tokenRParen {
+		if err := p.expr(); err != nil {
+			return err
+		}
+		count++
+		if head.kind == tokenOperator && count >= 2 && head.text != "^" && head.text != "~" {
+			p.list = append(p.list, head.text)
+		}
+	}
+
+	if head.kind == tokenIdent {
+		if err := p.close(head, count, count, open); err != nil {
+			return err
+		}
+		if err := checkCall(head.text, count); err != nil {
+			return err
+		}
+		p.list = append(p.list, functionToken(head.text, count))
+		return nil
+	}
+
+	switch head.text {
+	case "~":
+		return p.closeWith(head, count, 1, open)
+	case "^":
+		return p.closeWith(head, count, 2, open)
+	case "-":
+		if count == 1 {
+			p.list = append(p.list, "~") // (- x)
+			return p.close(head, count, 1, open)
+		}
+	}
+	return p.close(head, count, 2, open)
+}
+
+// close - ")" of a form with count arguments, at least min of them;
+// exactly min for "^", "~" and "in"
+func (p *sexprParser) close(head token, count, min int, open token) error {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenRParen {
+		if p.pos < len(p.tokens) && (head.text == "^" || head.text == "~" || head.text == "in") {
+			tok := p.tokens[p.pos]
+			return fmt.Errorf("%w: %q takes %d arguments, unexpected %q at position %d", ErrCovertExample, head.text, min, tok.text, tok.pos)
+		}
+		return fmt.Errorf("%w: unbalanced brackets", ErrCovertExample)
+	}
+	p.pos++
+	if count < min || (count > min && (head.text == "^" || head.text == "~")) {
+		return fmt.Errorf("%w: %q at position %d takes %d arguments, got %d", ErrCovertExample, head.text, open.pos, min, count)
+	}
+	return nil
+}
+
+// closeWith - close and the head after the arguments
+func (p *sexprParser) closeWith(head token, count, min int, open token) error {
+	if err := p.close(head, count, min, open); err != nil {
+		return err
+	}
+	p.list = append(p.list, head.text)
+	return nil
+}
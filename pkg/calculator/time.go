@@ -0,0 +1,258 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationUnits - units of durations: postfix "90 days", calls "days(90)"
+// and conversions "d in hours"
+var durationUnits = map[string]time.Duration{
+	"second":  time.Second,
+	"seconds": time.Second,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+}
+
+// dateLayouts - accepted forms of date("...")
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// IsUnit checks if the name is a unit of durations
+func IsUnit(name string) bool {
+	_, ok := durationUnits[name]
+	return ok
+}
+
+func StringValue(text string) Value {
+	return Value{Kind: KindString, Text: text}
+}
+
+func TimeValue(t time.Time) Value {
+	return Value{Kind: KindTime, Time: t.UTC()}
+}
+
+func DurationValue(d time.Duration) Value {
+	return Value{Kind: KindDuration, Duration: d}
+}
+
+// quoteLiteral - string literal as one item of the postfix notation:
+// quoted, spaces are escaped because items are separated by spaces
+func quoteLiteral(text string) string {
+	return strings.ReplaceAll(strconv.Quote(text), " ", `\x20`)
+}
+
+// formatTime - dates without time as 2026-10-16, the rest as RFC 3339
+func formatTime(t time.Time) string {
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatDuration - whole days as "90 days", the rest as 36h0m0s
+func formatDuration(d time.Duration) string {
+	day := durationUnits["day"]
+	switch {
+	case d%day != 0:
+		return d.String()
+	case d == day || d == -day:
+		return strconv.FormatInt(int64(d/day), 10) + " day"
+	default:
+		return strconv.FormatInt(int64(d/day), 10) + " days"
+	}
+}
+
+// parseDate - date("2026-10-16"), date("2026-10-16 10:30"), date("2026-10-16T10:30:00+03:00")
+func parseDate(args []Value) (Value, error) {
+	if args[0].Kind != KindString {
+		return Value{}, fmt.Errorf("%w: date expects a string", ErrInvalidArgument)
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, args[0].Text); err == nil {
+			return TimeValue(t), nil
+		}
+	}
+	return Value{}, fmt.Errorf("%w: bad date %q, expected YYYY-MM-DD", ErrInvalidArgument, args[0].Text)
+}
+
+func now(args []Value) (Value, error) {
+	return TimeValue(time.Now().Truncate(time.Second)), nil
+}
+
+// durationOf - days(90), also 90 days
+func durationOf(unit time.Duration) func(args []Value) (Value, error) {
+	return func(args []Value) (Value, error) {
+		if !args[0].IsNumber() {
+			return Value{}, fmt.Errorf("%w: duration expects a number, got %s", ErrInvalidArgument, kindOf(args[0]))
+		}
+		return durationFromFloat(args[0].Number * float64(unit))
+	}
+}
+
+// durationFromFloat - nanoseconds to a duration, about ±292 years at most
+func durationFromFloat(ns float64) (Value, error) {
+	if math.IsNaN(ns) || math.Abs(ns) >= math.MaxInt64 {
+		return Value{}, fmt.Errorf("%w: duration is out of range", ErrInvalidArgument)
+	}
+	return DurationValue(time.Duration(ns)), nil
+}
+
+func isTemporal(v Value) bool {
+	return v.Kind == KindTime || v.Kind == KindDuration || v.Kind == KindString
+}
+
+// timeOperator - arithmetic of dates and durations:
+// date ± duration, date - date, duration ± duration,
+// duration * number, duration / number, duration / duration,
+// "duration in unit" - the duration as a number of units
+func timeOperator(sign string, a, b Value) (Value, error) {
+	undefined := fmt.Errorf("%w: operator %s is not defined for %s and %s", ErrInvalidArgument, sign, kindOf(a), kindOf(b))
+
+	switch sign {
+	case "in":
+		unit, ok := durationUnits[b.Text]
+		if b.Kind != KindString || !ok {
+			return Value{}, fmt.Errorf("%w: unknown unit %s", ErrInvalidArgument, b)
+		}
+		if a.Kind != KindDuration {
+			return Value{}, undefined
+		}
+		return NumberValue(float64(a.Duration) / float64(unit)), nil
+	case "+":
+		switch {
+		case a.Kind == KindTime && b.Kind == KindDuration:
+			return TimeValue(a.Time.Add(b.Duration)), nil
+		case a.Kind == KindDuration && b.Kind == KindTime:
+			return TimeValue(b.Time.Add(a.Duration)), nil
+		case a.Kind == KindDuration && b.Kind == KindDuration:
+			return DurationValue(a.Duration + b.Duration), nil
+		}
+	case "-":
+		switch {
+		case a.Kind == KindTime && b.Kind == KindDuration:
+			return TimeValue(a.Time.Add(-b.Duration)), nil
+		case a.Kind == KindTime && b.Kind == KindTime:
+			return DurationValue(a.Time.Sub(b.Time)), nil
+		case a.Kind == KindDuration && b.Kind == KindDuration:
+			return DurationValue(a.Duration - b.Duration), nil
+		case a.IsNumber() && a.Number == 0 && b.Kind == KindDuration:
+			return DurationValue(-b.Duration), nil // ~d
+		}
+	case "*":
+		switch {
+		case a.Kind == KindDuration && b.IsNumber():
+			return durationFromFloat(float64(a.Duration) * b.Number)
+		case a.IsNumber() && b.Kind == KindDuration:
+			return durationFromFloat(a.Number * float64(b.Duration))
+		}
+	case "/":
+		switch {
+		case a.Kind == KindDuration && b.IsNumber():
+			if b.Number == 0 {
+				return Value{}, ErrDivisionByZero
+			}
+			return durationFromFloat(float64(a.Duration) / b.Number)
+		case a.Kind == KindDuration && b.Kind == KindDuration:
+			if b.Duration == 0 {
+				return Value{}, ErrDivisionByZero
+			}
+			return NumberValue(float64(a.Duration) / float64(b.Duration)), nil
+		}
+	}
+	return Value{}, undefined
+}
+
+// timeArg - date argument of the business day functions
+func timeArg(name string, v Value) (time.Time, error) {
+	if v.Kind != KindTime {
+		return time.Time{}, fmt.Errorf("%w: %s expects a date, got %s", ErrInvalidArgument, name, kindOf(v))
+	}
+	return v.Time.Truncate(24 * time.Hour), nil
+}
+
+func isWorkday(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// workdays - workdays(from, to), number of Monday..Friday days
+// from "from" inclusive to "to" exclusive, negative if to < from
+func workdays(args []Value) (Value, error) {
+	from, err := timeArg("workdays", args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	to, err := timeArg("workdays", args[1])
+	if err != nil {
+		return Value{}, err
+	}
+	sign := 1.0
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+
+	// whole weeks have 5 workdays, the rest is counted day by day
+	days := int(to.Sub(from) / durationUnits["day"])
+	count := days / 7 * 5
+	for day := from.AddDate(0, 0, days/7*7); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if isWorkday(day) {
+			count++
+		}
+	}
+	return NumberValue(sign * float64(count)), nil
+}
+
+// addWorkdays - addworkdays(date, n), the date n workdays later (earlier for n < 0)
+func addWorkdays(args []Value) (Value, error) {
+	day, err := timeArg("addworkdays", args[0])
+	if err != nil {
+		return Value{}, err
+	}
+	if !args[1].IsNumber() || args[1].Number != float64(int(args[1].Number)) {
+		return Value{}, fmt.Errorf("%w: addworkdays expects a whole number of days", ErrInvalidArgument)
+	}
+	n := int(args[1].Number)
+	step := 1
+	if n < 0 {
+		n, step = -n, -1
+	}
+	if n > maxWorkdays {
+		return Value{}, fmt.Errorf("%w: addworkdays is limited to %d days", ErrInvalidArgument, maxWorkdays)
+	}
+
+	if n == 0 {
+		return TimeValue(day), nil
+	}
+
+	// a weekend counts from the closest workday behind it:
+	// Saturday + 1 is Monday like Friday + 1
+	for !isWorkday(day) {
+		day = day.AddDate(0, 0, -step)
+	}
+
+	// whole weeks first, then day by day
+	day = day.AddDate(0, 0, step*n/5*7)
+	for n %= 5; n > 0; {
+		day = day.AddDate(0, 0, step)
+		if isWorkday(day) {
+			n--
+		}
+	}
+	return TimeValue(day), nil
+}
+
+// maxWorkdays - about 1000 years, keeps dates in the range of time.Duration arithmetic
+const maxWorkdays = 260000
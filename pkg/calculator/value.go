@@ -0,0 +1,274 @@
+package calculator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind - type of the value stored in a variable
+type Kind string
+
+const (
+	KindNumber   Kind = "number"
+	KindList     Kind = "list"
+	KindMatrix   Kind = "matrix"
+	KindInt      Kind = "int" // integer that doesn't fit float64 exactly
+	KindString   Kind = "string"
+	KindTime     Kind = "time"
+	KindDuration Kind = "duration"
+	KindMoney    Kind = "money" // Number in Currency
+)
+
+// Value - result of a step. Most steps produce numbers,
+// aggregate functions work with lists, linear algebra with matrices.
+type Value struct {
+	Kind     Kind
+	Number   float64
+	List     []Value
+	Matrix   [][]float64 // rows of the same length
+	Int      *big.Int
+	Text     string
+	Time     time.Time // always UTC
+	Duration time.Duration
+	Currency string
+}
+
+func NumberValue(number float64) Value {
+	return Value{Kind: KindNumber, Number: number}
+}
+
+func ListValue(items []Value) Value {
+	return Value{Kind: KindList, List: items}
+}
+
+// NumbersValue - list of plain numbers
+func NumbersValue(numbers []float64) Value {
+	items := make([]Value, len(numbers))
+	for i, number := range numbers {
+		items[i] = NumberValue(number)
+	}
+	return ListValue(items)
+}
+
+func (v Value) IsNumber() bool {
+	return v.Kind == KindNumber || v.Kind == ""
+}
+
+// String - human readable form: 3.5, [1, 2, 3.5] or [[1, 2], [3, 4]]
+func (v Value) String() string {
+	switch v.Kind {
+	case KindInt:
+		return v.Int.String()
+	case KindString:
+		return v.Text
+	case KindTime:
+		return formatTime(v.Time)
+	case KindDuration:
+		return formatDuration(v.Duration)
+	case KindMoney:
+		return formatMoney(v.Number, v.Currency)
+	case KindMatrix:
+		rows := make([]string, len(v.Matrix))
+		for i, row := range v.Matrix {
+			rows[i] = NumbersValue(row).String()
+		}
+		return "[" + strings.Join(rows, ", ") + "]"
+	case KindList:
+		items := make([]string, len(v.List))
+		for i, item := range v.List {
+			items[i] = item.String()
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		// integers without exponent: 688423210, not 6.8842321e+08
+		if v.Number == math.Trunc(v.Number) && math.Abs(v.Number) <= maxExactFloat {
+			return strconv.FormatFloat(v.Number, 'f', -1, 64)
+		}
+		return strconv.FormatFloat(v.Number, 'g', -1, 64)
+	}
+}
+
+// valueJSON - wire form of non-number values
+type valueJSON struct {
+	Kind     Kind          `json:"kind"`
+	List     []Value       `json:"list,omitempty"`
+	Matrix   [][]float64   `json:"matrix,omitempty"`
+	Int      *big.Int      `json:"int,omitempty"`
+	Text     string        `json:"text,omitempty"`
+	Time     *time.Time    `json:"time,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"` // nanoseconds
+	Amount   float64       `json:"amount,omitempty"`
+	Currency string        `json:"currency,omitempty"`
+}
+
+// MarshalJSON stores numbers as plain JSON numbers (the format used
+// before lists appeared), ±Inf and NaN of the IEEE mode as strings "+Inf",
+// "-Inf", "NaN", other kinds as {"kind": ..., ...}
+func (v Value) MarshalJSON() ([]byte, error) {
+	if v.IsNumber() {
+		if math.IsInf(v.Number, 0) || math.IsNaN(v.Number) {
+			return json.Marshal(strconv.FormatFloat(v.Number, 'g', -1, 64))
+		}
+		return json.Marshal(v.Number)
+	}
+	raw := valueJSON{Kind: v.Kind, List: v.List, Matrix: v.Matrix, Int: v.Int, Text: v.Text, Duration: v.Duration}
+	if v.Kind == KindMoney {
+		raw.Amount, raw.Currency = v.Number, v.Currency
+	}
+	if v.Kind == KindTime {
+		raw.Time = &v.Time
+	}
+	return json.Marshal(raw)
+}
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("decode number value: %w", err)
+		}
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil || !(math.IsInf(number, 0) || math.IsNaN(number)) {
+			return fmt.Errorf("decode number value: bad number %q", text)
+		}
+		*v = NumberValue(number)
+		return nil
+	}
+	if len(data) > 0 && data[0] != '{' {
+		var number float64
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("decode number value: %w", err)
+		}
+		*v = NumberValue(number)
+		return nil
+	}
+
+	var raw valueJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("decode value: %w", err)
+	}
+	switch raw.Kind {
+	case KindList:
+		if raw.List == nil {
+			raw.List = []Value{}
+		}
+		*v = ListValue(raw.List)
+	case KindMatrix:
+		if len(raw.Matrix) == 0 {
+			return fmt.Errorf("decode value: empty matrix")
+		}
+		*v = MatrixValue(raw.Matrix)
+	case KindInt:
+		if raw.Int == nil {
+			return fmt.Errorf("decode value: empty int")
+		}
+		*v = IntValue(raw.Int)
+	case KindString:
+		*v = StringValue(raw.Text)
+	case KindTime:
+		if raw.Time == nil {
+			return fmt.Errorf("decode value: empty time")
+		}
+		*v = TimeValue(*raw.Time)
+	case KindDuration:
+		*v = DurationValue(raw.Duration)
+	case KindMoney:
+		if !isCurrency(raw.Currency) {
+			return fmt.Errorf("decode value: bad currency %q", raw.Currency)
+		}
+		*v = MoneyValue(raw.Amount, raw.Currency)
+	default:
+		return fmt.Errorf("decode value: unknown kind %q", raw.Kind)
+	}
+	return nil
+}
+
+// finite - ErrOverflow for infinite numbers, ErrDomain for NaN. In the IEEE
+// mode numbers and lists keep them; matrices and money never do, their JSON
+// has no place for them.
+func finite(v Value, ieee bool) error {
+	check := func(number float64) error {
+		switch {
+		case math.IsNaN(number):
+			return ErrDomain
+		case math.IsInf(number, 0):
+			return ErrOverflow
+		}
+		return nil
+	}
+
+	switch {
+	case v.IsNumber():
+		if !ieee {
+			return check(v.Number)
+		}
+	case v.Kind == KindMoney:
+		return check(v.Number)
+	case v.Kind == KindList:
+		for _, item := range v.List {
+			if err := finite(item, ieee); err != nil {
+				return err
+			}
+		}
+	case v.Kind == KindMatrix:
+		for _, row := range v.Matrix {
+			for _, number := range row {
+				if err := check(number); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParseLiteral - value of an operand written directly in the task
+func ParseLiteral(ref string) (Value, bool) {
+	if strings.HasPrefix(ref, `"`) {
+		text, err := strconv.Unquote(ref)
+		if err != nil {
+			return Value{}, false
+		}
+		return StringValue(text), true
+	}
+	// long integers keep all digits
+	if n, ok := new(big.Int).SetString(ref, 10); ok {
+		return intResult(n), true
+	}
+	number, err := strconv.ParseFloat(ref, 64)
+	if err != nil {
+		return Value{}, false
+	}
+	return NumberValue(number), true
+}
+
+// numbers - all numbers of the arguments, lists and matrices are flattened
+func numbers(args []Value) ([]float64, error) {
+	result := make([]float64, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case arg.IsNumber():
+			result = append(result, arg.Number)
+		case arg.Kind == KindList:
+			nested, err := numbers(arg.List)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, nested...)
+		case arg.Kind == KindMatrix:
+			result = append(result, flatten(arg.Matrix)...)
+		case arg.Kind == KindInt:
+			result = append(result, toFloat(arg))
+		default:
+			return nil, fmt.Errorf("%w: expected numbers, got %s", ErrInvalidArgument, arg.Kind)
+		}
+	}
+	return result, nil
+}
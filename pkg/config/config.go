@@ -6,9 +6,15 @@ import (
 	"github.com/caarlos0/env/v8"
 	"github.com/joho/godotenv"
 	"github.com/tainj/distributed_calculator2/internal/auth"
+	"github.com/tainj/distributed_calculator2/internal/latency"
+	"github.com/tainj/distributed_calculator2/internal/operations"
+	"github.com/tainj/distributed_calculator2/internal/orchestrator"
+	"github.com/tainj/distributed_calculator2/internal/rates"
+	"github.com/tainj/distributed_calculator2/pkg/calculator"
 	"github.com/tainj/distributed_calculator2/pkg/db/cache"
 	"github.com/tainj/distributed_calculator2/pkg/db/postgres"
 	"github.com/tainj/distributed_calculator2/pkg/messaging/kafka"
+	"github.com/tainj/distributed_calculator2/pkg/retry"
 )
 
 type GRPCServer struct {
@@ -22,6 +28,21 @@ type Config struct {
 	Grpc     GRPCServer
 	Kafka    kafka.Config
 	JWT      auth.Config
+	Rates    rates.Config
+	Limits   Limits
+	Wasm     operations.Config
+	Retry    retry.Config
+	Latency  latency.Config
+	Deadline orchestrator.DeadlineConfig
+	Reaper   orchestrator.ReaperConfig
+}
+
+// Limits - expression limits by role: LIMITS_MAX_LENGTH, LIMITS_MAX_TASKS, ...
+// for users, LIMITS_ADMIN_MAX_LENGTH, ... for admins. Admins inherit what is
+// not set for them.
+type Limits struct {
+	User  calculator.Limits
+	Admin calculator.Limits
 }
 
 func LoadConfig() (*Config, error) {
@@ -52,5 +73,38 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := env.Parse(&cfg.Rates); err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg.Wasm); err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg.Retry); err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg.Deadline); err != nil {
+		return nil, err
+	}
+
+	if err := env.Parse(&cfg.Reaper); err != nil {
+		return nil, err
+	}
+
+	cfg.Limits.User = calculator.DefaultLimits
+	if err := env.ParseWithOptions(&cfg.Limits.User, env.Options{Prefix: "LIMITS_"}); err != nil {
+		return nil, err
+	}
+	cfg.Limits.Admin = cfg.Limits.User
+	if err := env.ParseWithOptions(&cfg.Limits.Admin, env.Options{Prefix: "LIMITS_ADMIN_"}); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/tainj/distributed_calculator2/pkg/logger"
@@ -55,3 +56,22 @@ func (s *CACHE) SetByKey(ctx context.Context, key string, value interface{}) err
 	}
 	return nil
 }
+
+// SetByKeyWithTTL - SetByKey for a value that expires after ttl
+func (s *CACHE) SetByKeyWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	if err := s.Client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set key in Redis: %w", err)
+	}
+	return nil
+}
+
+func (s *CACHE) DeleteByKey(ctx context.Context, key string) error {
+	if err := s.Client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete key from Redis: %w", err)
+	}
+	return nil
+}
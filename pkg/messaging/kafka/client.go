@@ -16,42 +16,88 @@ type TaskQueue interface {
 	Commit(message kafka.Message) error
 }
 
-type KafkaQueue struct {
+// Producer - a queue that is only written to
+type Producer interface {
+	SendTask(task interface{}) error
+}
+
+// KafkaWriter - writes to a topic without joining its consumer group,
+// for services that never read the topic
+type KafkaWriter struct {
 	writer *kafka.Writer
-	reader *kafka.Reader
 	logger logger.Logger
 }
 
+type KafkaQueue struct {
+	*KafkaWriter
+	reader *kafka.Reader
+}
+
 type Config struct {
 	BootstrapServers  string `env:"KAFKA_BOOTSTRAP_SERVERS" env-default:"localhost:9092,localhost:9094,localhost:9096"`
 	TopicCalculations string `env:"KAFKA_TOPIC_CALCULATIONS" env-default:"calculator_tasks"`
 	TopicResults      string `env:"KAFKA_TOPIC_RESULTS" env-default:"calculator_results"`
+	TopicDeadLetters  string `env:"KAFKA_TOPIC_DEAD_LETTERS" env-default:"calculator_dead_letters"`
 }
 
 func NewKafkaQueue(cfg Config, l logger.Logger) (*KafkaQueue, error) {
-	brokers := strings.Split(cfg.BootstrapServers, ",")
-	l.Info(context.Background(), "initializing KafkaQueue", "brokers", brokers, "topic", cfg.TopicCalculations)
-	if len(brokers) == 0 {
-		return nil, fmt.Errorf("no Kafka brokers specified")
+	return newQueue(cfg.BootstrapServers, cfg.TopicCalculations, "calculator_group", l)
+}
+
+// NewResultQueue - completion events of tasks, the server reads them
+func NewResultQueue(cfg Config, l logger.Logger) (*KafkaQueue, error) {
+	return newQueue(cfg.BootstrapServers, cfg.TopicResults, "calculator_results_group", l)
+}
+
+// NewResultWriter - completion events of tasks, workers send them
+func NewResultWriter(cfg Config, l logger.Logger) (*KafkaWriter, error) {
+	return newWriter(cfg.BootstrapServers, cfg.TopicResults, l)
+}
+
+// NewDeadLetterQueue - tasks workers gave up on, the server saves them
+func NewDeadLetterQueue(cfg Config, l logger.Logger) (*KafkaQueue, error) {
+	return newQueue(cfg.BootstrapServers, cfg.TopicDeadLetters, "calculator_dead_letters_group", l)
+}
+
+// NewDeadLetterWriter - tasks workers gave up on, workers send them
+func NewDeadLetterWriter(cfg Config, l logger.Logger) (*KafkaWriter, error) {
+	return newWriter(cfg.BootstrapServers, cfg.TopicDeadLetters, l)
+}
+
+func newQueue(servers, topic, group string, l logger.Logger) (*KafkaQueue, error) {
+	writer, err := newWriter(servers, topic, l)
+	if err != nil {
+		return nil, err
 	}
 	return &KafkaQueue{
-		writer: &kafka.Writer{
-			Addr:  kafka.TCP(brokers...),
-			Topic: cfg.TopicCalculations,
-		},
+		KafkaWriter: writer,
 		reader: kafka.NewReader(kafka.ReaderConfig{
-			Brokers:        brokers,
-			Topic:          cfg.TopicCalculations,
-			GroupID:        "calculator_group",
+			Brokers:        strings.Split(servers, ","),
+			Topic:          topic,
+			GroupID:        group,
 			MinBytes:       1,
 			MaxBytes:       1e6,
 			CommitInterval: 0, // commit manually
 		}),
+	}, nil
+}
+
+func newWriter(servers, topic string, l logger.Logger) (*KafkaWriter, error) {
+	brokers := strings.Split(servers, ",")
+	l.Info(context.Background(), "initializing KafkaQueue", "brokers", brokers, "topic", topic)
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("no Kafka brokers specified")
+	}
+	return &KafkaWriter{
+		writer: &kafka.Writer{
+			Addr:  kafka.TCP(brokers...),
+			Topic: topic,
+		},
 		logger: l,
 	}, nil
 }
 
-func (k *KafkaQueue) SendTask(task interface{}) error {
+func (k *KafkaWriter) SendTask(task interface{}) error {
 	jsonData, err := json.Marshal(task)
 	if err != nil {
 		k.logger.Error(context.Background(), "failed ot marshal task", "error", err)
@@ -69,10 +115,11 @@ func (k *KafkaQueue) SendTask(task interface{}) error {
 	return nil
 }
 
-// ReadTask returns message data and message itself for commit
+// ReadTask returns message data and message itself for commit.
+// The offset is not committed until Commit is called.
 func (k *KafkaQueue) ReadTask() ([]byte, kafka.Message, error) {
 	k.logger.Debug(context.Background(), "read task from Kafka")
-	message, err := k.reader.ReadMessage(context.Background())
+	message, err := k.reader.FetchMessage(context.Background())
 	if err != nil {
 		k.logger.Error(context.Background(), "failed to read message from Kafka", "error", err)
 		return nil, kafka.Message{}, err
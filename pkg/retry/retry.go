@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Config - how a failed task is retried: RETRY_ATTEMPTS attempts in all,
+// the pause after the first one is RETRY_BACKOFF and doubles after each
+// next one up to RETRY_MAX_BACKOFF
+type Config struct {
+	Attempts   int           `env:"RETRY_ATTEMPTS" envDefault:"5"`
+	Backoff    time.Duration `env:"RETRY_BACKOFF" envDefault:"200ms"`
+	MaxBackoff time.Duration `env:"RETRY_MAX_BACKOFF" envDefault:"10s"`
+}
+
+// Delay - the pause after the failed attempt, attempts are counted from 1
+func (c Config) Delay(attempt int) time.Duration {
+	delay := c.Backoff
+	for i := 1; i < attempt && delay < c.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, c.MaxBackoff)
+}
+
+// Do calls fn until it succeeds or the attempts run out and returns
+// the last error with the number of attempts made. It stops early when
+// ctx is done.
+func Do(ctx context.Context, cfg Config, fn func() error) (int, error) {
+	attempts := max(cfg.Attempts, 1)
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt == attempts {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(cfg.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	cfg := Config{Attempts: 10, Backoff: 200 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		name     string
+		attempt  int
+		expected time.Duration
+	}{
+		{"first attempt", 1, 200 * time.Millisecond},
+		{"doubles", 2, 400 * time.Millisecond},
+		{"doubles again", 3, 800 * time.Millisecond},
+		{"capped", 4, time.Second},
+		{"stays capped", 50, time.Second},
+		{"attempt zero", 0, 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.Delay(tt.attempt); got != tt.expected {
+				t.Errorf("Delay(%d) = %v, expected %v", tt.attempt, got, tt.expected)
+			}
+		})
+	}
+
+	capped := Config{Backoff: 2 * time.Second, MaxBackoff: time.Second}
+	if got := capped.Delay(1); got != time.Second {
+		t.Errorf("Delay(1) with backoff above the cap = %v, expected %v", got, time.Second)
+	}
+}
+
+func TestDo(t *testing.T) {
+	failure := errors.New("redis is down")
+
+	tests := []struct {
+		name     string
+		attempts int
+		failures int // calls that fail before the first success
+		expected int
+		err      error
+	}{
+		{"succeeds at once", 5, 0, 1, nil},
+		{"succeeds on the third attempt", 5, 2, 3, nil},
+		{"succeeds on the last attempt", 3, 2, 3, nil},
+		{"attempts run out", 3, 10, 3, failure},
+		{"no attempts configured, one is made", 0, 10, 1, failure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Attempts: tt.attempts, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}
+			calls := 0
+			attempts, err := Do(context.Background(), cfg, func() error {
+				calls++
+				if calls <= tt.failures {
+					return failure
+				}
+				return nil
+			})
+			if attempts != tt.expected || calls != tt.expected {
+				t.Errorf("Do() = %d attempts, %d calls, expected %d", attempts, calls, tt.expected)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Do() error = %v, expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDoStopsOnCancel(t *testing.T) {
+	failure := errors.New("redis is down")
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := Config{Attempts: 5, Backoff: time.Hour, MaxBackoff: time.Hour}
+
+	calls := 0
+	attempts, err := Do(ctx, cfg, func() error {
+		calls++
+		cancel()
+		return failure
+	})
+	if attempts != 1 || calls != 1 {
+		t.Errorf("Do() = %d attempts, %d calls, expected 1", attempts, calls)
+	}
+	if !errors.Is(err, failure) {
+		t.Errorf("Do() error = %v, expected %v", err, failure)
+	}
+}